@@ -6,6 +6,7 @@ import (
 	"syscall"
 
 	"dmud/internal/components"
+	"dmud/internal/game"
 	"dmud/internal/net"
 	"dmud/internal/util"
 
@@ -29,22 +30,35 @@ func main() {
 		Logger()
 	log.Logger = logger
 
-	// Load NPC templates from JSON
-	if err := components.LoadNPCTemplates("./resources/npcs.json"); err != nil {
-		log.Fatal().Err(err).Msg("Failed to load NPC templates")
+	// Load items, NPCs, races, classes, abilities and recipes from JSON
+	if err := components.LoadResources("./resources"); err != nil {
+		log.Fatal().Err(err).Msg("Failed to load resources")
 	}
 
-	// Initialize quest dialogues
-	components.InitializeQuests()
-
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 
 	}
-	server := net.NewServer(&net.ServerConfig{
+	config := &net.ServerConfig{
 		WSHost: "127.0.0.1", WSPort: port,
-	})
+	}
+
+	// Raw TCP is opt-in; the load tester and plain telnet clients use it
+	if tcpPort := os.Getenv("TCP_PORT"); tcpPort != "" {
+		config.TCPHost = "127.0.0.1"
+		config.TCPPort = tcpPort
+	}
+
+	// Session recording is opt-in; see cmd/replay
+	if recordDir := os.Getenv("RECORD_DIR"); recordDir != "" {
+		config.Game = &game.Config{
+			ResourceDir: "./resources",
+			RecordDir:   recordDir,
+		}
+	}
+
+	server := net.NewServer(config)
 
 	go server.Run()
 
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var exitsPattern = regexp.MustCompile(`Exits: \[([^\]]*)\]`)
+
+// botPassword is what bots register their names with, and log back in with
+// when a name is left over from an earlier run.
+const botPassword = "loadtest"
+
+// creationPrompts are what the server asks at each step of character
+// creation, in order, ending with the first look at the world.
+var creationPrompts = []string{"By what name", "password", "CHOOSE YOUR RACE", "CHOOSE YOUR CLASS", "Exits:"}
+
+var chatLines = []string{
+	"hello there",
+	"anyone seen the merchant?",
+	"these woods are creepy",
+	"need help with goblins",
+	"what time is it?",
+	"nice weather today",
+}
+
+type bot struct {
+	id      int
+	conn    conn
+	mix     *mix
+	stats   *stats
+	think   time.Duration
+	timeout time.Duration
+	rng     *rand.Rand
+	exits   []string
+}
+
+func newBot(id int, c conn, m *mix, s *stats, think, timeout time.Duration) *bot {
+	return &bot{
+		id:      id,
+		conn:    c,
+		mix:     m,
+		stats:   s,
+		think:   think,
+		timeout: timeout,
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano() + int64(id))),
+	}
+}
+
+func (b *bot) run(ctx context.Context) {
+	defer b.conn.Close()
+
+	// The server greets new connections with a banner and asks for a name
+	if !b.waitFor(ctx, func(frame string) bool { return strings.Contains(frame, "By what name") }, b.timeout) {
+		b.stats.recordError("connect", "no greeting")
+		return
+	}
+
+	// Character creation: name, password, race, then class
+	answers := []string{fmt.Sprintf("bot%03d", b.id), botPassword, "human", "warrior"}
+	for step, answer := range answers {
+		if !b.create(ctx, step, answer) {
+			return
+		}
+	}
+
+	for ctx.Err() == nil {
+		action := b.mix.pick(b.rng)
+		b.command(ctx, action, b.commandFor(action))
+
+		// Jitter think time by +/-50% so bots don't act in lockstep
+		pause := b.think/2 + time.Duration(b.rng.Int63n(int64(b.think)+1))
+		if !b.idle(ctx, pause) {
+			return
+		}
+	}
+}
+
+func (b *bot) commandFor(action string) string {
+	switch action {
+	case "wander":
+		if len(b.exits) == 0 {
+			return "look"
+		}
+		return b.exits[b.rng.Intn(len(b.exits))]
+	case "fight":
+		return "kill all"
+	case "chat":
+		return "say " + chatLines[b.rng.Intn(len(chatLines))]
+	default:
+		return "look"
+	}
+}
+
+// create answers the prompt at step of character creation and waits for the
+// next one. Any other creation prompt counts as an error, so a bot that has
+// fallen out of step with the server never carries on as if it had joined.
+func (b *bot) create(ctx context.Context, step int, answer string) bool {
+	start := time.Now()
+	if err := b.conn.Send(answer); err != nil {
+		b.stats.recordError("create", "send failed")
+		return false
+	}
+
+	want := creationPrompts[step+1]
+	unexpected := ""
+	matched := b.waitFor(ctx, func(frame string) bool {
+		if strings.Contains(frame, want) {
+			return true
+		}
+		for _, prompt := range creationPrompts {
+			if strings.Contains(frame, prompt) {
+				unexpected = prompt
+				return true
+			}
+		}
+		return false
+	}, b.timeout)
+
+	switch {
+	case !matched:
+		if ctx.Err() == nil {
+			b.stats.recordError("create", "timeout")
+		}
+		return false
+	case unexpected != "":
+		b.stats.recordError("create", fmt.Sprintf("asked %q instead of %q", unexpected, want))
+		return false
+	}
+	b.stats.recordLatency("create", time.Since(start))
+	return true
+}
+
+// command sends line and records how long the server takes to reply.
+func (b *bot) command(ctx context.Context, action, line string) {
+	start := time.Now()
+	if err := b.conn.Send(line); err != nil {
+		b.stats.recordError(action, "send failed")
+		return
+	}
+
+	if b.waitFor(ctx, b.conn.IsReply, b.timeout) {
+		b.stats.recordLatency(action, time.Since(start))
+	} else if ctx.Err() == nil {
+		b.stats.recordError(action, "timeout")
+	}
+}
+
+// waitFor drains frames until one satisfies match, the timeout passes, or the
+// connection closes.
+func (b *bot) waitFor(ctx context.Context, match func(string) bool, timeout time.Duration) bool {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case frame, ok := <-b.conn.Frames():
+			if !ok {
+				return false
+			}
+			b.observe(frame)
+			if match(frame) {
+				return true
+			}
+		case <-deadline.C:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// idle keeps draining frames for d so the server never blocks writing to us.
+func (b *bot) idle(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	for {
+		select {
+		case frame, ok := <-b.conn.Frames():
+			if !ok {
+				b.stats.recordError("connection", "closed by server")
+				return false
+			}
+			b.observe(frame)
+		case <-timer.C:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (b *bot) observe(frame string) {
+	m := exitsPattern.FindStringSubmatch(frame)
+	if m == nil {
+		return
+	}
+
+	b.exits = b.exits[:0]
+	for _, exit := range strings.Split(m[1], ",") {
+		if exit = strings.TrimSpace(exit); exit != "" {
+			b.exits = append(b.exits, exit)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// conn is a bot's connection to the server. Frames delivers everything the
+// server sends, and IsReply reports whether a frame marks the end of the
+// server's response to the last command.
+type conn interface {
+	Send(line string) error
+	Frames() <-chan string
+	IsReply(frame string) bool
+	Close() error
+}
+
+func dial(proto, addr string) (conn, error) {
+	switch proto {
+	case "tcp":
+		return dialTCP(addr)
+	case "ws":
+		return dialWS(addr)
+	default:
+		return nil, fmt.Errorf("unknown protocol %q", proto)
+	}
+}
+
+type tcpConn struct {
+	conn   net.Conn
+	frames chan string
+}
+
+func dialTCP(addr string) (*tcpConn, error) {
+	c, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	tc := &tcpConn{conn: c, frames: make(chan string, 256)}
+	go tc.read()
+	return tc, nil
+}
+
+func (c *tcpConn) read() {
+	defer close(c.frames)
+
+	r := bufio.NewReader(c.conn)
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			c.frames <- string(buf[:n])
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (c *tcpConn) Send(line string) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	_, err := c.conn.Write([]byte(line + "\n"))
+	return err
+}
+
+func (c *tcpConn) Frames() <-chan string { return c.frames }
+
+// The TCP client gets a prompt after every processed command, which makes it
+// an end-of-response marker. Reads can coalesce the prompt with output that
+// follows it, so it isn't necessarily at the end of the frame.
+func (c *tcpConn) IsReply(frame string) bool {
+	return strings.Contains(frame, "> ")
+}
+
+func (c *tcpConn) Close() error { return c.conn.Close() }
+
+type wsConn struct {
+	conn   *websocket.Conn
+	frames chan string
+}
+
+func dialWS(addr string) (*wsConn, error) {
+	dialer := websocket.Dialer{HandshakeTimeout: 5 * time.Second}
+	c, _, err := dialer.Dial("ws://"+addr+"/ws", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	wc := &wsConn{conn: c, frames: make(chan string, 256)}
+	go wc.read()
+	return wc, nil
+}
+
+func (c *wsConn) read() {
+	defer close(c.frames)
+
+	for {
+		_, p, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		c.frames <- string(p)
+	}
+}
+
+func (c *wsConn) Send(line string) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	return c.conn.WriteMessage(websocket.TextMessage, []byte(line))
+}
+
+func (c *wsConn) Frames() <-chan string { return c.frames }
+
+// WebSocket clients get no prompt, so the first non-state frame after a
+// command stands in for the reply. Unsolicited frames such as combat
+// messages can make this read slightly optimistic.
+func (c *wsConn) IsReply(frame string) bool {
+	return !strings.HasPrefix(frame, "STATE|")
+}
+
+func (c *wsConn) Close() error { return c.conn.Close() }
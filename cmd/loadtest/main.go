@@ -0,0 +1,136 @@
+// Command loadtest points a swarm of scripted bots at a running dmud server
+// and reports command latency percentiles, errors, and whether the game
+// loop's ticks slipped under the load.
+//
+//	TCP_PORT=3333 make run
+//	go run ./cmd/loadtest -proto tcp -addr 127.0.0.1:3333 -bots 200 -duration 2m
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+func main() {
+	proto := flag.String("proto", "ws", "protocol to connect with: tcp or ws")
+	addr := flag.String("addr", "127.0.0.1:8080", "server address (host:port)")
+	bots := flag.Int("bots", 50, "number of concurrent bots")
+	duration := flag.Duration("duration", time.Minute, "how long to run")
+	ramp := flag.Duration("ramp", 10*time.Second, "time over which bots connect")
+	think := flag.Duration("think", time.Second, "average pause between bot commands")
+	timeout := flag.Duration("timeout", 5*time.Second, "how long to wait for a reply before counting an error")
+	mixSpec := flag.String("mix", "wander=4,fight=2,chat=3,look=1", "weighted bot behaviour mix")
+	flag.Parse()
+
+	m, err := parseMix(*mixSpec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	before := loopHealth(*proto, *addr, *timeout)
+
+	fmt.Printf("Starting %d %s bots against %s for %s (mix %s)\n", *bots, *proto, *addr, *duration, *mixSpec)
+
+	s := newStats()
+	var wg sync.WaitGroup
+	var stagger time.Duration
+	if *bots > 0 {
+		stagger = *ramp / time.Duration(*bots)
+	}
+
+	for i := 0; i < *bots && ctx.Err() == nil; i++ {
+		c, err := dial(*proto, *addr)
+		if err != nil {
+			s.recordError("connect", err.Error())
+		} else {
+			wg.Add(1)
+			go func(id int, c conn) {
+				defer wg.Done()
+				newBot(id, c, m, s, *think, *timeout).run(ctx)
+			}(i, c)
+		}
+
+		select {
+		case <-time.After(stagger):
+		case <-ctx.Done():
+		}
+	}
+
+	wg.Wait()
+
+	fmt.Println()
+	fmt.Print(s.render())
+
+	after := loopHealth(*proto, *addr, *timeout)
+	fmt.Printf("\nServer loop before: %s\nServer loop after:  %s\n", before, after)
+}
+
+// loopHealth asks the server for its uptime report and returns the loop tick
+// line, which shows how many world ticks slipped.
+func loopHealth(proto, addr string, timeout time.Duration) string {
+	c, err := dial(proto, addr)
+	if err != nil {
+		return "unavailable (" + err.Error() + ")"
+	}
+	defer c.Close()
+
+	// Like the bots, walk through character creation before asking; a
+	// name that turns out to be taken is asked for again
+	deadline := time.After(timeout)
+	names := 0
+	sent := false
+	for {
+		select {
+		case frame, ok := <-c.Frames():
+			if !ok {
+				return "unavailable (connection closed)"
+			}
+			var reply string
+			switch {
+			case sent:
+			case strings.Contains(frame, "By what name"):
+				names++
+				reply = fmt.Sprintf("health%d", (time.Now().UnixNano()/1000+int64(names))%1000000)
+			case strings.Contains(frame, "password"):
+				reply = botPassword
+			case strings.Contains(frame, "CHOOSE YOUR RACE"):
+				reply = "human"
+			case strings.Contains(frame, "CHOOSE YOUR CLASS"):
+				reply = "warrior"
+			case strings.Contains(frame, "Exits:"):
+				reply = "uptime"
+				sent = true
+			}
+			if reply != "" {
+				if err := c.Send(reply); err != nil {
+					return "unavailable (" + err.Error() + ")"
+				}
+			}
+			for _, line := range strings.Split(frame, "\n") {
+				if strings.HasPrefix(line, "Loop ticks:") {
+					return strings.TrimSpace(strings.TrimPrefix(line, "Loop ticks:"))
+				}
+			}
+		case <-deadline:
+			return "unavailable (timed out)"
+		}
+	}
+}
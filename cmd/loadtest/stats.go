@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+)
+
+// mix is a weighted set of bot actions, parsed from "wander=4,fight=2".
+type mix struct {
+	actions []string
+	weights []int
+	total   int
+}
+
+var knownActions = map[string]bool{
+	"wander": true,
+	"fight":  true,
+	"chat":   true,
+	"look":   true,
+}
+
+func parseMix(spec string) (*mix, error) {
+	m := &mix{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, weightStr, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("mix entry %q must look like action=weight", part)
+		}
+		if !knownActions[name] {
+			return nil, fmt.Errorf("unknown action %q", name)
+		}
+		weight, err := strconv.Atoi(weightStr)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight for %s: %q", name, weightStr)
+		}
+
+		m.actions = append(m.actions, name)
+		m.weights = append(m.weights, weight)
+		m.total += weight
+	}
+
+	if m.total == 0 {
+		return nil, fmt.Errorf("mix %q has no weighted actions", spec)
+	}
+	return m, nil
+}
+
+func (m *mix) pick(r *rand.Rand) string {
+	n := r.Intn(m.total)
+	for i, w := range m.weights {
+		if n < w {
+			return m.actions[i]
+		}
+		n -= w
+	}
+	return m.actions[len(m.actions)-1]
+}
+
+type stats struct {
+	sync.Mutex
+
+	latencies map[string][]time.Duration
+	errors    map[string]map[string]int
+}
+
+func newStats() *stats {
+	return &stats{
+		latencies: make(map[string][]time.Duration),
+		errors:    make(map[string]map[string]int),
+	}
+}
+
+func (s *stats) recordLatency(action string, d time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+	s.latencies[action] = append(s.latencies[action], d)
+}
+
+func (s *stats) recordError(action, reason string) {
+	s.Lock()
+	defer s.Unlock()
+	if s.errors[action] == nil {
+		s.errors[action] = make(map[string]int)
+	}
+	s.errors[action][reason]++
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted)-1) * p)
+	return sorted[idx]
+}
+
+func (s *stats) render() string {
+	s.Lock()
+	defer s.Unlock()
+
+	var b strings.Builder
+
+	tw := table.NewWriter()
+	tw.SetStyle(table.StyleLight)
+	tw.AppendHeader(table.Row{"Action", "Count", "p50", "p90", "p95", "p99", "Max"})
+
+	actions := make([]string, 0, len(s.latencies))
+	for action := range s.latencies {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	var all []time.Duration
+	for _, action := range actions {
+		samples := append([]time.Duration(nil), s.latencies[action]...)
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		all = append(all, samples...)
+		tw.AppendRow(latencyRow(action, samples))
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	tw.AppendRow(latencyRow("all", all))
+	b.WriteString(tw.Render())
+	b.WriteString("\n")
+
+	if len(s.errors) == 0 {
+		b.WriteString("No errors.\n")
+		return b.String()
+	}
+
+	et := table.NewWriter()
+	et.SetStyle(table.StyleLight)
+	et.AppendHeader(table.Row{"Action", "Error", "Count"})
+	for action, reasons := range s.errors {
+		for reason, count := range reasons {
+			et.AppendRow(table.Row{action, reason, count})
+		}
+	}
+	et.SortBy([]table.SortBy{{Number: 1}, {Number: 2}})
+	b.WriteString(et.Render())
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+func latencyRow(label string, sorted []time.Duration) table.Row {
+	return table.Row{
+		label,
+		len(sorted),
+		percentile(sorted, 0.50).Round(time.Microsecond),
+		percentile(sorted, 0.90).Round(time.Microsecond),
+		percentile(sorted, 0.95).Round(time.Microsecond),
+		percentile(sorted, 0.99).Round(time.Microsecond),
+		percentile(sorted, 1.0).Round(time.Microsecond),
+	}
+}
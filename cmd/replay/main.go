@@ -0,0 +1,213 @@
+// Command replay plays back a recorded session in the terminal, or re-feeds
+// its input commands into a fresh world seeded like the original.
+//
+// A re-fed world rolls from the session's seed and only moves on to each
+// recorded timestamp, so re-feeding a session gives the same output every
+// time. It matches the original exactly when the session was the first and
+// only one on a freshly started server; otherwise the live world had already
+// rolled dice, spawned NPCs and seen other players the log knows nothing
+// about.
+//
+//	RECORD_DIR=./sessions make run
+//	go run ./cmd/replay -list
+//	go run ./cmd/replay -speed 4 123
+//	go run ./cmd/replay -refeed 123
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"dmud/internal/game"
+	"dmud/internal/recording"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	inputColor = "\033[1;36m"
+	resetColor = "\033[0m"
+)
+
+func main() {
+	dir := flag.String("dir", "./sessions", "directory holding session logs")
+	speed := flag.Float64("speed", 1, "playback speed multiplier; 0 plays without delays")
+	list := flag.Bool("list", false, "list recorded sessions")
+	refeed := flag.Bool("refeed", false, "re-run the session's inputs against a fresh world instead of printing its output")
+	resources := flag.String("resources", "./resources", "resource directory for -refeed")
+	flag.Parse()
+
+	if *list {
+		if err := listSessions(*dir); err != nil {
+			fail(err)
+		}
+		return
+	}
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: replay [flags] <session-id|path>")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	header, events, err := recording.ReadSession(sessionPath(*dir, flag.Arg(0)))
+	if err != nil {
+		fail(err)
+	}
+
+	fmt.Printf("Session %d from %s, started %s (seed %d)\n\n",
+		header.Session, header.RemoteAddr, header.Start.Format(time.RFC1123), header.Seed)
+
+	if *refeed {
+		err = refeedSession(header, events, *resources, *speed)
+	} else {
+		playSession(events, *speed)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+// sessionPath accepts either a session number or a path to a log file.
+func sessionPath(dir, arg string) string {
+	if id, err := strconv.Atoi(arg); err == nil {
+		return recording.SessionPath(dir, id)
+	}
+	return arg
+}
+
+func listSessions(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "session-*.log"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		header, events, err := recording.ReadSession(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+
+		inputs := 0
+		var length time.Duration
+		for _, event := range events {
+			if event.IsInput() {
+				inputs++
+			}
+			length = event.Elapsed()
+		}
+
+		fmt.Printf("%6d  %s  %-22s %4d commands  %s\n",
+			header.Session, header.Start.Format("2006-01-02 15:04:05"), header.RemoteAddr, inputs, length.Round(time.Second))
+	}
+	return nil
+}
+
+// wait sleeps until offset has elapsed since start, scaled by speed.
+func wait(start time.Time, offset time.Duration, speed float64) {
+	if speed <= 0 {
+		return
+	}
+	target := start.Add(time.Duration(float64(offset) / speed))
+	if d := time.Until(target); d > 0 {
+		time.Sleep(d)
+	}
+}
+
+func playSession(events []recording.Event, speed float64) {
+	start := time.Now()
+	for _, event := range events {
+		wait(start, event.Elapsed(), speed)
+
+		if event.IsInput() {
+			fmt.Printf("%s> %s%s\n", inputColor, event.Input, resetColor)
+			continue
+		}
+		printFrame(event.Output)
+	}
+}
+
+func printFrame(frame string) {
+	if isPrompt(frame) {
+		return
+	}
+	fmt.Print(frame)
+	if !strings.HasSuffix(frame, "\n") {
+		fmt.Println()
+	}
+}
+
+// refeedSession plays a session's inputs into a fresh world seeded like the
+// original and clocked from its start time, printing what the game sends
+// back. The world itself runs as fast as it can; speed only paces the
+// printing against the world clock.
+func refeedSession(header recording.Header, events []recording.Event, resources string, speed float64) error {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	if err := components.LoadResources(resources); err != nil {
+		return err
+	}
+
+	// The session was recorded against a server with no accounts yet, so
+	// the replay mustn't see or add to the live ones
+	saveDir, err := os.MkdirTemp("", "dmud-replay")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(saveDir)
+
+	clock := ecs.NewManualClock(header.Start)
+	g := game.NewGameWithConfig(&game.Config{
+		ResourceDir: resources,
+		SaveDir:     saveDir,
+		Seed:        header.Seed,
+		Clock:       clock,
+	})
+
+	start := time.Now()
+	client := &printingClient{addr: header.RemoteAddr, pace: func() {
+		wait(start, clock.Now().Sub(header.Start), speed)
+	}}
+
+	// Let the last command's effects (combat rounds, movement) play out
+	return g.Refeed(client, events, 30*time.Second, func(event recording.Event) {
+		wait(start, event.Elapsed(), speed)
+		fmt.Printf("%s> %s%s\n", inputColor, event.Input, resetColor)
+	})
+}
+
+// printingClient is a headless client that writes what the game sends it to
+// stdout, calling pace before each frame.
+type printingClient struct {
+	addr string
+	pace func()
+}
+
+func (c *printingClient) CloseConnection() error { return nil }
+func (c *printingClient) HandleRequest()         {}
+func (c *printingClient) RemoteAddr() string     { return c.addr }
+func (c *printingClient) SupportsPrompt() bool   { return false }
+
+func (c *printingClient) SendMessage(msg string) {
+	c.pace()
+	printFrame(msg)
+}
+
+// isPrompt reports whether frame is just a prompt, either the bare "> " or
+// one showing the player's health, mana and stamina.
+func isPrompt(frame string) bool {
+	return frame == "> " || (strings.HasPrefix(frame, "<") && strings.HasSuffix(frame, "> ") && !strings.Contains(frame, "\n"))
+}
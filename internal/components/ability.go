@@ -0,0 +1,268 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// AbilityTarget says who an ability can be used on.
+type AbilityTarget int
+
+const (
+	TargetSelf  AbilityTarget = iota
+	TargetEnemy               // One foe, named or the one being fought
+	TargetArea                // Every foe in the area
+)
+
+var abilityTargetFromString = map[string]AbilityTarget{
+	"self":  TargetSelf,
+	"enemy": TargetEnemy,
+	"area":  TargetArea,
+}
+
+// AbilityEffectType is what an ability does to each of its targets.
+type AbilityEffectType int
+
+const (
+	AbilityEffectDamage AbilityEffectType = iota
+	AbilityEffectHeal
+	AbilityEffectStun
+	AbilityEffectTaunt
+)
+
+var abilityEffectFromString = map[string]AbilityEffectType{
+	"damage": AbilityEffectDamage,
+	"heal":   AbilityEffectHeal,
+	"stun":   AbilityEffectStun,
+	"taunt":  AbilityEffectTaunt,
+}
+
+// AbilityEffect is one thing an ability does. Damage either scales the
+// user's weapon damage by Multiplier or rolls between Min and Max, and is of
+// DamageType if the effect has one, or else the weapon's type; healing
+// always rolls between Min and Max. Stuns last for Duration, as do taunts,
+// which force an NPC to attack the user.
+type AbilityEffect struct {
+	Type       AbilityEffectType
+	Multiplier float64
+	Min        int
+	Max        int
+	Duration   time.Duration
+
+	DamageType    DamageType
+	HasDamageType bool
+}
+
+// Ability is an active skill a class learns at Level. Rolled amounts grow
+// with the Scaling attribute, if the ability has one.
+type Ability struct {
+	ID          string
+	Name        string
+	Description string
+	Level       int
+	Target      AbilityTarget
+	Cost        int
+	Resource    string // Pool the cost is paid from, such as "mana"
+	Cooldown    time.Duration
+	Opener      bool      // Only usable to start a fight, not during one
+	Scaling     Attribute // Attribute that adds to rolled amounts
+	HasScaling  bool
+	Effects     []AbilityEffect
+}
+
+// ScalingBonus is how much the user's Scaling attribute adds to a rolled
+// amount.
+func (a *Ability) ScalingBonus(attributes *Attributes) int {
+	if !a.HasScaling {
+		return 0
+	}
+	return 2 * attributes.Modifier(a.Scaling)
+}
+
+// IsOffensive reports whether the ability is used on foes.
+func (a *Ability) IsOffensive() bool {
+	return a.Target != TargetSelf
+}
+
+type abilityEffectJSON struct {
+	Type            string  `json:"type"`
+	Multiplier      float64 `json:"multiplier,omitempty"`
+	Min             int     `json:"min,omitempty"`
+	Max             int     `json:"max,omitempty"`
+	DurationSeconds int     `json:"duration_seconds,omitempty"`
+	DamageType      string  `json:"damage_type,omitempty"`
+}
+
+type abilityJSON struct {
+	ID              string              `json:"id"`
+	Name            string              `json:"name"`
+	Description     string              `json:"description"`
+	Level           int                 `json:"level"`
+	Target          string              `json:"target"`
+	Cost            int                 `json:"cost"`
+	Resource        string              `json:"resource,omitempty"`
+	CooldownSeconds int                 `json:"cooldown_seconds"`
+	Opener          bool                `json:"opener,omitempty"`
+	Scaling         string              `json:"scaling,omitempty"`
+	Effects         []abilityEffectJSON `json:"effects"`
+}
+
+// Abilities holds every ability, keyed by ID.
+var Abilities = make(map[string]*Ability)
+
+func (res *resourceSet) loadAbilities(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var abilities []abilityJSON
+	if err := json.Unmarshal(data, &abilities); err != nil {
+		return err
+	}
+
+	for _, a := range abilities {
+		target, ok := abilityTargetFromString[a.Target]
+		if !ok {
+			return fmt.Errorf("ability %s: target must be self, enemy or area, not %q", a.ID, a.Target)
+		}
+		switch a.Resource {
+		case "", ResourceMana, ResourceStamina:
+		default:
+			return fmt.Errorf("ability %s: resource must be mana or stamina, not %q", a.ID, a.Resource)
+		}
+		if len(a.Effects) == 0 {
+			return fmt.Errorf("ability %s: needs at least one effect", a.ID)
+		}
+
+		effects := make([]AbilityEffect, len(a.Effects))
+		for i, e := range a.Effects {
+			effectType, ok := abilityEffectFromString[e.Type]
+			if !ok {
+				return fmt.Errorf("ability %s: unknown effect %q", a.ID, e.Type)
+			}
+			effects[i] = AbilityEffect{
+				Type:       effectType,
+				Multiplier: e.Multiplier,
+				Min:        e.Min,
+				Max:        e.Max,
+				Duration:   time.Duration(e.DurationSeconds) * time.Second,
+			}
+			if e.DamageType != "" {
+				damageType, ok := ParseDamageType(e.DamageType)
+				if !ok {
+					return fmt.Errorf("ability %s: unknown damage type %q", a.ID, e.DamageType)
+				}
+				effects[i].DamageType = damageType
+				effects[i].HasDamageType = true
+			}
+		}
+
+		ability := &Ability{
+			ID:          a.ID,
+			Name:        a.Name,
+			Description: a.Description,
+			Level:       a.Level,
+			Target:      target,
+			Cost:        a.Cost,
+			Resource:    a.Resource,
+			Cooldown:    time.Duration(a.CooldownSeconds) * time.Second,
+			Opener:      a.Opener,
+			Effects:     effects,
+		}
+		if a.Scaling != "" {
+			scaling, ok := ParseAttribute(a.Scaling)
+			if !ok {
+				return fmt.Errorf("ability %s: unknown scaling attribute %q", a.ID, a.Scaling)
+			}
+			ability.Scaling = scaling
+			ability.HasScaling = true
+		}
+		if ability.Level < 1 {
+			ability.Level = 1
+		}
+
+		res.abilities[a.ID] = ability
+	}
+
+	log.Info().Msgf("Loaded %d abilities from %s", len(abilities), filename)
+	return nil
+}
+
+// Skills are the abilities a character has learned and when each can next
+// be used.
+type Skills struct {
+	sync.RWMutex
+
+	Known     []string // Ability IDs in the order they were learned
+	Cooldowns map[string]time.Time
+}
+
+func NewSkills() *Skills {
+	return &Skills{
+		Cooldowns: make(map[string]time.Time),
+	}
+}
+
+func (s *Skills) Type() string {
+	return "Skills"
+}
+
+func (s *Skills) Knows(id string) bool {
+	s.RLock()
+	defer s.RUnlock()
+	for _, known := range s.Known {
+		if known == id {
+			return true
+		}
+	}
+	return false
+}
+
+// GetKnown returns the learned ability IDs.
+func (s *Skills) GetKnown() []string {
+	s.RLock()
+	defer s.RUnlock()
+	known := make([]string, len(s.Known))
+	copy(known, s.Known)
+	return known
+}
+
+// LearnForLevel teaches every ability class has unlocked by level, returning
+// the ones that are new.
+func (s *Skills) LearnForLevel(class *Class, level int) []*Ability {
+	var learned []*Ability
+	for _, id := range class.Abilities {
+		ability, ok := Abilities[id]
+		if !ok || ability.Level > level || s.Knows(id) {
+			continue
+		}
+		s.Lock()
+		s.Known = append(s.Known, id)
+		s.Unlock()
+		learned = append(learned, ability)
+	}
+	return learned
+}
+
+// CooldownRemaining is how long until ability id can be used again.
+func (s *Skills) CooldownRemaining(id string, now time.Time) time.Duration {
+	s.RLock()
+	defer s.RUnlock()
+	remaining := s.Cooldowns[id].Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+func (s *Skills) StartCooldown(id string, cooldown time.Duration, now time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	s.Cooldowns[id] = now.Add(cooldown)
+}
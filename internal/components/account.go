@@ -0,0 +1,77 @@
+package components
+
+import (
+	"dmud/internal/util"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// AccountStore remembers the password each character name was registered
+// with, so a player who comes back under that name can prove it is theirs.
+// Names are compared without regard to case. When Path is set the accounts
+// are saved there, so they outlast a restart along with the vaults they key.
+type AccountStore struct {
+	sync.RWMutex
+
+	Path      string
+	passwords map[string]string // Lower-cased name to password hash
+}
+
+// NewAccountStore opens the accounts saved at path, or an empty store if
+// there are none yet or path is empty.
+func NewAccountStore(path string) (*AccountStore, error) {
+	as := &AccountStore{Path: path, passwords: make(map[string]string)}
+	if path == "" {
+		return as, nil
+	}
+	if _, err := readJSON(path, &as.passwords); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
+// AccountKey is the form of name accounts and their vaults are stored under.
+func AccountKey(name string) string {
+	return strings.ToLower(name)
+}
+
+// Exists reports whether name has been registered.
+func (as *AccountStore) Exists(name string) bool {
+	as.RLock()
+	defer as.RUnlock()
+	_, ok := as.passwords[AccountKey(name)]
+	return ok
+}
+
+// Register claims name with password, returning false if it is already
+// registered.
+func (as *AccountStore) Register(name, password string) bool {
+	hash := util.HashAndSalt(password)
+
+	as.Lock()
+	defer as.Unlock()
+
+	key := AccountKey(name)
+	if _, ok := as.passwords[key]; ok {
+		return false
+	}
+	as.passwords[key] = hash
+
+	if as.Path != "" {
+		if err := writeJSON(as.Path, as.passwords); err != nil {
+			log.Error().Err(err).Msgf("Error saving the account of %s", key)
+		}
+	}
+	return true
+}
+
+// Check reports whether password is the one name was registered with.
+func (as *AccountStore) Check(name, password string) bool {
+	as.RLock()
+	hash, ok := as.passwords[AccountKey(name)]
+	as.RUnlock()
+
+	return ok && util.ComparePassword(hash, password)
+}
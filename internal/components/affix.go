@@ -0,0 +1,224 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Rarity is how exceptional a dropped item is. Rarer items roll more affixes
+// and are worth more.
+type Rarity int
+
+const (
+	RarityCommon Rarity = iota
+	RarityUncommon
+	RarityRare
+	RarityEpic
+)
+
+var rarityFromString = map[string]Rarity{
+	"common":   RarityCommon,
+	"uncommon": RarityUncommon,
+	"rare":     RarityRare,
+	"epic":     RarityEpic,
+}
+
+// rarityTier is how often a rarity drops and what it does to an item.
+type rarityTier struct {
+	name            string
+	color           string // ANSI colour used when the item is listed
+	weight          int    // Relative chance of dropping
+	affixes         int    // How many affixes are rolled
+	valueMultiplier int
+}
+
+var rarityTiers = map[Rarity]rarityTier{
+	RarityCommon:   {name: "common", color: "", weight: 70, affixes: 0, valueMultiplier: 1},
+	RarityUncommon: {name: "uncommon", color: "\033[1;32m", weight: 20, affixes: 1, valueMultiplier: 2},
+	RarityRare:     {name: "rare", color: "\033[1;34m", weight: 8, affixes: 2, valueMultiplier: 4},
+	RarityEpic:     {name: "epic", color: "\033[1;35m", weight: 2, affixes: 3, valueMultiplier: 8},
+}
+
+const colorReset = "\033[0m"
+
+func (r Rarity) String() string {
+	if tier, ok := rarityTiers[r]; ok {
+		return tier.name
+	}
+	return "unknown"
+}
+
+// Label is the rarity as shown to players, e.g. "Rare".
+func (r Rarity) Label() string {
+	name := r.String()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// Colorize wraps text in the rarity's colour.
+func (r Rarity) Colorize(text string) string {
+	if r == RarityCommon {
+		return text
+	}
+	return rarityTiers[r].color + text + colorReset
+}
+
+// RollRarity picks a rarity by weight, never lower than floor.
+func RollRarity(rng *rand.Rand, floor Rarity) Rarity {
+	total := 0
+	for r := floor; r <= RarityEpic; r++ {
+		total += rarityTiers[r].weight
+	}
+
+	roll := rng.Intn(total)
+	for r := floor; r < RarityEpic; r++ {
+		if roll < rarityTiers[r].weight {
+			return r
+		}
+		roll -= rarityTiers[r].weight
+	}
+	return RarityEpic
+}
+
+// Affix is a named bonus rolled onto equipment when it drops, such as
+// "of the Bear" for extra health. Prefixes go before the item's name and
+// suffixes after it.
+type Affix struct {
+	ID        string
+	Name      string
+	Suffix    bool
+	Modifiers StatModifiers
+	Slots     []EquipmentSlot // Slots the affix can roll on; empty means any
+}
+
+// CanRollOn reports whether the affix can appear on an item in slot.
+func (a *Affix) CanRollOn(slot EquipmentSlot) bool {
+	if len(a.Slots) == 0 {
+		return true
+	}
+	for _, s := range a.Slots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+type affixJSON struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Position  string            `json:"position"`
+	Modifiers statModifiersJSON `json:"modifiers"`
+	Slots     []string          `json:"slots,omitempty"`
+}
+
+// Affixes holds every affix that can roll on dropped equipment, keyed by ID.
+var Affixes = make(map[string]*Affix)
+
+func (res *resourceSet) loadAffixes(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var affixes []affixJSON
+	if err := json.Unmarshal(data, &affixes); err != nil {
+		return err
+	}
+
+	for _, a := range affixes {
+		if a.Position != "prefix" && a.Position != "suffix" {
+			return fmt.Errorf("affix %s: position must be prefix or suffix, not %q", a.ID, a.Position)
+		}
+
+		if err := a.Modifiers.validate(); err != nil {
+			return fmt.Errorf("affix %s: %v", a.ID, err)
+		}
+
+		slots := make([]EquipmentSlot, len(a.Slots))
+		for i, s := range a.Slots {
+			slot, ok := slotFromString[s]
+			if !ok || slot == SlotNone {
+				return fmt.Errorf("affix %s: unknown slot %q", a.ID, s)
+			}
+			slots[i] = slot
+		}
+
+		res.affixes[a.ID] = &Affix{
+			ID:     a.ID,
+			Name:   a.Name,
+			Suffix: a.Position == "suffix",
+			Modifiers: StatModifiers{
+				MinDamage: a.Modifiers.MinDamage,
+				MaxDamage: a.Modifiers.MaxDamage,
+				Armor:     a.Modifiers.Armor,
+				MaxHP:     a.Modifiers.MaxHP,
+			},
+			Slots: slots,
+		}
+	}
+
+	log.Info().Msgf("Loaded %d affixes from %s", len(affixes), filename)
+	return nil
+}
+
+// ApplyRarity makes item the given rarity, rolling its affixes and renaming
+// it after them, e.g. "Sharp Rusty Dagger of the Bear". Only equipment can
+// have a rarity; anything else is left common.
+func ApplyRarity(rng *rand.Rand, item *Item, rarity Rarity) {
+	item.Lock()
+	defer item.Unlock()
+
+	if item.Slot == SlotNone || item.Stackable {
+		return
+	}
+
+	var candidates []*Affix
+	for _, affix := range Affixes {
+		if affix.CanRollOn(item.Slot) {
+			candidates = append(candidates, affix)
+		}
+	}
+	// Map order isn't stable, so sort before shuffling to keep seeded games
+	// reproducible
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ID < candidates[j].ID
+	})
+	rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	var prefix, suffix string
+	for _, affix := range candidates {
+		if len(item.Affixes) == rarityTiers[rarity].affixes {
+			break
+		}
+
+		item.Affixes = append(item.Affixes, affix.Name)
+		item.Modifiers.MinDamage += affix.Modifiers.MinDamage
+		item.Modifiers.MaxDamage += affix.Modifiers.MaxDamage
+		item.Modifiers.Armor += affix.Modifiers.Armor
+		item.Modifiers.MaxHP += affix.Modifiers.MaxHP
+
+		if affix.Suffix && suffix == "" {
+			suffix = affix.Name
+		} else if !affix.Suffix && prefix == "" {
+			prefix = affix.Name
+		}
+	}
+
+	if prefix != "" {
+		item.Name = prefix + " " + item.Name
+	}
+	if suffix != "" {
+		item.Name = item.Name + " " + suffix
+	}
+
+	item.Rarity = rarity
+	item.Value *= rarityTiers[rarity].valueMultiplier
+}
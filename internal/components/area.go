@@ -2,6 +2,7 @@ package components
 
 import (
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
@@ -12,6 +13,12 @@ type Exit struct {
 	Area      *Area
 }
 
+// GroundItem is an item lying on the floor of an area.
+type GroundItem struct {
+	Item      *Item
+	DroppedAt time.Time
+}
+
 type Area struct {
 	X int
 	Y int
@@ -20,9 +27,12 @@ type Area struct {
 	Region      string
 	Description string
 	Exits       []Exit
+	Flags       map[string]bool // Features of the area, such as "campfire"
 	Players     []*Player
+	GroundItems []*GroundItem
 
-	PlayersMutex sync.RWMutex
+	PlayersMutex     sync.RWMutex
+	GroundItemsMutex sync.RWMutex
 }
 
 func (a *Area) AddPlayer(p *Player) {
@@ -35,6 +45,10 @@ func (a *Area) AddPlayer(p *Player) {
 	a.PlayersMutex.Unlock()
 }
 
+func (a *Area) HasFlag(flag string) bool {
+	return a.Flags[flag]
+}
+
 func (a *Area) GetExit(direction string) *Exit {
 	for i := range a.Exits {
 		exit := &a.Exits[i]
@@ -93,6 +107,73 @@ func (a *Area) GetCorpses(w WorldLike) []*Corpse {
 	return corpses
 }
 
+// AddGroundItem leaves an item on the ground. Stackable items merge into a
+// matching pile, which keeps decaying from when it was first dropped so a
+// trickle of drops can't keep it around forever.
+func (a *Area) AddGroundItem(item *Item, now time.Time) {
+	a.GroundItemsMutex.Lock()
+	defer a.GroundItemsMutex.Unlock()
+
+	if item.Stackable {
+		for _, ground := range a.GroundItems {
+			if ground.Item.ID == item.ID {
+				ground.Item.Lock()
+				ground.Item.Quantity += item.Quantity
+				ground.Item.Unlock()
+				return
+			}
+		}
+	}
+
+	a.GroundItems = append(a.GroundItems, &GroundItem{Item: item, DroppedAt: now})
+}
+
+func (a *Area) GetGroundItems() []*Item {
+	a.GroundItemsMutex.RLock()
+	defer a.GroundItemsMutex.RUnlock()
+
+	items := make([]*Item, len(a.GroundItems))
+	for i, ground := range a.GroundItems {
+		items[i] = ground.Item
+	}
+	return items
+}
+
+// RemoveGroundItem picks an item up off the ground. It returns false if the
+// item is no longer there, e.g. because someone else took it first.
+func (a *Area) RemoveGroundItem(item *Item) bool {
+	a.GroundItemsMutex.Lock()
+	defer a.GroundItemsMutex.Unlock()
+
+	for i, ground := range a.GroundItems {
+		if ground.Item == item {
+			a.GroundItems = append(a.GroundItems[:i], a.GroundItems[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveDecayedGroundItems removes and returns items that have been lying on
+// the ground for longer than decayTime.
+func (a *Area) RemoveDecayedGroundItems(decayTime time.Duration, now time.Time) []*Item {
+	a.GroundItemsMutex.Lock()
+	defer a.GroundItemsMutex.Unlock()
+
+	var decayed []*Item
+	remaining := a.GroundItems[:0]
+	for _, ground := range a.GroundItems {
+		if now.Sub(ground.DroppedAt) >= decayTime {
+			decayed = append(decayed, ground.Item)
+		} else {
+			remaining = append(remaining, ground)
+		}
+	}
+	a.GroundItems = remaining
+
+	return decayed
+}
+
 func (a *Area) GetPlayer(name string) *Player {
 	a.PlayersMutex.RLock()
 	defer a.PlayersMutex.RUnlock()
@@ -0,0 +1,159 @@
+package components
+
+import (
+	"strings"
+	"sync"
+)
+
+// Attribute is one of a character's primary stats.
+type Attribute int
+
+const (
+	Strength Attribute = iota
+	Dexterity
+	Constitution
+	Intelligence
+	Wisdom
+)
+
+// AllAttributes lists the attributes in display order.
+var AllAttributes = []Attribute{Strength, Dexterity, Constitution, Intelligence, Wisdom}
+
+var attributeNames = map[Attribute]string{
+	Strength:     "Strength",
+	Dexterity:    "Dexterity",
+	Constitution: "Constitution",
+	Intelligence: "Intelligence",
+	Wisdom:       "Wisdom",
+}
+
+const (
+	// BaseAttribute is an unremarkable score; derived stats are unchanged at it.
+	BaseAttribute = 10
+	// MaxAttribute is as high as training can take an attribute.
+	MaxAttribute = 25
+	// StartingTrainingPoints are handed out at character creation.
+	StartingTrainingPoints = 5
+	// TrainingPointsPerLevel are earned each time a character levels up.
+	TrainingPointsPerLevel = 2
+	// HPPerConstitution is the max HP each point of Constitution adds or
+	// takes away.
+	HPPerConstitution = 5
+)
+
+func (a Attribute) String() string {
+	return attributeNames[a]
+}
+
+// Abbrev is the attribute's short form, such as STR.
+func (a Attribute) Abbrev() string {
+	return strings.ToUpper(attributeNames[a][:3])
+}
+
+// ParseAttribute matches a full or abbreviated attribute name.
+func ParseAttribute(name string) (Attribute, bool) {
+	name = strings.ToLower(name)
+	if len(name) < 3 {
+		return 0, false
+	}
+	for _, attr := range AllAttributes {
+		if strings.HasPrefix(strings.ToLower(attr.String()), name) {
+			return attr, true
+		}
+	}
+	return 0, false
+}
+
+// Attributes are a character's primary stats. Strength drives damage and
+// carry capacity, Dexterity accuracy and evasion, and Constitution health;
+// Intelligence and Wisdom are the mental stats magic draws on.
+type Attributes struct {
+	sync.RWMutex
+
+	Scores map[Attribute]int
+	Points int // Training points waiting to be spent
+}
+
+func NewAttributes() *Attributes {
+	scores := make(map[Attribute]int, len(AllAttributes))
+	for _, attr := range AllAttributes {
+		scores[attr] = BaseAttribute
+	}
+	return &Attributes{
+		Scores: scores,
+		Points: StartingTrainingPoints,
+	}
+}
+
+func (a *Attributes) Type() string {
+	return "Attributes"
+}
+
+// Get returns an attribute's score. A nil Attributes reads as all average,
+// so NPCs and half-built entities get unmodified stats.
+func (a *Attributes) Get(attr Attribute) int {
+	if a == nil {
+		return BaseAttribute
+	}
+	a.RLock()
+	defer a.RUnlock()
+	return a.Scores[attr]
+}
+
+// Modifier is how far an attribute is above or below average.
+func (a *Attributes) Modifier(attr Attribute) int {
+	return a.Get(attr) - BaseAttribute
+}
+
+func (a *Attributes) GetPoints() int {
+	a.RLock()
+	defer a.RUnlock()
+	return a.Points
+}
+
+func (a *Attributes) AddPoints(n int) {
+	a.Lock()
+	defer a.Unlock()
+	a.Points += n
+}
+
+// Train spends a training point raising attr by one, returning false
+// without changing anything if there are no points or attr is maxed out.
+func (a *Attributes) Train(attr Attribute) bool {
+	a.Lock()
+	defer a.Unlock()
+
+	if a.Points <= 0 || a.Scores[attr] >= MaxAttribute {
+		return false
+	}
+	a.Scores[attr]++
+	a.Points--
+	return true
+}
+
+// ApplyModifiers adds a race's or class's modifiers to attributes.
+func (a *Attributes) ApplyModifiers(modifiers map[Attribute]int) {
+	a.Lock()
+	defer a.Unlock()
+	for attr, value := range modifiers {
+		a.Scores[attr] += value
+	}
+}
+
+// BonusHP is the max HP Constitution adds, or takes away when below average.
+func (a *Attributes) BonusHP() int {
+	return a.Modifier(Constitution) * HPPerConstitution
+}
+
+// PlayerMaxHP is a player's maximum health at level, with their attributes
+// and equipment taken into account. Either may be nil.
+func PlayerMaxHP(level int, attributes *Attributes, equipment *Equipment) int {
+	maxHP := int(float64(100)*GetLevelScaling(level)) + attributes.BonusHP()
+	if equipment != nil {
+		maxHP += equipment.TotalModifiers().MaxHP
+	}
+	if maxHP < 1 {
+		maxHP = 1
+	}
+	return maxHP
+}
@@ -0,0 +1,220 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Race is a playable race. Its modifiers are added to the starting
+// attributes, and new characters of the race begin in StartArea.
+type Race struct {
+	ID          string
+	Name        string
+	Description string
+	Modifiers   map[Attribute]int
+	StartArea   string // Area new characters appear in; empty for the default
+	Resistances Resistances
+}
+
+// StartingItem is an item a class begins with, worn or wielded if Equip is
+// set.
+type StartingItem struct {
+	ItemID   string
+	Quantity int
+	Equip    bool
+}
+
+// Class is a playable class: attribute modifiers, the gear a new character
+// starts with and the abilities the class can use.
+type Class struct {
+	ID            string
+	Name          string
+	Description   string
+	Modifiers     map[Attribute]int
+	StartingItems []StartingItem
+	Abilities     []string
+}
+
+type raceJSON struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Modifiers   map[string]int     `json:"modifiers,omitempty"`
+	StartArea   string             `json:"start_area,omitempty"`
+	Resistances map[string]float64 `json:"resistances,omitempty"`
+}
+
+type startingItemJSON struct {
+	ItemID   string `json:"item_id"`
+	Quantity int    `json:"quantity"`
+	Equip    bool   `json:"equip,omitempty"`
+}
+
+type classJSON struct {
+	ID            string             `json:"id"`
+	Name          string             `json:"name"`
+	Description   string             `json:"description"`
+	Modifiers     map[string]int     `json:"modifiers,omitempty"`
+	StartingItems []startingItemJSON `json:"starting_items,omitempty"`
+	Abilities     []string           `json:"abilities,omitempty"`
+}
+
+// Races and Classes hold every playable race and class, keyed by ID, and
+// RaceOrder and ClassOrder list their IDs in the order they were loaded.
+var (
+	Races      = make(map[string]*Race)
+	Classes    = make(map[string]*Class)
+	RaceOrder  []string
+	ClassOrder []string
+)
+
+func (res *resourceSet) loadRaces(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var races []raceJSON
+	if err := json.Unmarshal(data, &races); err != nil {
+		return err
+	}
+
+	for _, r := range races {
+		modifiers, err := toAttributeModifiers(r.Modifiers)
+		if err != nil {
+			return fmt.Errorf("race %s: %v", r.ID, err)
+		}
+
+		resistances, err := parseResistances(r.Resistances)
+		if err != nil {
+			return fmt.Errorf("race %s: %v", r.ID, err)
+		}
+
+		res.raceOrder = append(res.raceOrder, r.ID)
+		res.races[r.ID] = &Race{
+			ID:          r.ID,
+			Name:        r.Name,
+			Description: r.Description,
+			Modifiers:   modifiers,
+			StartArea:   r.StartArea,
+			Resistances: resistances,
+		}
+	}
+
+	log.Info().Msgf("Loaded %d races from %s", len(races), filename)
+	return nil
+}
+
+func (res *resourceSet) loadClasses(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var classes []classJSON
+	if err := json.Unmarshal(data, &classes); err != nil {
+		return err
+	}
+
+	for _, c := range classes {
+		modifiers, err := toAttributeModifiers(c.Modifiers)
+		if err != nil {
+			return fmt.Errorf("class %s: %v", c.ID, err)
+		}
+
+		for _, id := range c.Abilities {
+			if _, ok := res.abilities[id]; !ok {
+				return fmt.Errorf("class %s: unknown ability %q", c.ID, id)
+			}
+		}
+
+		items := make([]StartingItem, len(c.StartingItems))
+		for i, item := range c.StartingItems {
+			quantity := item.Quantity
+			if quantity < 1 {
+				quantity = 1
+			}
+			items[i] = StartingItem{ItemID: item.ItemID, Quantity: quantity, Equip: item.Equip}
+		}
+
+		res.classOrder = append(res.classOrder, c.ID)
+		res.classes[c.ID] = &Class{
+			ID:            c.ID,
+			Name:          c.Name,
+			Description:   c.Description,
+			Modifiers:     modifiers,
+			StartingItems: items,
+			Abilities:     c.Abilities,
+		}
+	}
+
+	log.Info().Msgf("Loaded %d classes from %s", len(classes), filename)
+	return nil
+}
+
+func toAttributeModifiers(raw map[string]int) (map[Attribute]int, error) {
+	modifiers := make(map[Attribute]int, len(raw))
+	for name, value := range raw {
+		attr, ok := ParseAttribute(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown attribute %q", name)
+		}
+		modifiers[attr] += value
+	}
+	return modifiers, nil
+}
+
+// Character is who a player chose to be when they created their character.
+type Character struct {
+	sync.RWMutex
+
+	Race  *Race
+	Class *Class
+}
+
+func NewCharacter(race *Race, class *Class) *Character {
+	return &Character{Race: race, Class: class}
+}
+
+func (c *Character) Type() string {
+	return "Character"
+}
+
+// Describe reads like "Human Warrior".
+func (c *Character) Describe() string {
+	c.RLock()
+	defer c.RUnlock()
+	return fmt.Sprintf("%s %s", c.Race.Name, c.Class.Name)
+}
+
+// CreationStep is where a new player is in creating their character.
+type CreationStep int
+
+const (
+	CreationName CreationStep = iota
+	CreationPassword
+	CreationRace
+	CreationClass
+)
+
+// CharacterCreation is held by a player who hasn't finished creating their
+// character. Their input goes to the creation prompts instead of commands.
+type CharacterCreation struct {
+	sync.RWMutex
+
+	Step      CreationStep
+	Returning bool // The name is registered, so the password must match it
+	Race      *Race
+}
+
+func NewCharacterCreation() *CharacterCreation {
+	return &CharacterCreation{Step: CreationName}
+}
+
+func (cc *CharacterCreation) Type() string {
+	return "CharacterCreation"
+}
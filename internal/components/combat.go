@@ -2,9 +2,18 @@ package components
 
 import (
 	"dmud/internal/common"
+	"math/rand"
 	"sync"
+	"time"
 )
 
+// DefaultCombatRound is how often fighters swing when the game doesn't
+// configure a round length.
+const DefaultCombatRound = 2 * time.Second
+
+// CriticalMultiplier scales the damage of a critical hit.
+const CriticalMultiplier = 2
+
 type Combat struct {
 	sync.RWMutex
 
@@ -12,4 +21,140 @@ type Combat struct {
 	TargetQueue []common.EntityID // Queue of additional targets to attack
 	MinDamage   int
 	MaxDamage   int
+	NextRound   time.Time // When this fighter next swings; zero swings straight away
+}
+
+// CombatRatings describe how well a fighter attacks and defends. Dodge,
+// Parry, Block and Crit are percentage chances; Parry needs a weapon and
+// Block a shield, so they are zero without one.
+type CombatRatings struct {
+	Attack  int
+	Defense int
+	Dodge   int
+	Parry   int
+	Block   int
+	Crit    int
+}
+
+// AttackOutcome is how a single swing turned out.
+type AttackOutcome int
+
+const (
+	OutcomeMiss AttackOutcome = iota
+	OutcomeDodge
+	OutcomeParry
+	OutcomeBlock
+	OutcomeHit
+	OutcomeCritical
+)
+
+// Landed reports whether the swing did damage.
+func (o AttackOutcome) Landed() bool {
+	return o == OutcomeHit || o == OutcomeCritical
+}
+
+// ResolveAttack rolls one swing. The attack rating against the defense
+// rating decides whether it connects at all; a swing on target can still be
+// dodged, parried or blocked, and one that lands may be a critical hit.
+func ResolveAttack(rng *rand.Rand, attacker, defender CombatRatings) AttackOutcome {
+	hitChance := 75 + (attacker.Attack-defender.Defense)*2
+	if hitChance < 5 {
+		hitChance = 5
+	}
+	if hitChance > 95 {
+		hitChance = 95
+	}
+	if rng.Intn(100) >= hitChance {
+		return OutcomeMiss
+	}
+
+	roll := rng.Intn(100)
+	if roll < defender.Dodge {
+		return OutcomeDodge
+	}
+	roll -= defender.Dodge
+	if roll < defender.Parry {
+		return OutcomeParry
+	}
+	roll -= defender.Parry
+	if roll < defender.Block {
+		return OutcomeBlock
+	}
+
+	if rng.Intn(100) < attacker.Crit {
+		return OutcomeCritical
+	}
+	return OutcomeHit
+}
+
+type damageVerb struct {
+	max   int // Largest damage described by this verb
+	first string
+	third string
+}
+
+var damageVerbs = []damageVerb{
+	{2, "scratch", "scratches"},
+	{4, "graze", "grazes"},
+	{7, "hit", "hits"},
+	{10, "injure", "injures"},
+	{14, "wound", "wounds"},
+	{19, "maul", "mauls"},
+	{25, "decimate", "decimates"},
+	{32, "devastate", "devastates"},
+	{40, "maim", "maims"},
+	{50, "MUTILATE", "MUTILATES"},
+	{65, "DISEMBOWEL", "DISEMBOWELS"},
+	{85, "MASSACRE", "MASSACRES"},
+}
+
+// DamageVerb describes a blow by how hard it hit, returning the verb as
+// the attacker says it ("You maul") and as others see it ("mauls").
+func DamageVerb(damage int) (string, string) {
+	for _, verb := range damageVerbs {
+		if damage <= verb.max {
+			return verb.first, verb.third
+		}
+	}
+	return "*** OBLITERATE ***", "*** OBLITERATES ***"
+}
+
+// PlayerCombatRatings works out a player's ratings from their level,
+// Dexterity and equipment; attributes and equipment may be nil. Parrying
+// needs a working weapon in hand and blocking a working shield.
+func PlayerCombatRatings(level int, attributes *Attributes, equipment *Equipment) CombatRatings {
+	dexterity := attributes.Modifier(Dexterity)
+	ratings := CombatRatings{
+		Attack:  10 + 2*level + dexterity,
+		Defense: 10 + 2*level + dexterity/2,
+		Dodge:   5 + dexterity/2,
+		Crit:    5,
+	}
+	if ratings.Dodge < 0 {
+		ratings.Dodge = 0
+	}
+
+	if equipment != nil {
+		if weapon := equipment.Get(SlotMainHand); weapon != nil && !weapon.IsBroken() {
+			ratings.Parry = 10
+		}
+		if shield := equipment.Get(SlotOffHand); shield != nil && shield.Type == ItemTypeArmor && !shield.IsBroken() {
+			ratings.Block = 15
+		}
+	}
+	return ratings
+}
+
+// NPCCombatRatings returns the ratings an NPC built from templateID fights
+// with.
+func NPCCombatRatings(templateID string) CombatRatings {
+	if template, ok := NPCTemplates[templateID]; ok {
+		return template.Ratings
+	}
+	return CombatRatings{
+		Attack:  defaultNPCRating,
+		Defense: defaultNPCRating,
+		Dodge:   defaultNPCDodge,
+		Crit:    defaultNPCCrit,
+	}
 }
@@ -1,7 +1,6 @@
 package components
 
 import (
-	"dmud/internal/common"
 	"sync"
 	"time"
 )
@@ -10,7 +9,7 @@ type Corpse struct {
 	sync.RWMutex
 
 	VictimName  string
-	VictimID    common.EntityID
+	Owner       string // Account of the player who died; empty for NPCs
 	WasPlayer   bool
 	TimeOfDeath time.Time
 	DecayTime   time.Duration // How long before the corpse decays (in seconds)
@@ -19,19 +18,18 @@ type Corpse struct {
 	LootedAt    *time.Time
 }
 
-func (c *Corpse) IsDecayed() bool {
+func (c *Corpse) IsDecayed(now time.Time) bool {
 	c.RLock()
 	defer c.RUnlock()
 	if c.LootedAt != nil { // If corpse was fully looted, decay after 5 seconds
-		return time.Since(*c.LootedAt) >= 5*time.Second
+		return now.Sub(*c.LootedAt) >= 5*time.Second
 	} // Otherwise use normal decay time
-	return time.Since(c.TimeOfDeath) >= c.DecayTime
+	return now.Sub(c.TimeOfDeath) >= c.DecayTime
 }
 
-func (c *Corpse) MarkAsLooted() {
+func (c *Corpse) MarkAsLooted(now time.Time) {
 	c.Lock()
 	defer c.Unlock()
-	now := time.Now()
 	c.LootedAt = &now
 }
 
@@ -44,12 +42,28 @@ func (c *Corpse) GetDescription() string {
 	return "the corpse of " + c.VictimName
 }
 
-func NewCorpse(victimName string, victimID common.EntityID, wasPlayer bool, area *Area, inventory *Inventory) *Corpse {
+// CanLoot reports whether the player logged in as account may take from the
+// corpse. Anyone can loot an NPC, but a player's corpse belongs to their
+// account alone, whatever character they are playing now, unless consent
+// allows the looter.
+func (c *Corpse) CanLoot(account string, consent *Consent) bool {
+	c.RLock()
+	wasPlayer := c.WasPlayer
+	owner := c.Owner
+	c.RUnlock()
+
+	if !wasPlayer || AccountKey(account) == AccountKey(owner) {
+		return true
+	}
+	return consent != nil && consent.Allows(account)
+}
+
+func NewCorpse(victimName, owner string, wasPlayer bool, area *Area, inventory *Inventory, now time.Time) *Corpse {
 	return &Corpse{
 		VictimName:  victimName,
-		VictimID:    victimID,
+		Owner:       owner,
 		WasPlayer:   wasPlayer,
-		TimeOfDeath: time.Now(),
+		TimeOfDeath: now,
 		DecayTime:   30 * time.Minute,
 		Area:        area,
 		Inventory:   inventory,
@@ -0,0 +1,47 @@
+package components
+
+import "sync"
+
+// CraftingSkill tracks how practiced a player is at crafting. Higher levels
+// unlock harder recipes and make every attempt more likely to succeed.
+type CraftingSkill struct {
+	sync.RWMutex
+
+	Level int
+	XP    int
+}
+
+func NewCraftingSkill() *CraftingSkill {
+	return &CraftingSkill{
+		Level: 1,
+	}
+}
+
+func (cs *CraftingSkill) Type() string {
+	return "CraftingSkill"
+}
+
+func (cs *CraftingSkill) GetLevel() int {
+	cs.RLock()
+	defer cs.RUnlock()
+	return cs.Level
+}
+
+// CraftingXPForLevel is the XP needed to advance past level.
+func CraftingXPForLevel(level int) int {
+	return level * 50
+}
+
+func (cs *CraftingSkill) AddXP(amount int) (leveledUp bool, newLevel int) {
+	cs.Lock()
+	defer cs.Unlock()
+
+	oldLevel := cs.Level
+	cs.XP += amount
+	for cs.XP >= CraftingXPForLevel(cs.Level) {
+		cs.XP -= CraftingXPForLevel(cs.Level)
+		cs.Level++
+	}
+
+	return cs.Level > oldLevel, cs.Level
+}
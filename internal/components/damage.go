@@ -0,0 +1,188 @@
+package components
+
+import (
+	"dmud/internal/common"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DamageType is the kind of harm a blow or spell does. Creatures can shrug
+// off some kinds and be weak to others.
+type DamageType int
+
+const (
+	DamageSlash DamageType = iota
+	DamagePierce
+	DamageBludgeon
+	DamageFire
+	DamageCold
+	DamagePoison
+	DamageHoly
+)
+
+var damageTypeFromString = map[string]DamageType{
+	"slash":    DamageSlash,
+	"pierce":   DamagePierce,
+	"bludgeon": DamageBludgeon,
+	"fire":     DamageFire,
+	"cold":     DamageCold,
+	"poison":   DamagePoison,
+	"holy":     DamageHoly,
+}
+
+func (d DamageType) String() string {
+	for name, damageType := range damageTypeFromString {
+		if damageType == d {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+func ParseDamageType(name string) (DamageType, bool) {
+	damageType, ok := damageTypeFromString[name]
+	return damageType, ok
+}
+
+const (
+	// UnarmedDamageType is dealt by fists, and by NPCs whose template
+	// doesn't say otherwise.
+	UnarmedDamageType = DamageBludgeon
+
+	// MinResistanceMultiplier and MaxResistanceMultiplier bound how far
+	// stacked resistances and weaknesses can scale a hit.
+	MinResistanceMultiplier = 0.25
+	MaxResistanceMultiplier = 2.0
+)
+
+// Resistances scale the damage taken of each type: below 1 resists it and
+// above 1 is a weakness. Types left out take normal damage.
+type Resistances map[DamageType]float64
+
+// Multiplier is how much damage of type t is scaled by.
+func (r Resistances) Multiplier(t DamageType) float64 {
+	if multiplier, ok := r[t]; ok {
+		return multiplier
+	}
+	return 1
+}
+
+// Combine returns r and other together, multiplying where both cover the
+// same type.
+func (r Resistances) Combine(other Resistances) Resistances {
+	combined := make(Resistances, len(r)+len(other))
+	for t, multiplier := range r {
+		combined[t] = multiplier
+	}
+	for t, multiplier := range other {
+		combined[t] = combined.Multiplier(t) * multiplier
+	}
+	return combined
+}
+
+// Describe lists the resistances in damage type order, like
+// "pierce x0.5, holy x1.5", or returns "" when there are none.
+func (r Resistances) Describe() string {
+	var parts []string
+	for t := DamageSlash; t <= DamageHoly; t++ {
+		if multiplier, ok := r[t]; ok && multiplier != 1 {
+			parts = append(parts, fmt.Sprintf("%s %s", t, formatMultiplier(multiplier)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func parseResistances(raw map[string]float64) (Resistances, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	resistances := make(Resistances, len(raw))
+	for name, multiplier := range raw {
+		damageType, ok := ParseDamageType(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown damage type %q", name)
+		}
+		if multiplier <= 0 {
+			return nil, fmt.Errorf("resistance to %s must be above 0, not %g", name, multiplier)
+		}
+		resistances[damageType] = multiplier
+	}
+	return resistances, nil
+}
+
+// EntityResistances gathers everything that changes how much damage an
+// entity takes: an NPC's template, or a player's race, unbroken worn gear
+// and status effects.
+func EntityResistances(w WorldLike, entityID common.EntityID) Resistances {
+	var resistances Resistances
+
+	if npcComp, err := w.GetComponent(entityID, "NPC"); err == nil {
+		if template, ok := NPCTemplates[npcComp.(*NPC).TemplateID]; ok {
+			resistances = resistances.Combine(template.Resistances)
+		}
+	}
+	if charComp, err := w.GetComponent(entityID, "Character"); err == nil {
+		character := charComp.(*Character)
+		character.RLock()
+		race := character.Race
+		character.RUnlock()
+		if race != nil {
+			resistances = resistances.Combine(race.Resistances)
+		}
+	}
+	if equipComp, err := w.GetComponent(entityID, "Equipment"); err == nil {
+		for _, item := range equipComp.(*Equipment).GetItems() {
+			if !item.IsBroken() {
+				resistances = resistances.Combine(item.Resistances)
+			}
+		}
+	}
+	if effectsComp, err := w.GetComponent(entityID, "StatusEffects"); err == nil {
+		resistances = resistances.Combine(effectsComp.(*StatusEffects).Resistances())
+	}
+
+	return resistances
+}
+
+// ResistanceMultiplier is how much an entity's resistances scale damage of
+// type t, within MinResistanceMultiplier and MaxResistanceMultiplier.
+func ResistanceMultiplier(w WorldLike, entityID common.EntityID, t DamageType) float64 {
+	multiplier := EntityResistances(w, entityID).Multiplier(t)
+	if multiplier < MinResistanceMultiplier {
+		return MinResistanceMultiplier
+	}
+	if multiplier > MaxResistanceMultiplier {
+		return MaxResistanceMultiplier
+	}
+	return multiplier
+}
+
+// DescribeResistance explains a resistance multiplier for combat messages,
+// like "pierce resisted x0.5", or returns "" when damage wasn't scaled.
+func DescribeResistance(t DamageType, multiplier float64) string {
+	switch {
+	case multiplier < 1:
+		return fmt.Sprintf("%s resisted %s", t, formatMultiplier(multiplier))
+	case multiplier > 1:
+		return fmt.Sprintf("%s weakness %s", t, formatMultiplier(multiplier))
+	}
+	return ""
+}
+
+// formatMultiplier reads like "x1.5", rounded to two places so stacked
+// resistances stay readable.
+func formatMultiplier(multiplier float64) string {
+	return fmt.Sprintf("x%g", math.Round(multiplier*100)/100)
+}
+
+// WeaponDamageType is the kind of damage a player deals with whatever is
+// in their main hand.
+func WeaponDamageType(equipment *Equipment) DamageType {
+	if equipment != nil {
+		if weapon := equipment.Get(SlotMainHand); weapon != nil {
+			return weapon.DamageType
+		}
+	}
+	return UnarmedDamageType
+}
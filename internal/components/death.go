@@ -0,0 +1,147 @@
+package components
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultGhostDuration is how long a dead player stays a ghost before
+	// they are pulled back to life at their bind point.
+	DefaultGhostDuration = 30 * time.Second
+
+	// DeathXPPenalty is the share of the XP needed for the next level lost
+	// on dying.
+	DeathXPPenalty = 0.1
+
+	// Players come back weakened for a while, dealing less damage.
+	WeakenedDuration         = 2 * time.Minute
+	WeakenedDamageMultiplier = 0.75
+
+	// ShrineFlag marks an area players can bind themselves to.
+	ShrineFlag = "shrine"
+)
+
+// Ghost is held by a dead player until they return to life.
+type Ghost struct {
+	sync.RWMutex
+
+	DiedAt time.Time
+}
+
+func NewGhost(now time.Time) *Ghost {
+	return &Ghost{DiedAt: now}
+}
+
+func (g *Ghost) Type() string {
+	return "Ghost"
+}
+
+// Since is how long ago the player died.
+func (g *Ghost) Since(now time.Time) time.Duration {
+	g.RLock()
+	defer g.RUnlock()
+	return now.Sub(g.DiedAt)
+}
+
+// BindPoint is the area a player returns to life in.
+type BindPoint struct {
+	sync.RWMutex
+
+	AreaID string
+}
+
+func NewBindPoint(areaID string) *BindPoint {
+	return &BindPoint{AreaID: areaID}
+}
+
+func (b *BindPoint) Type() string {
+	return "BindPoint"
+}
+
+func (b *BindPoint) Get() string {
+	b.RLock()
+	defer b.RUnlock()
+	return b.AreaID
+}
+
+func (b *BindPoint) Set(areaID string) {
+	b.Lock()
+	defer b.Unlock()
+	b.AreaID = areaID
+}
+
+// Consent lists the accounts allowed to loot a player's corpse.
+type Consent struct {
+	sync.RWMutex
+
+	Names map[string]bool
+}
+
+func NewConsent() *Consent {
+	return &Consent{Names: make(map[string]bool)}
+}
+
+func (c *Consent) Grant(account string) {
+	c.Lock()
+	defer c.Unlock()
+	c.Names[AccountKey(account)] = true
+}
+
+// Revoke takes consent away, returning false if account didn't have it.
+func (c *Consent) Revoke(account string) bool {
+	c.Lock()
+	defer c.Unlock()
+	key := AccountKey(account)
+	if !c.Names[key] {
+		return false
+	}
+	delete(c.Names, key)
+	return true
+}
+
+func (c *Consent) Allows(account string) bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.Names[AccountKey(account)]
+}
+
+// List returns the consented names in alphabetical order.
+func (c *Consent) List() []string {
+	c.RLock()
+	defer c.RUnlock()
+	names := make([]string, 0, len(c.Names))
+	for name := range c.Names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConsentStore keeps each account's consent. Like their corpse, it outlasts
+// the character, so a player who logs out and back in can still let a friend
+// recover their things.
+type ConsentStore struct {
+	sync.Mutex
+
+	consents map[string]*Consent
+}
+
+func NewConsentStore() *ConsentStore {
+	return &ConsentStore{consents: make(map[string]*Consent)}
+}
+
+// Get returns account's consent, starting an empty one the first time.
+func (cs *ConsentStore) Get(account string) *Consent {
+	cs.Lock()
+	defer cs.Unlock()
+
+	key := AccountKey(account)
+	if consent, ok := cs.consents[key]; ok {
+		return consent
+	}
+	consent := NewConsent()
+	cs.consents[key] = consent
+	return consent
+}
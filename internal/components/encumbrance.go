@@ -0,0 +1,41 @@
+package components
+
+import "dmud/internal/common"
+
+// Carry capacity comes from Strength: an average character can carry 35.
+const (
+	BaseCarryCapacity        = 5.0
+	CarryCapacityPerStrength = 3.0
+)
+
+func CarryCapacity(strength int) float64 {
+	capacity := BaseCarryCapacity + CarryCapacityPerStrength*float64(strength)
+	if capacity < BaseCarryCapacity {
+		capacity = BaseCarryCapacity
+	}
+	return capacity
+}
+
+// CarryingWeight returns how much an entity is carrying, worn items
+// included, and how much it can carry.
+func CarryingWeight(w WorldLike, entityID common.EntityID) (carried float64, capacity float64) {
+	var attributes *Attributes
+	if comp, err := w.GetComponent(entityID, "Attributes"); err == nil {
+		attributes = comp.(*Attributes)
+	}
+
+	if comp, err := w.GetComponent(entityID, "Inventory"); err == nil {
+		carried += comp.(*Inventory).TotalWeight()
+	}
+	if comp, err := w.GetComponent(entityID, "Equipment"); err == nil {
+		carried += comp.(*Equipment).TotalWeight()
+	}
+
+	return carried, CarryCapacity(attributes.Get(Strength))
+}
+
+// IsOverburdened reports whether an entity is carrying more than it can.
+func IsOverburdened(w WorldLike, entityID common.EntityID) bool {
+	carried, capacity := CarryingWeight(w, entityID)
+	return carried > capacity
+}
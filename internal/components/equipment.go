@@ -0,0 +1,147 @@
+package components
+
+import "sync"
+
+// Base damage range for an unarmed player, before level scaling.
+const (
+	BasePlayerMinDamage = 10
+	BasePlayerMaxDamage = 50
+)
+
+// EquipmentSlots lists the slots in display order.
+var EquipmentSlots = []EquipmentSlot{
+	SlotHead,
+	SlotChest,
+	SlotLegs,
+	SlotFeet,
+	SlotMainHand,
+	SlotOffHand,
+}
+
+type Equipment struct {
+	sync.RWMutex
+
+	Slots map[EquipmentSlot]*Item
+}
+
+func NewEquipment() *Equipment {
+	return &Equipment{
+		Slots: make(map[EquipmentSlot]*Item),
+	}
+}
+
+func (e *Equipment) Type() string {
+	return "Equipment"
+}
+
+// Equip puts item in its slot and returns whatever was there before.
+func (e *Equipment) Equip(item *Item) *Item {
+	e.Lock()
+	defer e.Unlock()
+
+	previous := e.Slots[item.Slot]
+	e.Slots[item.Slot] = item
+	return previous
+}
+
+// Unequip empties slot and returns the item that was in it.
+func (e *Equipment) Unequip(slot EquipmentSlot) *Item {
+	e.Lock()
+	defer e.Unlock()
+
+	item := e.Slots[slot]
+	delete(e.Slots, slot)
+	return item
+}
+
+func (e *Equipment) Get(slot EquipmentSlot) *Item {
+	e.RLock()
+	defer e.RUnlock()
+	return e.Slots[slot]
+}
+
+// GetItems returns the equipped items in slot order.
+func (e *Equipment) GetItems() []*Item {
+	e.RLock()
+	defer e.RUnlock()
+
+	items := make([]*Item, 0, len(e.Slots))
+	for _, slot := range EquipmentSlots {
+		if item, ok := e.Slots[slot]; ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (e *Equipment) TotalWeight() float64 {
+	e.RLock()
+	defer e.RUnlock()
+
+	total := 0.0
+	for _, item := range e.Slots {
+		total += item.TotalWeight()
+	}
+	return total
+}
+
+// TotalModifiers sums the stat modifiers of everything equipped. Broken
+// items don't count.
+func (e *Equipment) TotalModifiers() StatModifiers {
+	e.RLock()
+	defer e.RUnlock()
+
+	var total StatModifiers
+	for _, item := range e.Slots {
+		mods := item.ActiveModifiers()
+		total.MinDamage += mods.MinDamage
+		total.MaxDamage += mods.MaxDamage
+		total.Armor += mods.Armor
+		total.MaxHP += mods.MaxHP
+	}
+	return total
+}
+
+// WearableArmor returns the equipped items that protect the wearer and can
+// still be worn down.
+func (e *Equipment) WearableArmor() []*Item {
+	e.RLock()
+	defer e.RUnlock()
+
+	var items []*Item
+	for _, slot := range EquipmentSlots {
+		item, ok := e.Slots[slot]
+		if !ok {
+			continue
+		}
+		item.RLock()
+		wearable := item.Modifiers.Armor > 0 && item.Durability > 0
+		item.RUnlock()
+		if wearable {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// PlayerDamageRange returns a player's damage range with their Strength and
+// their equipment's modifiers applied. Either may be nil.
+func PlayerDamageRange(equipment *Equipment, attributes *Attributes) (int, int) {
+	strength := attributes.Modifier(Strength)
+	minDamage := BasePlayerMinDamage + strength
+	maxDamage := BasePlayerMaxDamage + 2*strength
+
+	if equipment != nil {
+		mods := equipment.TotalModifiers()
+		minDamage += mods.MinDamage
+		maxDamage += mods.MaxDamage
+	}
+
+	if minDamage < 1 {
+		minDamage = 1
+	}
+	if maxDamage < minDamage {
+		maxDamage = minDamage
+	}
+	return minDamage, maxDamage
+}
@@ -63,6 +63,19 @@ func (e *Experience) AddXP(amount int) (leveledUp bool, newLevel int) {
 	return false, e.Level
 }
 
+// LoseXP takes up to amount from the XP earned towards the next level,
+// never costing a level, and returns how much was lost.
+func (e *Experience) LoseXP(amount int) int {
+	e.Lock()
+	defer e.Unlock()
+
+	if amount > e.Current {
+		amount = e.Current
+	}
+	e.Current -= amount
+	return amount
+}
+
 func GetLevelScaling(level int) float64 {
 	if level <= 1 {
 		return 1.0
@@ -0,0 +1,56 @@
+package components
+
+import "sync"
+
+const (
+	// BaseFleeChance is the percent chance an average character escapes
+	// when they flee. Each point of Dexterity modifier adds FleeChancePerDex.
+	BaseFleeChance   = 50
+	FleeChancePerDex = 3
+	MinFleeChance    = 10
+	MaxFleeChance    = 90
+
+	// FleeXPPenalty is the share of the XP needed for the next level lost
+	// by running away.
+	FleeXPPenalty = 0.05
+)
+
+// FleeChance is the percent chance a character with attributes escapes.
+func FleeChance(attributes *Attributes) int {
+	chance := BaseFleeChance + attributes.Modifier(Dexterity)*FleeChancePerDex
+	if chance < MinFleeChance {
+		return MinFleeChance
+	}
+	if chance > MaxFleeChance {
+		return MaxFleeChance
+	}
+	return chance
+}
+
+// Wimpy makes a player flee automatically once a hit leaves their health
+// below Threshold. A threshold of zero turns it off.
+type Wimpy struct {
+	sync.RWMutex
+
+	Threshold int
+}
+
+func NewWimpy() *Wimpy {
+	return &Wimpy{}
+}
+
+func (wi *Wimpy) Type() string {
+	return "Wimpy"
+}
+
+func (wi *Wimpy) GetThreshold() int {
+	wi.RLock()
+	defer wi.RUnlock()
+	return wi.Threshold
+}
+
+func (wi *Wimpy) SetThreshold(threshold int) {
+	wi.Lock()
+	defer wi.Unlock()
+	wi.Threshold = threshold
+}
@@ -25,6 +25,18 @@ func (hc *Health) GetEffectiveMax(bonus int) int {
 	return hc.Max + bonus
 }
 
+// AdjustMax changes the maximum HP, as when gear granting HP is put on or
+// taken off. Gaining max HP heals by the same amount; losing it never leaves
+// current HP above the new maximum.
+func (hc *Health) AdjustMax(delta int) {
+	hc.Max += delta
+	if delta > 0 {
+		hc.Current += delta
+	} else if hc.Current > hc.Max {
+		hc.Current = hc.Max
+	}
+}
+
 func (hc *Health) Heal(amount int) {
 	hc.Current += amount
 	if hc.Current > hc.Max {
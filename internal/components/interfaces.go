@@ -1,8 +1,15 @@
 package components
 
-import "dmud/internal/common"
+import (
+	"math/rand"
+	"time"
+
+	"dmud/internal/common"
+)
 
 type WorldLike interface {
+	Now() time.Time
+	Rand() *rand.Rand
 	FindEntitiesByComponentPredicate(componentType string, predicate func(interface{}) bool) ([]EntityLike, error)
 	GetComponent(entityID common.EntityID, componentType string) (interface{}, error)
 	RemoveComponent(entityID common.EntityID, componentType string) error
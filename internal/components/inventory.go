@@ -20,14 +20,12 @@ func (inv *Inventory) Type() string {
 	return "Inventory"
 }
 
+// AddItem puts item in the inventory, returning false if there is no room.
+// A stackable item joins a stack already there, which needs no free slot.
 func (inv *Inventory) AddItem(item *Item) bool {
 	inv.Lock()
 	defer inv.Unlock()
 
-	if inv.MaxSlots > 0 && len(inv.Items) >= inv.MaxSlots {
-		return false
-	}
-
 	// Check if item is stackable and already exists
 	if item.Stackable {
 		for _, existing := range inv.Items {
@@ -41,56 +39,113 @@ func (inv *Inventory) AddItem(item *Item) bool {
 		}
 	}
 
+	if inv.MaxSlots > 0 && len(inv.Items) >= inv.MaxSlots {
+		return false
+	}
+
 	// Add as new item
 	inv.Items = append(inv.Items, item)
 	return true
 }
 
+// RemoveItem takes quantity of an item out of the inventory. itemID may be a
+// template ID, which matches the first such item, or an instance ID from
+// Item.Key, which matches only that item.
 func (inv *Inventory) RemoveItem(itemID string, quantity int) *Item {
 	inv.Lock()
 	defer inv.Unlock()
 
 	for i, item := range inv.Items {
-		item.Lock()
-		if item.ID == itemID {
-			if item.Stackable && item.Quantity > quantity {
-				// Partial removal from stack
-				item.Quantity -= quantity
-				removed := item.Clone()
-				removed.Quantity = quantity
-				item.Unlock()
-				return removed
-			} else {
-				// Remove entire item/stack
-				removed := item.Clone()
-				item.Unlock()
-				inv.Items = append(inv.Items[:i], inv.Items[i+1:]...)
-				return removed
-			}
+		item.RLock()
+		matches := item.ID == itemID || item.InstanceID == itemID
+		partial := item.Stackable && item.Quantity > quantity
+		item.RUnlock()
+
+		if !matches {
+			continue
+		}
+
+		if partial {
+			// Partial removal from stack
+			item.Lock()
+			item.Quantity -= quantity
+			item.Unlock()
+
+			removed := item.Clone()
+			removed.Quantity = quantity
+			return removed
 		}
-		item.Unlock()
+
+		// Remove entire item/stack
+		inv.Items = append(inv.Items[:i], inv.Items[i+1:]...)
+		return item.Clone()
 	}
 
 	return nil
 }
 
+// RemoveQuantity takes quantity of template itemID out of the inventory,
+// from as many stacks and instances as it takes, and returns how many it
+// removed.
+func (inv *Inventory) RemoveQuantity(itemID string, quantity int) int {
+	removed := 0
+	for removed < quantity {
+		item := inv.RemoveItem(itemID, quantity-removed)
+		if item == nil {
+			break
+		}
+		removed += item.Quantity
+	}
+	return removed
+}
+
 func (inv *Inventory) FindItem(itemID string) *Item {
 	inv.RLock()
 	defer inv.RUnlock()
 
 	for _, item := range inv.Items {
 		item.RLock()
-		if item.ID == itemID {
-			clone := item.Clone()
-			item.RUnlock()
-			return clone
-		}
+		matches := item.ID == itemID || item.InstanceID == itemID
 		item.RUnlock()
+
+		if matches {
+			return item.Clone()
+		}
 	}
 
 	return nil
 }
 
+// UpdateItem calls fn on the item with the given key (see Item.Key), letting
+// callers change an item in place rather than a copy of it. It returns false
+// if there is no such item.
+func (inv *Inventory) UpdateItem(key string, fn func(item *Item)) bool {
+	inv.Lock()
+	defer inv.Unlock()
+
+	for _, item := range inv.Items {
+		item.RLock()
+		matches := item.ID == key || item.InstanceID == key
+		item.RUnlock()
+
+		if matches {
+			fn(item)
+			return true
+		}
+	}
+
+	return false
+}
+
+// TakeAll empties the inventory, returning everything that was in it.
+func (inv *Inventory) TakeAll() []*Item {
+	inv.Lock()
+	defer inv.Unlock()
+	items := inv.Items
+	inv.Items = make([]*Item, 0)
+	return items
+}
+
 func (inv *Inventory) GetItems() []*Item {
 	inv.RLock()
 	defer inv.RUnlock()
@@ -102,6 +157,26 @@ func (inv *Inventory) GetItems() []*Item {
 	return items
 }
 
+// TotalWeight is the weight of everything in the inventory, including the
+// contents of any containers.
+func (inv *Inventory) TotalWeight() float64 {
+	inv.RLock()
+	defer inv.RUnlock()
+
+	total := 0.0
+	for _, item := range inv.Items {
+		total += item.TotalWeight()
+	}
+	return total
+}
+
+func (inv *Inventory) IsEmpty() bool {
+	inv.RLock()
+	defer inv.RUnlock()
+
+	return len(inv.Items) == 0
+}
+
 func (inv *Inventory) IsFull() bool {
 	inv.RLock()
 	defer inv.RUnlock()
@@ -1,6 +1,11 @@
 package components
 
-import "sync"
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
 
 type ItemType int
 
@@ -9,31 +14,286 @@ const (
 	ItemTypeArmor
 	ItemTypeConsumable
 	ItemTypeMisc
+	ItemTypeCurrency // Coins; collected into the Wallet rather than carried
+)
+
+var itemTypeFromString = map[string]ItemType{
+	"weapon":     ItemTypeWeapon,
+	"armor":      ItemTypeArmor,
+	"consumable": ItemTypeConsumable,
+	"misc":       ItemTypeMisc,
+	"currency":   ItemTypeCurrency,
+}
+
+// EquipmentSlot is where an item is worn or wielded.
+type EquipmentSlot int
+
+const (
+	SlotNone EquipmentSlot = iota
+	SlotHead
+	SlotChest
+	SlotLegs
+	SlotFeet
+	SlotMainHand
+	SlotOffHand
+)
+
+var slotFromString = map[string]EquipmentSlot{
+	"":          SlotNone,
+	"head":      SlotHead,
+	"chest":     SlotChest,
+	"legs":      SlotLegs,
+	"feet":      SlotFeet,
+	"main_hand": SlotMainHand,
+	"off_hand":  SlotOffHand,
+}
+
+// StatModifiers are the bonuses an item grants while it is equipped.
+type StatModifiers struct {
+	MinDamage int
+	MaxDamage int
+	Armor     int
+	MaxHP     int
+}
+
+type UseEffectType int
+
+const (
+	UseEffectHeal UseEffectType = iota
+	UseEffectStatus
+	UseEffectTeachRecipe
 )
 
+var useEffectFromString = map[string]UseEffectType{
+	"heal":          UseEffectHeal,
+	"status_effect": UseEffectStatus,
+	"teach_recipe":  UseEffectTeachRecipe,
+}
+
+// UseEffect describes what happens when an item is consumed or used.
+type UseEffect struct {
+	Type        UseEffectType
+	Amount      int           // HP healed, or HP bonus granted by a status effect
+	Name        string        // Status effect name or recipe ID
+	Duration    time.Duration // How long a status effect lasts
+	Resistances Resistances   // Granted by a status effect while it lasts
+}
+
 type Item struct {
 	sync.RWMutex
 
-	ID          string
-	Name        string
-	Description string
-	Type        ItemType
-	Value       int
-	Stackable   bool
-	Quantity    int
+	ID               string // Template ID, shared by every copy of the item
+	InstanceID       string // Unique to this item; empty for stackable items
+	Name             string
+	Description      string
+	Type             ItemType
+	Value            int
+	Stackable        bool
+	Quantity         int
+	Weight           float64
+	LevelRequirement int
+	Slot             EquipmentSlot
+	Modifiers        StatModifiers
+	UseVerb          string // "eat", "drink" or "use"; empty if the item can't be consumed
+	UseEffects       []UseEffect
+	Container        *Inventory // Contents of a bag or backpack, nil for other items
+	Rarity           Rarity
+	Affixes          []string    // Names of the affixes rolled when the item dropped
+	Durability       int         // Remaining condition; the item is broken at 0
+	MaxDurability    int         // 0 for items that never wear out
+	DamageType       DamageType  // What kind of damage a weapon does; weapons must set one
+	Resistances      Resistances // Granted while worn; shared by every copy of the item
 }
 
 func (i *Item) Clone() *Item {
 	i.RLock()
 	defer i.RUnlock()
 
+	var useEffects []UseEffect
+	if len(i.UseEffects) > 0 {
+		useEffects = make([]UseEffect, len(i.UseEffects))
+		copy(useEffects, i.UseEffects)
+	}
+
+	var affixes []string
+	if len(i.Affixes) > 0 {
+		affixes = make([]string, len(i.Affixes))
+		copy(affixes, i.Affixes)
+	}
+
 	return &Item{
-		ID:          i.ID,
-		Name:        i.Name,
-		Description: i.Description,
-		Type:        i.Type,
-		Value:       i.Value,
-		Stackable:   i.Stackable,
-		Quantity:    i.Quantity,
+		ID:               i.ID,
+		InstanceID:       i.InstanceID,
+		Name:             i.Name,
+		Description:      i.Description,
+		Type:             i.Type,
+		Value:            i.Value,
+		Stackable:        i.Stackable,
+		Quantity:         i.Quantity,
+		Weight:           i.Weight,
+		LevelRequirement: i.LevelRequirement,
+		Slot:             i.Slot,
+		Modifiers:        i.Modifiers,
+		UseVerb:          i.UseVerb,
+		UseEffects:       useEffects,
+		Container:        i.Container, // Copies refer to the same contents
+		Rarity:           i.Rarity,
+		Affixes:          affixes,
+		Durability:       i.Durability,
+		MaxDurability:    i.MaxDurability,
+		DamageType:       i.DamageType,
+		Resistances:      i.Resistances,
+	}
+}
+
+// Key identifies this particular item within an inventory: its instance ID,
+// or its template ID for stackable items, whose copies are interchangeable.
+func (i *Item) Key() string {
+	i.RLock()
+	defer i.RUnlock()
+
+	if i.InstanceID != "" {
+		return i.InstanceID
+	}
+	return i.ID
+}
+
+// TotalWeight is the weight of the whole stack plus anything inside it.
+func (i *Item) TotalWeight() float64 {
+	i.RLock()
+	weight := i.Weight * float64(i.Quantity)
+	container := i.Container
+	i.RUnlock()
+
+	if container != nil {
+		weight += container.TotalWeight()
+	}
+	return weight
+}
+
+// DisplayName is the item's name with its quantity when it is a stack.
+func (i *Item) DisplayName() string {
+	i.RLock()
+	defer i.RUnlock()
+
+	if i.Stackable && i.Quantity > 1 {
+		return fmt.Sprintf("%s (x%d)", i.Name, i.Quantity)
+	}
+	return i.Name
+}
+
+// ColoredName is the item's name in the colour of its rarity, labelled with
+// the rarity unless it is common.
+func (i *Item) ColoredName() string {
+	i.RLock()
+	defer i.RUnlock()
+
+	if i.Rarity == RarityCommon {
+		return i.Name
+	}
+	return i.Rarity.Colorize(fmt.Sprintf("%s [%s]", i.Name, i.Rarity.Label()))
+}
+
+// IsBroken reports whether the item has worn out. Broken gear gives no
+// bonuses until it is repaired.
+func (i *Item) IsBroken() bool {
+	i.RLock()
+	defer i.RUnlock()
+	return i.MaxDurability > 0 && i.Durability <= 0
+}
+
+// ActiveModifiers are the bonuses the item currently gives, which are none
+// at all once it is broken.
+func (i *Item) ActiveModifiers() StatModifiers {
+	i.RLock()
+	defer i.RUnlock()
+
+	if i.MaxDurability > 0 && i.Durability <= 0 {
+		return StatModifiers{}
+	}
+	return i.Modifiers
+}
+
+// Wear takes amount off the item's durability, returning true if that breaks it.
+func (i *Item) Wear(amount int) bool {
+	i.Lock()
+	defer i.Unlock()
+
+	if i.MaxDurability == 0 || i.Durability <= 0 {
+		return false
+	}
+
+	i.Durability -= amount
+	if i.Durability <= 0 {
+		i.Durability = 0
+		return true
+	}
+	return false
+}
+
+// Repair restores the item to full durability.
+func (i *Item) Repair() {
+	i.Lock()
+	defer i.Unlock()
+	i.Durability = i.MaxDurability
+}
+
+// RepairCost is what a smith charges to fully repair the item: rate times
+// its value, scaled by how worn it is. Undamaged items cost nothing.
+func (i *Item) RepairCost(rate float64) int {
+	i.RLock()
+	defer i.RUnlock()
+
+	if i.MaxDurability == 0 || i.Durability >= i.MaxDurability {
+		return 0
+	}
+
+	worn := float64(i.MaxDurability-i.Durability) / float64(i.MaxDurability)
+	cost := int(math.Ceil(float64(i.Value) * rate * worn))
+	if cost < 1 {
+		cost = 1
+	}
+	return cost
+}
+
+// Condition describes how worn the item is, or is empty for items that
+// don't wear out.
+func (i *Item) Condition() string {
+	i.RLock()
+	defer i.RUnlock()
+
+	if i.MaxDurability == 0 {
+		return ""
+	}
+
+	percent := float64(i.Durability) / float64(i.MaxDurability) * 100
+	switch {
+	case i.Durability <= 0:
+		return "broken"
+	case percent >= 90:
+		return "excellent"
+	case percent >= 60:
+		return "good"
+	case percent >= 30:
+		return "worn"
+	default:
+		return "badly damaged"
+	}
+}
+
+var slotNames = map[EquipmentSlot]string{
+	SlotNone:     "none",
+	SlotHead:     "head",
+	SlotChest:    "chest",
+	SlotLegs:     "legs",
+	SlotFeet:     "feet",
+	SlotMainHand: "main hand",
+	SlotOffHand:  "off hand",
+}
+
+func (s EquipmentSlot) String() string {
+	if name, ok := slotNames[s]; ok {
+		return name
 	}
+	return "unknown"
 }
@@ -1,117 +1,265 @@
 package components
 
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// JSON structs for loading
+type statModifiersJSON struct {
+	MinDamage int `json:"min_damage"`
+	MaxDamage int `json:"max_damage"`
+	Armor     int `json:"armor"`
+	MaxHP     int `json:"max_hp"`
+}
+
+// validate checks that the damage bonus doesn't put the bottom of a range
+// above its top.
+func (m statModifiersJSON) validate() error {
+	if m.MinDamage > m.MaxDamage {
+		return fmt.Errorf("min_damage %d is above max_damage %d", m.MinDamage, m.MaxDamage)
+	}
+	return nil
+}
+
+type useEffectJSON struct {
+	Type            string             `json:"type"`
+	Amount          int                `json:"amount"`
+	Name            string             `json:"name,omitempty"`
+	DurationSeconds int                `json:"duration_seconds,omitempty"`
+	Resistances     map[string]float64 `json:"resistances,omitempty"`
+}
+
+type itemTemplateJSON struct {
+	ID               string             `json:"id"`
+	Name             string             `json:"name"`
+	Description      string             `json:"description"`
+	Type             string             `json:"type"`
+	Value            int                `json:"value"`
+	Stackable        bool               `json:"stackable"`
+	Weight           float64            `json:"weight"`
+	LevelRequirement int                `json:"level_requirement,omitempty"`
+	Slot             string             `json:"slot,omitempty"`
+	Modifiers        statModifiersJSON  `json:"modifiers,omitempty"`
+	UseVerb          string             `json:"use_verb,omitempty"`
+	UseEffects       []useEffectJSON    `json:"use_effects,omitempty"`
+	ContainerSlots   int                `json:"container_slots,omitempty"`
+	Durability       int                `json:"durability,omitempty"`
+	DamageType       string             `json:"damage_type,omitempty"`
+	Resistances      map[string]float64 `json:"resistances,omitempty"`
+}
+
+// useVerbs are the commands that can consume an item.
+var useVerbs = map[string]bool{
+	"eat":   true,
+	"drink": true,
+	"use":   true,
+}
+
 // ItemTemplates defines all available items in the game
-var ItemTemplates = map[string]Item{
-	"rat_fur": {
-		ID:          "rat_fur",
-		Name:        "Rat Fur",
-		Description: "A small patch of matted rat fur.",
-		Type:        ItemTypeMisc,
-		Value:       1,
-		Stackable:   true,
-		Quantity:    1,
-	},
-	"rat_tail": {
-		ID:          "rat_tail",
-		Name:        "Rat Tail",
-		Description: "A long, scaly rat tail.",
-		Type:        ItemTypeMisc,
-		Value:       2,
-		Stackable:   true,
-		Quantity:    1,
-	},
-	"goblin_ear": {
-		ID:          "goblin_ear",
-		Name:        "Goblin Ear",
-		Description: "A pointed goblin ear, still slightly warm.",
-		Type:        ItemTypeMisc,
-		Value:       5,
-		Stackable:   true,
-		Quantity:    1,
-	},
-	"rusty_dagger": {
-		ID:          "rusty_dagger",
-		Name:        "Rusty Dagger",
-		Description: "A crude, rusty dagger that's seen better days.",
-		Type:        ItemTypeWeapon,
-		Value:       10,
-		Stackable:   false,
-		Quantity:    1,
-	},
-	"gold_coin": {
-		ID:          "gold_coin",
-		Name:        "Gold Coin",
-		Description: "A shiny gold coin.",
-		Type:        ItemTypeMisc,
-		Value:       1,
-		Stackable:   true,
-		Quantity:    1,
-	},
-	"chicken_feather": {
-		ID:          "chicken_feather",
-		Name:        "Chicken Feather",
-		Description: "A soft, white chicken feather.",
-		Type:        ItemTypeMisc,
-		Value:       1,
-		Stackable:   true,
-		Quantity:    1,
-	},
-	"raw_chicken": {
-		ID:          "raw_chicken",
-		Name:        "Raw Chicken",
-		Description: "A freshly plucked chicken, ready to be cooked.",
-		Type:        ItemTypeConsumable,
-		Value:       5,
-		Stackable:   true,
-		Quantity:    1,
-	},
-	"leather_helmet": {
-		ID:          "leather_helmet",
-		Name:        "Leather Helmet",
-		Description: "A sturdy helmet made of tanned leather.",
-		Type:        ItemTypeArmor,
-		Value:       50,
-		Stackable:   false,
-		Quantity:    1,
-	},
-	"leather_chest": {
-		ID:          "leather_chest",
-		Name:        "Leather Chestpiece",
-		Description: "A protective chestpiece crafted from hardened leather.",
-		Type:        ItemTypeArmor,
-		Value:       75,
-		Stackable:   false,
-		Quantity:    1,
-	},
-	"leather_legs": {
-		ID:          "leather_legs",
-		Name:        "Leather Leggings",
-		Description: "Flexible leather leggings that provide good protection.",
-		Type:        ItemTypeArmor,
-		Value:       60,
-		Stackable:   false,
-		Quantity:    1,
-	},
-	"leather_boots": {
-		ID:          "leather_boots",
-		Name:        "Leather Boots",
-		Description: "Comfortable boots made from supple leather.",
-		Type:        ItemTypeArmor,
-		Value:       40,
-		Stackable:   false,
-		Quantity:    1,
-	},
-	"bone": {
-		ID:          "bone",
-		Name:        "Bone",
-		Description: "A weathered bone from some unfortunate creature.",
-		Type:        ItemTypeMisc,
-		Value:       3,
-		Stackable:   true,
-		Quantity:    1,
-	},
+var ItemTemplates = make(map[string]*Item)
+
+func (res *resourceSet) loadItemTemplates(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var templates []itemTemplateJSON
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return err
+	}
+
+	for _, t := range templates {
+		itemType, ok := itemTypeFromString[t.Type]
+		if !ok {
+			return fmt.Errorf("item %s: unknown type %q", t.ID, t.Type)
+		}
+
+		slot, ok := slotFromString[t.Slot]
+		if !ok {
+			return fmt.Errorf("item %s: unknown slot %q", t.ID, t.Slot)
+		}
+
+		useEffects := make([]UseEffect, len(t.UseEffects))
+		for i, e := range t.UseEffects {
+			effectType, ok := useEffectFromString[e.Type]
+			if !ok {
+				return fmt.Errorf("item %s: unknown use effect %q", t.ID, e.Type)
+			}
+			resistances, err := parseResistances(e.Resistances)
+			if err != nil {
+				return fmt.Errorf("item %s: %v", t.ID, err)
+			}
+			useEffects[i] = UseEffect{
+				Type:        effectType,
+				Amount:      e.Amount,
+				Name:        e.Name,
+				Duration:    time.Duration(e.DurationSeconds) * time.Second,
+				Resistances: resistances,
+			}
+		}
+
+		useVerb := t.UseVerb
+		if useVerb == "" && len(useEffects) > 0 {
+			useVerb = "use"
+		}
+		if useVerb != "" && !useVerbs[useVerb] {
+			return fmt.Errorf("item %s: unknown use verb %q", t.ID, t.UseVerb)
+		}
+
+		var container *Inventory
+		if t.ContainerSlots > 0 {
+			if t.Stackable {
+				return fmt.Errorf("item %s: containers can't be stackable", t.ID)
+			}
+			container = NewInventory(t.ContainerSlots)
+		}
+
+		if t.Durability > 0 && slot == SlotNone {
+			return fmt.Errorf("item %s: only equipment can have durability", t.ID)
+		}
+
+		// Every weapon names the kind of damage it does; nothing else has one
+		var damageType DamageType
+		if itemType == ItemTypeWeapon && t.DamageType == "" {
+			return fmt.Errorf("item %s: weapons need a damage type", t.ID)
+		}
+		if t.DamageType != "" {
+			if itemType != ItemTypeWeapon {
+				return fmt.Errorf("item %s: only weapons can have a damage type", t.ID)
+			}
+			parsed, ok := ParseDamageType(t.DamageType)
+			if !ok {
+				return fmt.Errorf("item %s: unknown damage type %q", t.ID, t.DamageType)
+			}
+			damageType = parsed
+		}
+
+		if err := t.Modifiers.validate(); err != nil {
+			return fmt.Errorf("item %s: %v", t.ID, err)
+		}
+
+		resistances, err := parseResistances(t.Resistances)
+		if err != nil {
+			return fmt.Errorf("item %s: %v", t.ID, err)
+		}
+		if resistances != nil && slot == SlotNone {
+			return fmt.Errorf("item %s: only equipment can grant resistances", t.ID)
+		}
+
+		res.items[t.ID] = &Item{
+			ID:               t.ID,
+			Name:             t.Name,
+			Description:      t.Description,
+			Type:             itemType,
+			Value:            t.Value,
+			Stackable:        t.Stackable,
+			Quantity:         1,
+			Weight:           t.Weight,
+			LevelRequirement: t.LevelRequirement,
+			Slot:             slot,
+			Modifiers: StatModifiers{
+				MinDamage: t.Modifiers.MinDamage,
+				MaxDamage: t.Modifiers.MaxDamage,
+				Armor:     t.Modifiers.Armor,
+				MaxHP:     t.Modifiers.MaxHP,
+			},
+			UseVerb:       useVerb,
+			UseEffects:    useEffects,
+			Container:     container,
+			Durability:    t.Durability,
+			MaxDurability: t.Durability,
+			DamageType:    damageType,
+			Resistances:   resistances,
+		}
+	}
+
+	log.Info().Msgf("Loaded %d item templates from %s", len(templates), filename)
+	return nil
+}
+
+// validateItemReferences checks that every item referenced by NPC loot
+// tables, shops, quests and recipes exists among the loaded item templates,
+// and that every recipe an item teaches was loaded too.
+func (res *resourceSet) validateItemReferences() error {
+	var missing []string
+
+	check := func(itemID, source string) {
+		if _, ok := res.items[itemID]; !ok {
+			missing = append(missing, fmt.Sprintf("%s (%s)", itemID, source))
+		}
+	}
+
+	for _, npc := range res.npcs {
+		for _, drop := range npc.LootTable {
+			check(drop.ItemID, "loot table of NPC "+npc.ID)
+		}
+		if npc.Shop != nil {
+			for _, stock := range npc.Shop.Stock {
+				check(stock.ItemID, "shop of NPC "+npc.ID)
+			}
+		}
+	}
+
+	for _, quest := range QuestRegistry {
+		for _, req := range quest.Requirements {
+			check(req.ItemID, "requirement of quest "+quest.ID)
+		}
+		for _, reward := range quest.Rewards {
+			if reward.Currency > 0 {
+				continue
+			}
+			check(reward.ItemID, "reward of quest "+quest.ID)
+		}
+	}
+
+	for _, class := range res.classes {
+		for _, item := range class.StartingItems {
+			check(item.ItemID, "starting items of class "+class.ID)
+		}
+	}
+
+	for _, recipe := range res.recipes {
+		for _, input := range recipe.Inputs {
+			check(input.ItemID, "input of recipe "+recipe.ID)
+		}
+		for _, output := range recipe.Outputs {
+			check(output.ItemID, "output of recipe "+recipe.ID)
+		}
+		if recipe.Tool != "" {
+			check(recipe.Tool, "tool of recipe "+recipe.ID)
+		}
+	}
+
+	for _, item := range res.items {
+		for _, effect := range item.UseEffects {
+			if effect.Type != UseEffectTeachRecipe {
+				continue
+			}
+			if _, ok := res.recipes[effect.Name]; !ok {
+				missing = append(missing, fmt.Sprintf("recipe %s (use effect of item %s)", effect.Name, item.ID))
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("unknown item references: %s", strings.Join(missing, ", "))
+	}
+	return nil
 }
 
+// nextInstanceID numbers the items created from templates.
+var nextInstanceID int64
+
 // CreateItem creates a new item from a template with the specified quantity
 func CreateItem(itemID string, quantity int) *Item {
 	template, exists := ItemTemplates[itemID]
@@ -121,5 +269,12 @@ func CreateItem(itemID string, quantity int) *Item {
 
 	item := template.Clone()
 	item.Quantity = quantity
+	if !item.Stackable {
+		item.InstanceID = fmt.Sprintf("%s#%d", itemID, atomic.AddInt64(&nextInstanceID, 1))
+	}
+	if template.Container != nil {
+		// Every bag gets its own contents
+		item.Container = NewInventory(template.Container.MaxSlots)
+	}
 	return item
 }
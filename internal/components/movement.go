@@ -17,3 +17,67 @@ type Movement struct {
 	Status    MovementStatus
 	Direction string
 }
+
+// Posture is how an entity is positioned between moves: standing, or Down
+// resting or asleep. Being down speeds up regeneration but rules out moving
+// and fighting.
+type Posture struct {
+	sync.RWMutex
+
+	Status MovementStatus
+	Asleep bool
+}
+
+func NewPosture() *Posture {
+	return &Posture{Status: Standing}
+}
+
+func (p *Posture) Type() string {
+	return "Posture"
+}
+
+func (p *Posture) IsDown() bool {
+	p.RLock()
+	defer p.RUnlock()
+	return p.Status == Down
+}
+
+func (p *Posture) IsAsleep() bool {
+	p.RLock()
+	defer p.RUnlock()
+	return p.Status == Down && p.Asleep
+}
+
+// LieDown puts the entity down to rest, or to sleep if asleep is set.
+func (p *Posture) LieDown(asleep bool) {
+	p.Lock()
+	defer p.Unlock()
+	p.Status = Down
+	p.Asleep = asleep
+}
+
+// Stand gets the entity up, returning false if it was already standing.
+func (p *Posture) Stand() bool {
+	p.Lock()
+	defer p.Unlock()
+	if p.Status != Down {
+		return false
+	}
+	p.Status = Standing
+	p.Asleep = false
+	return true
+}
+
+// RegenMultiplier is how much faster being down makes regeneration.
+func (p *Posture) RegenMultiplier() float64 {
+	p.RLock()
+	defer p.RUnlock()
+	switch {
+	case p.Status != Down:
+		return 1
+	case p.Asleep:
+		return SleepingRegenMultiplier
+	default:
+		return RestingRegenMultiplier
+	}
+}
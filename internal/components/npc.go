@@ -21,11 +21,11 @@ type NPC struct {
 	TemplateID   string
 }
 
-func (n *NPC) GetRandomDialogue() string {
+func (n *NPC) GetRandomDialogue(rng *rand.Rand) string {
 	n.RLock()
 	defer n.RUnlock()
 	if len(n.Dialogue) == 0 {
 		return ""
 	}
-	return n.Dialogue[rand.Intn(len(n.Dialogue))]
+	return n.Dialogue[rng.Intn(len(n.Dialogue))]
 }
@@ -2,6 +2,7 @@ package components
 
 import (
 	"encoding/json"
+	"fmt"
 	"math/rand"
 	"os"
 	"time"
@@ -18,6 +19,8 @@ const (
 	BehaviorFriendly
 	BehaviorMerchant
 	BehaviorGuard
+	BehaviorSmith
+	BehaviorBanker
 )
 
 var behaviorFromString = map[string]NPCBehavior{
@@ -27,13 +30,16 @@ var behaviorFromString = map[string]NPCBehavior{
 	"friendly":   BehaviorFriendly,
 	"merchant":   BehaviorMerchant,
 	"guard":      BehaviorGuard,
+	"smith":      BehaviorSmith,
+	"banker":     BehaviorBanker,
 }
 
 type LootDrop struct {
-	ItemID   string
-	Chance   float64 // 0.0 to 1.0
-	MinCount int
-	MaxCount int
+	ItemID    string
+	Chance    float64 // 0.0 to 1.0
+	MinCount  int
+	MaxCount  int
+	MinRarity Rarity // Equipment drops are rolled at this rarity or better
 }
 
 type NPCTemplate struct {
@@ -46,35 +52,70 @@ type NPCTemplate struct {
 	Behavior    NPCBehavior
 	Dialogue    []string // Random things they might say
 	RespawnTime time.Duration
-	Stationary  bool       // If true, NPC will not wander between areas
-	LootTable   []LootDrop // Possible items this NPC can drop
+	Stationary  bool          // If true, NPC will not wander between areas
+	LootTable   []LootDrop    // Possible items this NPC can drop
+	Shop        *ShopTemplate // Wares for sale, nil if the NPC doesn't trade
+	RepairRate  float64       // Fraction of an item's value a smith charges to fully repair it
+	Ratings     CombatRatings // How well the NPC attacks and defends
+	DamageType  DamageType    // What kind of damage the NPC's attacks do, bludgeon unless set
+	Resistances Resistances
 }
 
 // JSON structs for loading
 type lootDropJSON struct {
-	ItemID   string  `json:"item_id"`
-	Chance   float64 `json:"chance"`
-	MinCount int     `json:"min_count"`
-	MaxCount int     `json:"max_count"`
+	ItemID    string  `json:"item_id"`
+	Chance    float64 `json:"chance"`
+	MinCount  int     `json:"min_count"`
+	MaxCount  int     `json:"max_count"`
+	MinRarity string  `json:"min_rarity,omitempty"`
+}
+
+type shopStockJSON struct {
+	ItemID   string `json:"item_id"`
+	Quantity int    `json:"quantity"`
+}
+
+type shopJSON struct {
+	Markup         float64         `json:"markup"`
+	Markdown       float64         `json:"markdown"`
+	RestockSeconds int             `json:"restock_seconds"`
+	Stock          []shopStockJSON `json:"stock"`
 }
 
 type npcTemplateJSON struct {
-	ID                 string         `json:"id"`
-	Name               string         `json:"name"`
-	Description        string         `json:"description"`
-	Health             int            `json:"health"`
-	MinDamage          int            `json:"min_damage"`
-	MaxDamage          int            `json:"max_damage"`
-	Behavior           string         `json:"behavior"`
-	Dialogue           []string       `json:"dialogue"`
-	RespawnTimeSeconds int            `json:"respawn_time_seconds"`
-	Stationary         bool           `json:"stationary,omitempty"`
-	LootTable          []lootDropJSON `json:"loot_table"`
+	ID                 string             `json:"id"`
+	Name               string             `json:"name"`
+	Description        string             `json:"description"`
+	Health             int                `json:"health"`
+	MinDamage          int                `json:"min_damage"`
+	MaxDamage          int                `json:"max_damage"`
+	Behavior           string             `json:"behavior"`
+	Dialogue           []string           `json:"dialogue"`
+	RespawnTimeSeconds int                `json:"respawn_time_seconds"`
+	Stationary         bool               `json:"stationary,omitempty"`
+	LootTable          []lootDropJSON     `json:"loot_table"`
+	Shop               *shopJSON          `json:"shop,omitempty"`
+	RepairRate         float64            `json:"repair_rate,omitempty"`
+	AttackRating       int                `json:"attack_rating,omitempty"`
+	DefenseRating      int                `json:"defense_rating,omitempty"`
+	Dodge              *int               `json:"dodge,omitempty"`
+	Parry              int                `json:"parry,omitempty"`
+	Block              int                `json:"block,omitempty"`
+	DamageType         string             `json:"damage_type,omitempty"`
+	Resistances        map[string]float64 `json:"resistances,omitempty"`
 }
 
+// Ratings an NPC fights with when its template leaves them out. Beasts
+// neither parry nor block unless their template says so.
+const (
+	defaultNPCRating = 10
+	defaultNPCDodge  = 5
+	defaultNPCCrit   = 5
+)
+
 var NPCTemplates = make(map[string]NPCTemplate)
 
-func LoadNPCTemplates(filename string) error {
+func (res *resourceSet) loadNPCTemplates(filename string) error {
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return err
@@ -86,13 +127,48 @@ func LoadNPCTemplates(filename string) error {
 	}
 
 	for _, t := range templates {
+		if t.MinDamage < 0 || t.MinDamage > t.MaxDamage {
+			return fmt.Errorf("NPC %s: invalid damage range %d-%d", t.ID, t.MinDamage, t.MaxDamage)
+		}
+
 		lootTable := make([]LootDrop, len(t.LootTable))
 		for i, l := range t.LootTable {
+			minRarity := RarityCommon
+			if l.MinRarity != "" {
+				r, ok := rarityFromString[l.MinRarity]
+				if !ok {
+					return fmt.Errorf("NPC %s: unknown rarity %q in loot table", t.ID, l.MinRarity)
+				}
+				minRarity = r
+			}
+
 			lootTable[i] = LootDrop{
-				ItemID:   l.ItemID,
-				Chance:   l.Chance,
-				MinCount: l.MinCount,
-				MaxCount: l.MaxCount,
+				ItemID:    l.ItemID,
+				Chance:    l.Chance,
+				MinCount:  l.MinCount,
+				MaxCount:  l.MaxCount,
+				MinRarity: minRarity,
+			}
+		}
+
+		var shop *ShopTemplate
+		if t.Shop != nil {
+			stock := make([]ShopStockTemplate, len(t.Shop.Stock))
+			for i, s := range t.Shop.Stock {
+				stock[i] = ShopStockTemplate{
+					ItemID:   s.ItemID,
+					Quantity: s.Quantity,
+				}
+			}
+
+			shop = &ShopTemplate{
+				Markup:      t.Shop.Markup,
+				Markdown:    t.Shop.Markdown,
+				RestockTime: time.Duration(t.Shop.RestockSeconds) * time.Second,
+				Stock:       stock,
+			}
+			if err := shop.validate(); err != nil {
+				return fmt.Errorf("NPC %s: %v", t.ID, err)
 			}
 		}
 
@@ -102,7 +178,40 @@ func LoadNPCTemplates(filename string) error {
 			behavior = BehaviorPassive
 		}
 
-		NPCTemplates[t.ID] = NPCTemplate{
+		ratings := CombatRatings{
+			Attack:  t.AttackRating,
+			Defense: t.DefenseRating,
+			Dodge:   defaultNPCDodge,
+			Parry:   t.Parry,
+			Block:   t.Block,
+			Crit:    defaultNPCCrit,
+		}
+		if ratings.Attack == 0 {
+			ratings.Attack = defaultNPCRating
+		}
+		if ratings.Defense == 0 {
+			ratings.Defense = defaultNPCRating
+		}
+		if t.Dodge != nil {
+			ratings.Dodge = *t.Dodge
+		}
+
+		// NPCs fight bare-handed unless their template says otherwise
+		damageType := UnarmedDamageType
+		if t.DamageType != "" {
+			parsed, ok := ParseDamageType(t.DamageType)
+			if !ok {
+				return fmt.Errorf("NPC %s: unknown damage type %q", t.ID, t.DamageType)
+			}
+			damageType = parsed
+		}
+
+		resistances, err := parseResistances(t.Resistances)
+		if err != nil {
+			return fmt.Errorf("NPC %s: %v", t.ID, err)
+		}
+
+		res.npcs[t.ID] = NPCTemplate{
 			ID:          t.ID,
 			Name:        t.Name,
 			Description: t.Description,
@@ -114,6 +223,11 @@ func LoadNPCTemplates(filename string) error {
 			RespawnTime: time.Duration(t.RespawnTimeSeconds) * time.Second,
 			Stationary:  t.Stationary,
 			LootTable:   lootTable,
+			Shop:        shop,
+			RepairRate:  t.RepairRate,
+			Ratings:     ratings,
+			DamageType:  damageType,
+			Resistances: resistances,
 		}
 	}
 
@@ -122,7 +236,7 @@ func LoadNPCTemplates(filename string) error {
 }
 
 // GenerateLoot creates inventory items based on the NPC's loot table
-func GenerateLoot(templateID string) *Inventory {
+func GenerateLoot(rng *rand.Rand, templateID string) *Inventory {
 	template, exists := NPCTemplates[templateID]
 	if !exists {
 		return NewInventory(0)
@@ -132,16 +246,19 @@ func GenerateLoot(templateID string) *Inventory {
 
 	for _, lootDrop := range template.LootTable {
 		// Roll for chance
-		if rand.Float64() <= lootDrop.Chance {
+		if rng.Float64() <= lootDrop.Chance {
 			// Determine quantity
 			count := lootDrop.MinCount
 			if lootDrop.MaxCount > lootDrop.MinCount {
-				count = lootDrop.MinCount + rand.Intn(lootDrop.MaxCount-lootDrop.MinCount+1)
+				count = lootDrop.MinCount + rng.Intn(lootDrop.MaxCount-lootDrop.MinCount+1)
 			}
 
-			// Create and add item
+			// Create and add item, rolling a rarity if it is equipment
 			item := CreateItem(lootDrop.ItemID, count)
 			if item != nil {
+				if item.Slot != SlotNone {
+					ApplyRarity(rng, item, RollRarity(rng, lootDrop.MinRarity))
+				}
 				inventory.AddItem(item)
 			}
 		}
@@ -16,6 +16,7 @@ type Player struct {
 	Client         common.Client
 	CommandHistory *CommandHistory
 	Name           string
+	Account        string // Name the player registered or logged in with
 }
 
 func (p *Player) Broadcast(msg string) {
@@ -72,6 +73,14 @@ func (p *Player) BroadcastState(w WorldLike, entityID common.EntityID) {
 	h.RUnlock()
 
 	stateMsg := fmt.Sprintf("STATE|HP:%d/%d|LEVEL:%d|XP:%d/%d|AREA:%s", currentHP, maxHP, level, currentXP, requiredXP, areaName)
+	if mana, err := w.GetComponent(entityID, "Mana"); err == nil {
+		current, max := mana.(*Mana).Get()
+		stateMsg += fmt.Sprintf("|MANA:%d/%d", current, max)
+	}
+	if stamina, err := w.GetComponent(entityID, "Stamina"); err == nil {
+		current, max := stamina.(*Stamina).Get()
+		stateMsg += fmt.Sprintf("|STAMINA:%d/%d", current, max)
+	}
 	if effectsStr != "" {
 		stateMsg += "|EFFECTS:" + effectsStr
 	}
@@ -83,7 +92,7 @@ func (p *Player) Look(w WorldLike) {
 }
 
 // DescribeArea returns information about the player's current area, including
-// other players, NPCs, and exits.
+// other players, NPCs, items on the ground, and exits.
 func (p *Player) DescribeArea(w WorldLike) string {
 	if p.Area == nil {
 		return "You are nowhere."
@@ -104,8 +113,9 @@ func (p *Player) DescribeArea(w WorldLike) string {
 
 	npcs := p.Area.GetNPCs(w)
 	corpses := p.Area.GetCorpses(w)
+	groundItems := p.Area.GetGroundItems()
 
-	hasEntities := len(otherPlayers) > 0 || len(npcs) > 0 || len(corpses) > 0
+	hasEntities := len(otherPlayers) > 0 || len(npcs) > 0 || len(corpses) > 0 || len(groundItems) > 0
 	if hasEntities {
 		b.WriteString("\n\n")
 		for _, name := range otherPlayers {
@@ -122,6 +132,11 @@ func (p *Player) DescribeArea(w WorldLike) string {
 			b.WriteString(corpse.GetDescription())
 			b.WriteString(" is here.\n")
 		}
+
+		for _, item := range groundItems {
+			b.WriteString(item.DisplayName())
+			b.WriteString(" is lying here.\n")
+		}
 	}
 
 	if len(p.Area.Exits) > 0 {
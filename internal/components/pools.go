@@ -0,0 +1,146 @@
+package components
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Resources abilities can be paid from.
+const (
+	ResourceMana    = "mana"
+	ResourceStamina = "stamina"
+)
+
+const (
+	// DefaultRegenTick is how often health, mana and stamina regenerate.
+	DefaultRegenTick = 5 * time.Second
+
+	// HealthRegenRate and PoolRegenRate are the share of the maximum
+	// restored each tick while standing.
+	HealthRegenRate = 0.02
+	PoolRegenRate   = 0.05
+
+	// Regeneration is multiplied by these while resting, asleep or fighting.
+	RestingRegenMultiplier  = 2.0
+	SleepingRegenMultiplier = 3.0
+	CombatRegenMultiplier   = 0.5
+
+	// BasePool is a level 1 character's mana and stamina before attributes.
+	BasePool = 50
+	// PoolPerAttribute is the mana or stamina each point of a governing
+	// attribute's modifier adds or takes away.
+	PoolPerAttribute = 3
+)
+
+// Pool is a resource that is spent and regenerates over time.
+type Pool struct {
+	sync.RWMutex
+
+	Current int
+	Max     int
+}
+
+// Get returns the current and maximum amounts.
+func (p *Pool) Get() (int, int) {
+	p.RLock()
+	defer p.RUnlock()
+	return p.Current, p.Max
+}
+
+// Spend takes amount from the pool, returning false without taking anything
+// if there isn't enough.
+func (p *Pool) Spend(amount int) bool {
+	p.Lock()
+	defer p.Unlock()
+	if p.Current < amount {
+		return false
+	}
+	p.Current -= amount
+	return true
+}
+
+// Restore adds up to amount without going over the maximum, returning how
+// much was added.
+func (p *Pool) Restore(amount int) int {
+	p.Lock()
+	defer p.Unlock()
+	if p.Current+amount > p.Max {
+		amount = p.Max - p.Current
+	}
+	if amount < 0 {
+		return 0
+	}
+	p.Current += amount
+	return amount
+}
+
+// SetMax changes the maximum, filling the pool if refill is set and
+// otherwise never leaving it above the new maximum.
+func (p *Pool) SetMax(max int, refill bool) {
+	p.Lock()
+	defer p.Unlock()
+	p.Max = max
+	if refill || p.Current > p.Max {
+		p.Current = p.Max
+	}
+}
+
+// Mana powers spells.
+type Mana struct {
+	Pool
+}
+
+func NewMana(max int) *Mana {
+	return &Mana{Pool{Current: max, Max: max}}
+}
+
+func (m *Mana) Type() string {
+	return "Mana"
+}
+
+// Stamina powers physical techniques.
+type Stamina struct {
+	Pool
+}
+
+func NewStamina(max int) *Stamina {
+	return &Stamina{Pool{Current: max, Max: max}}
+}
+
+func (s *Stamina) Type() string {
+	return "Stamina"
+}
+
+// PlayerMaxMana grows with level, Intelligence and Wisdom.
+func PlayerMaxMana(level int, attributes *Attributes) int {
+	bonus := attributes.Modifier(Intelligence) + attributes.Modifier(Wisdom)
+	return poolMax(level, bonus)
+}
+
+// PlayerMaxStamina grows with level, Constitution and Dexterity.
+func PlayerMaxStamina(level int, attributes *Attributes) int {
+	bonus := attributes.Modifier(Constitution) + attributes.Modifier(Dexterity)
+	return poolMax(level, bonus)
+}
+
+func poolMax(level, modifiers int) int {
+	max := int(float64(BasePool)*GetLevelScaling(level)) + modifiers*PoolPerAttribute
+	if max < 0 {
+		max = 0
+	}
+	return max
+}
+
+// RegenAmount is how much of max comes back in one tick at rate, scaled by
+// multiplier. Anything that regenerates at all gets at least one point.
+func RegenAmount(max int, rate, multiplier float64) int {
+	if max <= 0 {
+		return 0
+	}
+	amount := int(math.Round(float64(max) * rate * multiplier))
+	if amount < 1 {
+		amount = 1
+	}
+	return amount
+}
@@ -0,0 +1,138 @@
+package components
+
+import (
+	"dmud/internal/common"
+	"sync"
+	"time"
+)
+
+const (
+	// SafeFlag marks an area where players can't fight each other.
+	SafeFlag = "safe"
+
+	// PvPToggleCooldown is how long a player must wait between changes to
+	// their PvP flag, so nobody can flip it off the moment a fight turns.
+	PvPToggleCooldown = 5 * time.Minute
+
+	// DuelChallengeTimeout is how long a duel challenge stays open.
+	DuelChallengeTimeout = time.Minute
+
+	// DuelTimeout is how long a duel can last before it is called a draw.
+	DuelTimeout = 5 * time.Minute
+)
+
+// PvP is a player's choice to fight other players. It starts off.
+type PvP struct {
+	sync.RWMutex
+
+	Enabled   bool
+	ChangedAt time.Time
+}
+
+func NewPvP() *PvP {
+	return &PvP{}
+}
+
+func (p *PvP) Type() string {
+	return "PvP"
+}
+
+func (p *PvP) IsEnabled() bool {
+	p.RLock()
+	defer p.RUnlock()
+	return p.Enabled
+}
+
+// CooldownRemaining is how long until the flag can be changed again.
+func (p *PvP) CooldownRemaining(now time.Time) time.Duration {
+	p.RLock()
+	defer p.RUnlock()
+	if p.ChangedAt.IsZero() {
+		return 0
+	}
+	remaining := p.ChangedAt.Add(PvPToggleCooldown).Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+func (p *PvP) Set(enabled bool, now time.Time) {
+	p.Lock()
+	defer p.Unlock()
+	p.Enabled = enabled
+	p.ChangedAt = now
+}
+
+// Duel tracks a player's open challenge and the opponent they are dueling.
+// Duels end when one side is beaten down to 1 health, never in death, or
+// when either leaves the area the duel is fought in.
+type Duel struct {
+	sync.RWMutex
+
+	ChallengedBy common.EntityID
+	ChallengedAt time.Time
+	Opponent     common.EntityID
+	Arena        *Area // Where the duel is being fought
+	StartedAt    time.Time
+}
+
+func NewDuel() *Duel {
+	return &Duel{}
+}
+
+func (d *Duel) Type() string {
+	return "Duel"
+}
+
+// Challenge records a challenge from challenger, replacing any older one.
+func (d *Duel) Challenge(challenger common.EntityID, now time.Time) {
+	d.Lock()
+	defer d.Unlock()
+	d.ChallengedBy = challenger
+	d.ChallengedAt = now
+}
+
+// HasChallengeFrom reports whether challenger's challenge is still open.
+func (d *Duel) HasChallengeFrom(challenger common.EntityID, now time.Time) bool {
+	d.RLock()
+	defer d.RUnlock()
+	return d.ChallengedBy == challenger && now.Sub(d.ChallengedAt) < DuelChallengeTimeout
+}
+
+func (d *Duel) GetOpponent() common.EntityID {
+	d.RLock()
+	defer d.RUnlock()
+	return d.Opponent
+}
+
+// Start begins a duel against opponent in arena, closing any open challenge.
+func (d *Duel) Start(opponent common.EntityID, arena *Area, now time.Time) {
+	d.Lock()
+	defer d.Unlock()
+	d.Opponent = opponent
+	d.Arena = arena
+	d.StartedAt = now
+	d.ChallengedBy = ""
+}
+
+// GetArena returns the area the duel is being fought in.
+func (d *Duel) GetArena() *Area {
+	d.RLock()
+	defer d.RUnlock()
+	return d.Arena
+}
+
+// TimedOut reports whether the duel has run past DuelTimeout.
+func (d *Duel) TimedOut(now time.Time) bool {
+	d.RLock()
+	defer d.RUnlock()
+	return d.Opponent != "" && now.Sub(d.StartedAt) >= DuelTimeout
+}
+
+func (d *Duel) End() {
+	d.Lock()
+	defer d.Unlock()
+	d.Opponent = ""
+	d.Arena = nil
+}
@@ -15,9 +15,12 @@ type QuestRequirement struct {
 	Quantity int
 }
 
+// QuestReward grants either Quantity of ItemID or, when Currency is set,
+// that many copper straight into the player's wallet.
 type QuestReward struct {
 	ItemID   string
 	Quantity int
+	Currency int
 }
 
 type Quest struct {
@@ -115,7 +118,7 @@ var QuestRegistry = map[string]*Quest{
 			{ItemID: "leather_chest", Quantity: 1},
 			{ItemID: "leather_legs", Quantity: 1},
 			{ItemID: "leather_boots", Quantity: 1},
-			{ItemID: "gold_coin", Quantity: 50},
+			{Currency: 50},
 		},
 		NPCID: "merchant",
 	},
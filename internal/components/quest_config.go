@@ -277,27 +277,35 @@ func (h *QuestDialogueHandler) completeQuest(player *Player, playerEntityID comm
 
 	// Remove required items
 	for _, req := range questDef.Requirements {
-		inventory.RemoveItem(req.ItemID, req.Quantity)
+		inventory.RemoveQuantity(req.ItemID, req.Quantity)
+	}
+
+	var wallet *Wallet
+	if walletComp, err := h.World.GetComponent(playerEntityID, "Wallet"); err == nil {
+		wallet = walletComp.(*Wallet)
 	}
 
 	// Give rewards
 	for _, reward := range questDef.Rewards {
-		template, exists := ItemTemplates[reward.ItemID]
-		if !exists {
+		if reward.Currency > 0 {
+			if wallet == nil {
+				log.Error().Msgf("Player %s has no wallet for quest %s reward", player.Name, questDef.ID)
+				continue
+			}
+			wallet.Deposit(reward.Currency, "reward for quest "+questDef.Name, h.World.Now())
+			player.Broadcast(fmt.Sprintf("You received: %s", FormatCurrency(reward.Currency)))
+			continue
+		}
+
+		item := CreateItem(reward.ItemID, reward.Quantity)
+		if item == nil {
 			log.Error().Msgf("Item template %s not found", reward.ItemID)
 			continue
 		}
 
-		item := &Item{
-			ID:          template.ID,
-			Name:        template.Name,
-			Description: template.Description,
-			Type:        template.Type,
-			Value:       template.Value,
-			Stackable:   template.Stackable,
-			Quantity:    reward.Quantity,
+		if wallet == nil || !wallet.Collect(item, "reward for quest "+questDef.Name, h.World.Now()) {
+			inventory.AddItem(item)
 		}
-		inventory.AddItem(item)
 		player.Broadcast(fmt.Sprintf("You received: %s x%d", item.Name, reward.Quantity))
 	}
 
@@ -0,0 +1,97 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RecipeItem is a quantity of an item consumed or produced by a recipe.
+type RecipeItem struct {
+	ItemID   string
+	Quantity int
+}
+
+type Recipe struct {
+	ID               string
+	Name             string
+	Inputs           []RecipeItem
+	Outputs          []RecipeItem
+	Tool             string  // Item that must be carried but isn't used up
+	Station          string  // Area flag required, such as "campfire"
+	SkillLevel       int     // Crafting skill needed to attempt the recipe
+	SuccessChance    float64 // 0.0 to 1.0 at the required skill level
+	XP               int     // Crafting XP for a successful attempt
+	RequiresLearning bool    // If true, the recipe must be taught before use
+}
+
+type recipeItemJSON struct {
+	ItemID   string `json:"item_id"`
+	Quantity int    `json:"quantity"`
+}
+
+type recipeJSON struct {
+	ID               string           `json:"id"`
+	Name             string           `json:"name"`
+	Inputs           []recipeItemJSON `json:"inputs"`
+	Outputs          []recipeItemJSON `json:"outputs"`
+	Tool             string           `json:"tool,omitempty"`
+	Station          string           `json:"station,omitempty"`
+	SkillLevel       int              `json:"skill_level"`
+	SuccessChance    float64          `json:"success_chance"`
+	XP               int              `json:"xp"`
+	RequiresLearning bool             `json:"requires_learning,omitempty"`
+}
+
+// Recipes holds every crafting recipe, keyed by ID.
+var Recipes = make(map[string]*Recipe)
+
+func (res *resourceSet) loadRecipes(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var recipes []recipeJSON
+	if err := json.Unmarshal(data, &recipes); err != nil {
+		return err
+	}
+
+	for _, r := range recipes {
+		if len(r.Inputs) == 0 || len(r.Outputs) == 0 {
+			return fmt.Errorf("recipe %s: needs at least one input and one output", r.ID)
+		}
+		if r.SuccessChance <= 0 || r.SuccessChance > 1 {
+			return fmt.Errorf("recipe %s: success_chance must be in (0, 1]", r.ID)
+		}
+
+		res.recipes[r.ID] = &Recipe{
+			ID:               r.ID,
+			Name:             r.Name,
+			Inputs:           toRecipeItems(r.Inputs),
+			Outputs:          toRecipeItems(r.Outputs),
+			Tool:             r.Tool,
+			Station:          r.Station,
+			SkillLevel:       r.SkillLevel,
+			SuccessChance:    r.SuccessChance,
+			XP:               r.XP,
+			RequiresLearning: r.RequiresLearning,
+		}
+	}
+
+	log.Info().Msgf("Loaded %d recipes from %s", len(recipes), filename)
+	return nil
+}
+
+func toRecipeItems(items []recipeItemJSON) []RecipeItem {
+	result := make([]RecipeItem, len(items))
+	for i, item := range items {
+		result[i] = RecipeItem{
+			ItemID:   item.ItemID,
+			Quantity: item.Quantity,
+		}
+	}
+	return result
+}
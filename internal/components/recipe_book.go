@@ -0,0 +1,54 @@
+package components
+
+import (
+	"sort"
+	"sync"
+)
+
+// RecipeBook holds the crafting recipes a player has learned.
+type RecipeBook struct {
+	sync.RWMutex
+
+	Known map[string]bool
+}
+
+func NewRecipeBook() *RecipeBook {
+	return &RecipeBook{
+		Known: make(map[string]bool),
+	}
+}
+
+func (rb *RecipeBook) Type() string {
+	return "RecipeBook"
+}
+
+// Learn adds a recipe, returning false if it was already known.
+func (rb *RecipeBook) Learn(recipeID string) bool {
+	rb.Lock()
+	defer rb.Unlock()
+
+	if rb.Known[recipeID] {
+		return false
+	}
+	rb.Known[recipeID] = true
+	return true
+}
+
+func (rb *RecipeBook) Knows(recipeID string) bool {
+	rb.RLock()
+	defer rb.RUnlock()
+	return rb.Known[recipeID]
+}
+
+// GetKnown returns the IDs of every learned recipe, sorted.
+func (rb *RecipeBook) GetKnown() []string {
+	rb.RLock()
+	defer rb.RUnlock()
+
+	known := make([]string, 0, len(rb.Known))
+	for id := range rb.Known {
+		known = append(known, id)
+	}
+	sort.Strings(known)
+	return known
+}
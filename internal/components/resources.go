@@ -0,0 +1,74 @@
+package components
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// resourceSet holds the templates read by one LoadResources call until they
+// have all loaded and checked out.
+type resourceSet struct {
+	items      map[string]*Item
+	npcs       map[string]NPCTemplate
+	affixes    map[string]*Affix
+	abilities  map[string]*Ability
+	races      map[string]*Race
+	raceOrder  []string
+	classes    map[string]*Class
+	classOrder []string
+	recipes    map[string]*Recipe
+}
+
+// LoadResources loads every template file the game needs from dir, builds
+// the quest dialogues and checks that all item references resolve. Files are
+// loaded in dependency order: abilities before the classes that teach them,
+// and everything before the item reference check. The templates are only
+// swapped in once all of them have loaded, so a failed load leaves the ones
+// already in use alone.
+func LoadResources(dir string) error {
+	res := &resourceSet{
+		items:     make(map[string]*Item),
+		npcs:      make(map[string]NPCTemplate),
+		affixes:   make(map[string]*Affix),
+		abilities: make(map[string]*Ability),
+		races:     make(map[string]*Race),
+		classes:   make(map[string]*Class),
+		recipes:   make(map[string]*Recipe),
+	}
+
+	loaders := []struct {
+		what string
+		file string
+		load func(string) error
+	}{
+		{"item templates", "items.json", res.loadItemTemplates},
+		{"NPC templates", "npcs.json", res.loadNPCTemplates},
+		{"affixes", "affixes.json", res.loadAffixes},
+		{"abilities", "abilities.json", res.loadAbilities},
+		{"races", "races.json", res.loadRaces},
+		{"classes", "classes.json", res.loadClasses},
+		{"recipes", "recipes.json", res.loadRecipes},
+	}
+
+	for _, l := range loaders {
+		if err := l.load(filepath.Join(dir, l.file)); err != nil {
+			return fmt.Errorf("loading %s: %w", l.what, err)
+		}
+	}
+
+	// Every loot drop, quest and recipe item must resolve to an item template
+	if err := res.validateItemReferences(); err != nil {
+		return fmt.Errorf("invalid item references: %w", err)
+	}
+
+	ItemTemplates = res.items
+	NPCTemplates = res.npcs
+	Affixes = res.affixes
+	Abilities = res.abilities
+	Races, RaceOrder = res.races, res.raceOrder
+	Classes, ClassOrder = res.classes, res.classOrder
+	Recipes = res.recipes
+
+	InitializeQuests()
+	return nil
+}
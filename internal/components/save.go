@@ -0,0 +1,121 @@
+package components
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+)
+
+// savedItem is how an item is written to disk. Items are rebuilt from their
+// template, then the parts rolled at drop time are restored on top.
+type savedItem struct {
+	TemplateID string            `json:"template_id"`
+	Name       string            `json:"name"`
+	Quantity   int               `json:"quantity"`
+	Value      int               `json:"value"`
+	Rarity     string            `json:"rarity,omitempty"`
+	Affixes    []string          `json:"affixes,omitempty"`
+	Modifiers  statModifiersJSON `json:"modifiers"`
+	Durability int               `json:"durability,omitempty"`
+	Contents   []savedItem       `json:"contents,omitempty"`
+}
+
+func saveItems(items []*Item) []savedItem {
+	saved := make([]savedItem, 0, len(items))
+	for _, item := range items {
+		item.RLock()
+		s := savedItem{
+			TemplateID: item.ID,
+			Name:       item.Name,
+			Quantity:   item.Quantity,
+			Value:      item.Value,
+			Affixes:    item.Affixes,
+			Modifiers: statModifiersJSON{
+				MinDamage: item.Modifiers.MinDamage,
+				MaxDamage: item.Modifiers.MaxDamage,
+				Armor:     item.Modifiers.Armor,
+				MaxHP:     item.Modifiers.MaxHP,
+			},
+			Durability: item.Durability,
+		}
+		if item.Rarity != RarityCommon {
+			s.Rarity = item.Rarity.String()
+		}
+		container := item.Container
+		item.RUnlock()
+
+		if container != nil {
+			s.Contents = saveItems(container.GetItems())
+		}
+		saved = append(saved, s)
+	}
+	return saved
+}
+
+// restoreItems rebuilds saved items, dropping any whose template no longer
+// exists.
+func restoreItems(saved []savedItem, owner string) []*Item {
+	items := make([]*Item, 0, len(saved))
+	for _, s := range saved {
+		item := CreateItem(s.TemplateID, s.Quantity)
+		if item == nil {
+			log.Warn().Msgf("Dropping unknown item %s saved for %s", s.TemplateID, owner)
+			continue
+		}
+
+		item.Name = s.Name
+		item.Value = s.Value
+		item.Rarity = rarityFromString[s.Rarity]
+		item.Affixes = s.Affixes
+		item.Modifiers = StatModifiers{
+			MinDamage: s.Modifiers.MinDamage,
+			MaxDamage: s.Modifiers.MaxDamage,
+			Armor:     s.Modifiers.Armor,
+			MaxHP:     s.Modifiers.MaxHP,
+		}
+		if item.MaxDurability > 0 {
+			item.Durability = s.Durability
+		}
+		if item.Container != nil {
+			for _, content := range restoreItems(s.Contents, owner) {
+				item.Container.AddItem(content)
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// writeJSON saves v to path, writing a temporary file first so a crash can't
+// leave half a file behind.
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readJSON loads path into v, returning false if there is no such file.
+func readJSON(path string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, v)
+}
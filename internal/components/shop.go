@@ -0,0 +1,164 @@
+package components
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ShopStockTemplate is one line of a merchant's wares as defined in npcs.json.
+type ShopStockTemplate struct {
+	ItemID   string
+	Quantity int // How many the merchant holds when fully stocked
+}
+
+// ShopTemplate describes what a merchant sells and how they price it.
+type ShopTemplate struct {
+	Markup      float64 // Multiplier on Item.Value when players buy
+	Markdown    float64 // Multiplier on Item.Value when players sell
+	RestockTime time.Duration
+	Stock       []ShopStockTemplate
+}
+
+// validate rejects prices a player could exploit: a merchant must charge
+// something, and never pay more for an item than they sell it for, or
+// buying and selling it back would print money.
+func (t *ShopTemplate) validate() error {
+	if t.Markup <= 0 {
+		return fmt.Errorf("shop markup %v must be positive", t.Markup)
+	}
+	if t.Markdown < 0 {
+		return fmt.Errorf("shop markdown %v must not be negative", t.Markdown)
+	}
+	if t.Markdown > t.Markup {
+		return fmt.Errorf("shop markdown %v is above its markup %v", t.Markdown, t.Markup)
+	}
+	return nil
+}
+
+type ShopStock struct {
+	ItemID      string
+	Quantity    int
+	MaxQuantity int
+}
+
+type Shop struct {
+	sync.RWMutex
+
+	Markup      float64
+	Markdown    float64
+	RestockTime time.Duration
+	Stock       []*ShopStock
+	LastRestock time.Time
+}
+
+func NewShop(template *ShopTemplate, now time.Time) *Shop {
+	stock := make([]*ShopStock, len(template.Stock))
+	for i, s := range template.Stock {
+		stock[i] = &ShopStock{
+			ItemID:      s.ItemID,
+			Quantity:    s.Quantity,
+			MaxQuantity: s.Quantity,
+		}
+	}
+
+	return &Shop{
+		Markup:      template.Markup,
+		Markdown:    template.Markdown,
+		RestockTime: template.RestockTime,
+		Stock:       stock,
+		LastRestock: now,
+	}
+}
+
+func (s *Shop) Type() string {
+	return "Shop"
+}
+
+// BuyPrice is what a player pays the merchant for one of the item.
+func (s *Shop) BuyPrice(item *Item) int {
+	s.RLock()
+	defer s.RUnlock()
+
+	price := int(math.Ceil(float64(item.Value) * s.Markup))
+	if price < 1 {
+		price = 1
+	}
+	return price
+}
+
+// SellPrice is what the merchant pays a player for one of the item. Zero
+// means the merchant won't buy it.
+func (s *Shop) SellPrice(item *Item) int {
+	s.RLock()
+	defer s.RUnlock()
+
+	return int(math.Round(float64(item.Value) * s.Markdown))
+}
+
+// GetStock returns a snapshot of the merchant's wares.
+func (s *Shop) GetStock() []ShopStock {
+	s.RLock()
+	defer s.RUnlock()
+
+	stock := make([]ShopStock, len(s.Stock))
+	for i, entry := range s.Stock {
+		stock[i] = *entry
+	}
+	return stock
+}
+
+// TakeStock removes one of itemID from the merchant's wares, returning false
+// if they have sold out.
+func (s *Shop) TakeStock(itemID string) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, entry := range s.Stock {
+		if entry.ItemID == itemID && entry.Quantity > 0 {
+			entry.Quantity--
+			return true
+		}
+	}
+	return false
+}
+
+// ReturnStock puts items a player sold back on the shelf if the merchant
+// deals in them, never holding more than their usual quantity, so selling
+// can't stock a shop beyond what it restocks to.
+func (s *Shop) ReturnStock(itemID string, quantity int) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, entry := range s.Stock {
+		if entry.ItemID == itemID {
+			entry.Quantity += quantity
+			if entry.Quantity > entry.MaxQuantity {
+				entry.Quantity = entry.MaxQuantity
+			}
+			return
+		}
+	}
+}
+
+// Restock brings every ware that is below its usual quantity up by one once
+// RestockTime has passed since the last restock.
+func (s *Shop) Restock(now time.Time) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.RestockTime <= 0 || now.Sub(s.LastRestock) < s.RestockTime {
+		return false
+	}
+	s.LastRestock = now
+
+	restocked := false
+	for _, entry := range s.Stock {
+		if entry.Quantity < entry.MaxQuantity {
+			entry.Quantity++
+			restocked = true
+		}
+	}
+	return restocked
+}
@@ -9,6 +9,9 @@ type StatusEffectType int
 
 const (
 	StatusEffectGuardBlessing StatusEffectType = iota
+	StatusEffectItem                           // Granted by using an item; identified by Name
+	StatusEffectStunned                        // Loses combat rounds until it wears off
+	StatusEffectWeakened                       // Deals less damage after returning from death
 )
 
 type StatusEffect struct {
@@ -18,6 +21,8 @@ type StatusEffect struct {
 	Duration  time.Duration
 	HPBonus   int
 	Applied   bool
+
+	Resistances Resistances
 }
 
 type StatusEffects struct {
@@ -36,7 +41,7 @@ func (se *StatusEffects) AddEffect(effect StatusEffect) {
 	defer se.Unlock()
 
 	for i, existing := range se.Effects {
-		if existing.Type == effect.Type {
+		if existing.Type == effect.Type && existing.Name == effect.Name {
 			se.Effects[i] = effect
 			return
 		}
@@ -50,7 +55,20 @@ func (se *StatusEffects) HasEffect(effectType StatusEffectType) bool {
 	defer se.RUnlock()
 
 	for _, effect := range se.Effects {
-		if effect.Type == effectType && !se.isExpired(effect) {
+		if effect.Type == effectType {
+			return true
+		}
+	}
+	return false
+}
+
+// HasNamedEffect reports whether an effect called name is active.
+func (se *StatusEffects) HasNamedEffect(name string) bool {
+	se.RLock()
+	defer se.RUnlock()
+
+	for _, effect := range se.Effects {
+		if effect.Name == name {
 			return true
 		}
 	}
@@ -62,14 +80,17 @@ func (se *StatusEffects) GetEffect(effectType StatusEffectType) (*StatusEffect,
 	defer se.RUnlock()
 
 	for i := range se.Effects {
-		if se.Effects[i].Type == effectType && !se.isExpired(se.Effects[i]) {
+		if se.Effects[i].Type == effectType {
 			return &se.Effects[i], true
 		}
 	}
 	return nil, false
 }
 
-func (se *StatusEffects) RemoveExpired() []StatusEffect {
+// RemoveExpired drops the effects that have run out by now and returns them.
+// The StatusEffectSystem calls it every tick, so every effect still listed
+// counts as active.
+func (se *StatusEffects) RemoveExpired(now time.Time) []StatusEffect {
 	se.Lock()
 	defer se.Unlock()
 
@@ -77,7 +98,7 @@ func (se *StatusEffects) RemoveExpired() []StatusEffect {
 	var active []StatusEffect
 
 	for _, effect := range se.Effects {
-		if se.isExpired(effect) {
+		if effect.Duration != 0 && now.Sub(effect.AppliedAt) >= effect.Duration {
 			removed = append(removed, effect)
 		} else {
 			active = append(active, effect)
@@ -88,22 +109,33 @@ func (se *StatusEffects) RemoveExpired() []StatusEffect {
 	return removed
 }
 
-func (se *StatusEffects) isExpired(effect StatusEffect) bool {
-	if effect.Duration == 0 {
-		return false
-	}
-	return time.Since(effect.AppliedAt) >= effect.Duration
-}
-
 func (se *StatusEffects) GetTotalHPBonus() int {
 	se.RLock()
 	defer se.RUnlock()
 
 	total := 0
 	for _, effect := range se.Effects {
-		if !se.isExpired(effect) {
-			total += effect.HPBonus
-		}
+		total += effect.HPBonus
 	}
 	return total
 }
+
+// Resistances combines the resistances granted by every active effect.
+func (se *StatusEffects) Resistances() Resistances {
+	se.RLock()
+	defer se.RUnlock()
+
+	var resistances Resistances
+	for _, effect := range se.Effects {
+		resistances = resistances.Combine(effect.Resistances)
+	}
+	return resistances
+}
+
+// DamageMultiplier scales the damage an entity deals for its effects.
+func (se *StatusEffects) DamageMultiplier() float64 {
+	if se.HasEffect(StatusEffectWeakened) {
+		return WeakenedDamageMultiplier
+	}
+	return 1
+}
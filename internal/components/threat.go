@@ -0,0 +1,97 @@
+package components
+
+import (
+	"dmud/internal/common"
+	"sync"
+	"time"
+)
+
+// HealingThreatMultiplier is how much threat each point of healing draws
+// from the healed character's foes, compared to a point of damage.
+const HealingThreatMultiplier = 0.5
+
+// Threat is an NPC's hate for everyone who has fought it. The NPC attacks
+// whoever has built up the most, unless someone has taunted it.
+type Threat struct {
+	sync.RWMutex
+
+	Table      map[common.EntityID]int
+	Taunter    common.EntityID
+	TauntUntil time.Time
+}
+
+func NewThreat() *Threat {
+	return &Threat{Table: make(map[common.EntityID]int)}
+}
+
+func (t *Threat) Type() string {
+	return "Threat"
+}
+
+// Add builds up id's threat by amount.
+func (t *Threat) Add(id common.EntityID, amount int) {
+	t.Lock()
+	defer t.Unlock()
+	t.Table[id] += amount
+}
+
+func (t *Threat) Get(id common.EntityID) int {
+	t.RLock()
+	defer t.RUnlock()
+	return t.Table[id]
+}
+
+// Has reports whether id is on the threat table at all.
+func (t *Threat) Has(id common.EntityID) bool {
+	t.RLock()
+	defer t.RUnlock()
+	_, ok := t.Table[id]
+	return ok
+}
+
+// Taunt raises id to the top of the table and holds the NPC's attention on
+// them for duration.
+func (t *Threat) Taunt(id common.EntityID, duration time.Duration, now time.Time) {
+	t.Lock()
+	defer t.Unlock()
+	for _, amount := range t.Table {
+		if amount > t.Table[id] {
+			t.Table[id] = amount
+		}
+	}
+	if _, ok := t.Table[id]; !ok {
+		t.Table[id] = 0
+	}
+	t.Taunter = id
+	t.TauntUntil = now.Add(duration)
+}
+
+// Target picks who the NPC should be attacking: a taunter while the taunt
+// lasts, otherwise whoever has the most threat. The current target keeps
+// the NPC's attention on a tie. Anyone valid rejects is dropped from the
+// table, and "" means nobody is left.
+func (t *Threat) Target(current common.EntityID, valid func(common.EntityID) bool, now time.Time) common.EntityID {
+	t.Lock()
+	defer t.Unlock()
+
+	for id := range t.Table {
+		if !valid(id) {
+			delete(t.Table, id)
+		}
+	}
+
+	if _, ok := t.Table[t.Taunter]; ok && now.Before(t.TauntUntil) {
+		return t.Taunter
+	}
+
+	best := common.EntityID("")
+	if _, ok := t.Table[current]; ok {
+		best = current
+	}
+	for id, amount := range t.Table {
+		if best == "" || amount > t.Table[best] {
+			best = id
+		}
+	}
+	return best
+}
@@ -0,0 +1,211 @@
+package components
+
+import (
+	"dmud/internal/common"
+	"sync"
+)
+
+// TradeItem is an item put up in a trade. Key is the item's Key in its
+// owner's inventory, so it still refers to the same item when the trade
+// goes through. Durability and Contents record the item as it was offered,
+// so a blade that has worn down or a bag that has been filled since can be
+// caught before it changes hands.
+type TradeItem struct {
+	Key        string
+	Name       string
+	Quantity   int
+	Durability int
+	Contents   int
+}
+
+// NewTradeItem puts quantity of item up for trade as it is now.
+func NewTradeItem(item *Item, quantity int) TradeItem {
+	offered := TradeItem{Key: item.Key(), Quantity: quantity}
+
+	item.RLock()
+	offered.Name = item.Name
+	offered.Durability = item.Durability
+	container := item.Container
+	item.RUnlock()
+
+	if container != nil {
+		offered.Contents = len(container.GetItems())
+	}
+	return offered
+}
+
+// Changed reports whether item is no longer as it was when offered.
+func (t TradeItem) Changed(item *Item) bool {
+	now := NewTradeItem(item, t.Quantity)
+	return now.Durability != t.Durability || now.Contents != t.Contents
+}
+
+// TradeOffer is what one side of a trade is putting up.
+type TradeOffer struct {
+	Items    []TradeItem
+	Copper   int
+	Accepted bool
+}
+
+// TradeSession is a trade between two players. Nothing changes hands until
+// both have accepted the current offers; any change to either offer clears
+// both acceptances so nobody can be caught out by a last-second swap.
+type TradeSession struct {
+	sync.RWMutex
+
+	Players [2]common.EntityID
+	Names   [2]string
+	Offers  [2]*TradeOffer
+}
+
+func NewTradeSession(a common.EntityID, aName string, b common.EntityID, bName string) *TradeSession {
+	return &TradeSession{
+		Players: [2]common.EntityID{a, b},
+		Names:   [2]string{aName, bName},
+		Offers:  [2]*TradeOffer{{}, {}},
+	}
+}
+
+// side returns 0 or 1 for the two participants.
+func (ts *TradeSession) side(id common.EntityID) int {
+	if ts.Players[1] == id {
+		return 1
+	}
+	return 0
+}
+
+// Partner returns the other participant's entity ID and name.
+func (ts *TradeSession) Partner(id common.EntityID) (common.EntityID, string) {
+	other := 1 - ts.side(id)
+	return ts.Players[other], ts.Names[other]
+}
+
+// GetOffer returns a copy of the offer made by id.
+func (ts *TradeSession) GetOffer(id common.EntityID) TradeOffer {
+	ts.RLock()
+	defer ts.RUnlock()
+
+	offer := ts.Offers[ts.side(id)]
+	items := make([]TradeItem, len(offer.Items))
+	copy(items, offer.Items)
+	return TradeOffer{Items: items, Copper: offer.Copper, Accepted: offer.Accepted}
+}
+
+// OfferItem adds an item to id's offer. Offering more of a stack that is
+// already on the table raises the quantity instead.
+func (ts *TradeSession) OfferItem(id common.EntityID, item TradeItem) {
+	ts.Lock()
+	defer ts.Unlock()
+
+	offer := ts.Offers[ts.side(id)]
+	ts.resetAcceptance()
+
+	for i, existing := range offer.Items {
+		if existing.Key == item.Key {
+			offer.Items[i].Quantity += item.Quantity
+			return
+		}
+	}
+	offer.Items = append(offer.Items, item)
+}
+
+// UpdateItem replaces the record of an offered item with how it is now,
+// clearing both acceptances since the offer is no longer what was agreed.
+func (ts *TradeSession) UpdateItem(id common.EntityID, item TradeItem) {
+	ts.Lock()
+	defer ts.Unlock()
+
+	offer := ts.Offers[ts.side(id)]
+	for i, existing := range offer.Items {
+		if existing.Key == item.Key {
+			offer.Items[i] = item
+			ts.resetAcceptance()
+			return
+		}
+	}
+}
+
+// OfferedQuantity is how many of the item with key id has already offered.
+func (ts *TradeSession) OfferedQuantity(id common.EntityID, key string) int {
+	ts.RLock()
+	defer ts.RUnlock()
+
+	for _, item := range ts.Offers[ts.side(id)].Items {
+		if item.Key == key {
+			return item.Quantity
+		}
+	}
+	return 0
+}
+
+// RetractItem takes an item back out of id's offer, returning false if it
+// wasn't offered.
+func (ts *TradeSession) RetractItem(id common.EntityID, key string) bool {
+	ts.Lock()
+	defer ts.Unlock()
+
+	offer := ts.Offers[ts.side(id)]
+	for i, item := range offer.Items {
+		if item.Key == key {
+			offer.Items = append(offer.Items[:i], offer.Items[i+1:]...)
+			ts.resetAcceptance()
+			return true
+		}
+	}
+	return false
+}
+
+// SetCopper sets how much money id is offering.
+func (ts *TradeSession) SetCopper(id common.EntityID, copper int) {
+	ts.Lock()
+	defer ts.Unlock()
+
+	ts.Offers[ts.side(id)].Copper = copper
+	ts.resetAcceptance()
+}
+
+// Accept marks id as happy with both offers as they stand, returning true
+// once both players have accepted.
+func (ts *TradeSession) Accept(id common.EntityID) bool {
+	ts.Lock()
+	defer ts.Unlock()
+
+	ts.Offers[ts.side(id)].Accepted = true
+	return ts.Offers[0].Accepted && ts.Offers[1].Accepted
+}
+
+// ResetAcceptance clears both players' acceptance, as when a trade fails to
+// go through and has to be agreed again.
+func (ts *TradeSession) ResetAcceptance() {
+	ts.Lock()
+	defer ts.Unlock()
+	ts.resetAcceptance()
+}
+
+func (ts *TradeSession) resetAcceptance() {
+	ts.Offers[0].Accepted = false
+	ts.Offers[1].Accepted = false
+}
+
+// Trade is a player's part in trading: an invitation they have sent, or the
+// session they are in.
+type Trade struct {
+	sync.RWMutex
+
+	Invited common.EntityID // Player this one has asked to trade with
+	Session *TradeSession
+}
+
+func NewTrade() *Trade {
+	return &Trade{}
+}
+
+func (t *Trade) Type() string {
+	return "Trade"
+}
+
+func (t *Trade) GetSession() *TradeSession {
+	t.RLock()
+	defer t.RUnlock()
+	return t.Session
+}
@@ -0,0 +1,118 @@
+package components
+
+import (
+	"net/url"
+	"path/filepath"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// VaultSlots is how many items or stacks a bank vault holds.
+const VaultSlots = 30
+
+// Vault is a player's bank storage. It isn't part of their inventory, so
+// nothing in it goes into their corpse when they die.
+type Vault struct {
+	sync.RWMutex
+
+	Owner  string // Account the vault belongs to
+	Items  *Inventory
+	Copper int
+}
+
+func (v *Vault) Balance() int {
+	v.RLock()
+	defer v.RUnlock()
+	return v.Copper
+}
+
+// Deposit adds amount to the vault, returning false without changing
+// anything unless amount is positive.
+func (v *Vault) Deposit(amount int) bool {
+	if amount <= 0 {
+		return false
+	}
+
+	v.Lock()
+	defer v.Unlock()
+	v.Copper += amount
+	return true
+}
+
+// Withdraw takes amount out of the vault, returning false without changing
+// anything if amount isn't positive or there isn't enough.
+func (v *Vault) Withdraw(amount int) bool {
+	if amount <= 0 {
+		return false
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	if v.Copper < amount {
+		return false
+	}
+	v.Copper -= amount
+	return true
+}
+
+type savedVault struct {
+	Copper int         `json:"copper"`
+	Items  []savedItem `json:"items"`
+}
+
+// VaultStore keeps the vault of every account. Vaults belong to the account
+// a player logged in with, so whatever is kept there is still waiting when
+// they come back with their password, whatever they have renamed themselves
+// to since. When Dir is set each vault is saved there as JSON, so vaults
+// outlast a restart.
+type VaultStore struct {
+	sync.Mutex
+
+	Dir    string
+	vaults map[string]*Vault
+}
+
+func NewVaultStore(dir string) *VaultStore {
+	return &VaultStore{Dir: dir, vaults: make(map[string]*Vault)}
+}
+
+// Get returns account's vault, loading it from disk the first time it is
+// used.
+func (vs *VaultStore) Get(account string) *Vault {
+	vs.Lock()
+	defer vs.Unlock()
+
+	key := AccountKey(account)
+	if vault, ok := vs.vaults[key]; ok {
+		return vault
+	}
+
+	vault := &Vault{Owner: key, Items: NewInventory(VaultSlots)}
+	if vs.Dir != "" {
+		var saved savedVault
+		if _, err := readJSON(vs.path(key), &saved); err != nil {
+			log.Error().Err(err).Msgf("Error loading the vault of %s", key)
+		}
+		vault.Copper = saved.Copper
+		vault.Items.Items = append(vault.Items.Items, restoreItems(saved.Items, key)...)
+	}
+	vs.vaults[key] = vault
+	return vault
+}
+
+// Save writes vault to disk.
+func (vs *VaultStore) Save(vault *Vault) error {
+	if vs.Dir == "" {
+		return nil
+	}
+	saved := savedVault{Copper: vault.Balance(), Items: saveItems(vault.Items.GetItems())}
+	return writeJSON(vs.path(vault.Owner), saved)
+}
+
+// path is where account's vault is saved. Names are escaped so any name
+// maps to its own file inside Dir.
+func (vs *VaultStore) path(account string) string {
+	return filepath.Join(vs.Dir, url.PathEscape(account)+".json")
+}
@@ -0,0 +1,178 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Currency is counted in copper. Item.Value and shop prices are in copper too.
+const (
+	CopperPerSilver = 10
+	SilverPerGold   = 10
+	CopperPerGold   = CopperPerSilver * SilverPerGold
+)
+
+// MaxCurrency is the most copper a single amount of money can be. It is far
+// more than the game pays out, and small enough that adding a few amounts
+// together can't overflow.
+const MaxCurrency = 1_000_000_000
+
+var (
+	ErrUnknownDenomination = errors.New("unknown denomination")
+	ErrCurrencyOutOfRange  = errors.New("amount of money out of range")
+)
+
+// maxWalletHistory is how many transactions a wallet remembers.
+const maxWalletHistory = 20
+
+var copperPerDenomination = map[string]int{
+	"copper": 1,
+	"silver": CopperPerSilver,
+	"gold":   CopperPerGold,
+}
+
+// WalletTransaction is one entry in a wallet's audit trail. Amount is
+// negative for money leaving the wallet.
+type WalletTransaction struct {
+	Time        time.Time
+	Amount      int
+	Description string
+}
+
+type Wallet struct {
+	sync.RWMutex
+
+	Copper  int
+	History []WalletTransaction
+}
+
+func NewWallet() *Wallet {
+	return &Wallet{}
+}
+
+func (w *Wallet) Type() string {
+	return "Wallet"
+}
+
+func (w *Wallet) Balance() int {
+	w.RLock()
+	defer w.RUnlock()
+	return w.Copper
+}
+
+// Deposit adds amount to the wallet, returning false without changing
+// anything unless amount is positive.
+func (w *Wallet) Deposit(amount int, description string, now time.Time) bool {
+	if amount <= 0 {
+		return false
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	w.Copper += amount
+	w.record(amount, description, now)
+	return true
+}
+
+// Withdraw takes amount out of the wallet, returning false without changing
+// anything if amount isn't positive or there isn't enough.
+func (w *Wallet) Withdraw(amount int, description string, now time.Time) bool {
+	if amount <= 0 {
+		return false
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	if w.Copper < amount {
+		return false
+	}
+
+	w.Copper -= amount
+	w.record(-amount, description, now)
+	return true
+}
+
+// Collect converts a currency item into wallet funds. It returns false for
+// items that aren't currency, which belong in the inventory instead.
+func (w *Wallet) Collect(item *Item, source string, now time.Time) bool {
+	item.RLock()
+	isCurrency := item.Type == ItemTypeCurrency
+	amount := item.Value * item.Quantity
+	item.RUnlock()
+
+	if !isCurrency {
+		return false
+	}
+
+	w.Deposit(amount, source, now)
+	return true
+}
+
+// GetHistory returns the wallet's recent transactions, oldest first.
+func (w *Wallet) GetHistory() []WalletTransaction {
+	w.RLock()
+	defer w.RUnlock()
+
+	history := make([]WalletTransaction, len(w.History))
+	copy(history, w.History)
+	return history
+}
+
+func (w *Wallet) record(amount int, description string, now time.Time) {
+	w.History = append(w.History, WalletTransaction{
+		Time:        now,
+		Amount:      amount,
+		Description: description,
+	})
+	if len(w.History) > maxWalletHistory {
+		w.History = w.History[len(w.History)-maxWalletHistory:]
+	}
+}
+
+// ParseCurrency converts an amount of a named denomination such as "gold"
+// into copper. It fails with ErrUnknownDenomination if denomination isn't
+// one, and ErrCurrencyOutOfRange unless the amount comes to between 1 copper
+// and MaxCurrency.
+func ParseCurrency(amount int, denomination string) (int, error) {
+	denomination = strings.TrimSuffix(strings.ToLower(denomination), "s")
+	perCoin, ok := copperPerDenomination[denomination]
+	if !ok {
+		return 0, ErrUnknownDenomination
+	}
+	// Checked before multiplying, so a huge count can't wrap around
+	if amount <= 0 || amount > MaxCurrency/perCoin {
+		return 0, ErrCurrencyOutOfRange
+	}
+	return amount * perCoin, nil
+}
+
+// FormatCurrency renders copper as gold, silver and copper, e.g. "1 gold, 5 copper".
+func FormatCurrency(copper int) string {
+	if copper == 0 {
+		return "0 copper"
+	}
+
+	sign := ""
+	if copper < 0 {
+		sign = "-"
+		copper = -copper
+	}
+
+	var parts []string
+	if gold := copper / CopperPerGold; gold > 0 {
+		parts = append(parts, fmt.Sprintf("%d gold", gold))
+	}
+	if silver := copper % CopperPerGold / CopperPerSilver; silver > 0 {
+		parts = append(parts, fmt.Sprintf("%d silver", silver))
+	}
+	if rest := copper % CopperPerSilver; rest > 0 {
+		parts = append(parts, fmt.Sprintf("%d copper", rest))
+	}
+
+	return sign + strings.Join(parts, ", ")
+}
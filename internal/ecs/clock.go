@@ -0,0 +1,68 @@
+package ecs
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Clock tells a world what time it is.
+type Clock interface {
+	Now() time.Time
+}
+
+// WallClock is the real time, used by live games.
+type WallClock struct{}
+
+func (WallClock) Now() time.Time {
+	return time.Now()
+}
+
+// ManualClock only moves when it is set, so a re-fed session can step the
+// world through the timeline it was recorded with.
+type ManualClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.now
+}
+
+// Set moves the clock to t.
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// lockedSource lets the world's random numbers be drawn from any goroutine,
+// such as a test spawning NPCs while the game loop runs.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// newRand returns a random source of its own, so seeding one world doesn't
+// disturb any other.
+func newRand(seed int64) *rand.Rand {
+	return rand.New(&lockedSource{src: rand.NewSource(seed)})
+}
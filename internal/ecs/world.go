@@ -2,8 +2,11 @@ package ecs
 
 import (
 	"fmt"
+	"math/rand"
 	"reflect"
+	"sort"
 	"sync"
+	"time"
 
 	"dmud/internal/common"
 	"dmud/internal/components"
@@ -19,11 +22,41 @@ type World struct {
 	entities    map[common.EntityID]Entity
 	entityMutex sync.RWMutex
 
+	// order numbers entities as they are added, so queries return them in
+	// the same order every run whatever their IDs
+	order     map[common.EntityID]uint64
+	nextOrder uint64
+
+	clock      Clock
+	rand       *rand.Rand
+	lastUpdate time.Time
+
 	elapsedTime float64
 
 	systems []System
 }
 
+// Now is the world's current time. Everything that times itself against the
+// world, such as combat rounds, cooldowns and decay, reads it from here.
+func (w *World) Now() time.Time {
+	return w.clock.Now()
+}
+
+// SetClock makes the world tell time by clock.
+func (w *World) SetClock(clock Clock) {
+	w.clock = clock
+}
+
+// Rand is the world's random source, used for every roll in the game.
+func (w *World) Rand() *rand.Rand {
+	return w.rand
+}
+
+// Seed restarts the world's random source from seed.
+func (w *World) Seed(seed int64) {
+	w.rand = newRand(seed)
+}
+
 func (w *World) AddComponent(entity *Entity, component Component) {
 	// Always acquire locks in the same order: entityMutex first, then componentMutex
 	w.entityMutex.Lock()
@@ -54,6 +87,10 @@ func (w *World) AddEntity(entity Entity) {
 
 	w.entities[entity.ID] = entity
 	w.components[entity.ID] = make(map[string]Component)
+	if _, ok := w.order[entity.ID]; !ok {
+		w.order[entity.ID] = w.nextOrder
+		w.nextOrder++
+	}
 
 	log.Info().Msgf("Added entity %s", entity.ID)
 }
@@ -97,6 +134,10 @@ func (w *World) FindEntitiesByComponentPredicate(componentType string, predicate
 		return nil, nil
 	}
 
+	sort.Slice(entities, func(i, j int) bool {
+		return w.order[entities[i].ID] < w.order[entities[j].ID]
+	})
+
 	return entities, nil
 }
 
@@ -161,6 +202,7 @@ func (w *World) RemoveEntity(entityID common.EntityID) {
 
 	w.entityMutex.Lock()
 	delete(w.entities, entityID)
+	delete(w.order, entityID)
 	w.entityMutex.Unlock()
 
 	w.componentMutex.Lock()
@@ -171,7 +213,12 @@ func (w *World) RemoveEntity(entityID common.EntityID) {
 }
 
 func (w *World) Update() {
-	deltaTime := util.CalculateDeltaTime()
+	now := w.Now()
+	var deltaTime float64
+	if !w.lastUpdate.IsZero() {
+		deltaTime = now.Sub(w.lastUpdate).Seconds()
+	}
+	w.lastUpdate = now
 
 	w.elapsedTime += deltaTime
 
@@ -185,18 +232,30 @@ func (w *World) Update() {
 }
 
 func NewWorld() *World {
+	return NewWorldFromFile("./resources/areas.json")
+}
+
+// NewWorldFromFile builds a world whose areas are loaded from the given JSON file.
+func NewWorldFromFile(areasFile string) *World {
 	world := &World{
 		entities:   make(map[common.EntityID]Entity),
 		components: make(map[common.EntityID]map[string]Component),
+		order:      make(map[common.EntityID]uint64),
+		clock:      WallClock{},
+		rand:       newRand(time.Now().UnixNano()),
 	}
 
-	areas := loadAreasFromFile("./resources/areas.json")
+	areas := loadAreasFromFile(areasFile)
 
 	for _, area := range areas {
 		areaEntity := NewEntity(area.ID)
 		areaComponent := &components.Area{
 			Region:      area.Region,
 			Description: area.Description,
+			Flags:       make(map[string]bool),
+		}
+		for _, flag := range area.Flags {
+			areaComponent.Flags[flag] = true
 		}
 		world.AddEntity(areaEntity)
 		world.AddComponent(&areaEntity, areaComponent)
@@ -215,7 +274,16 @@ func NewWorld() *World {
 			continue
 		}
 
-		for direction, areaID := range area.Exits {
+		// Map order changes from run to run; sorted exits list and roll the
+		// same way every time
+		directions := make([]string, 0, len(area.Exits))
+		for direction := range area.Exits {
+			directions = append(directions, direction)
+		}
+		sort.Strings(directions)
+
+		for _, direction := range directions {
+			areaID := area.Exits[direction]
 			exitAreaUntyped, err := world.GetComponent(common.EntityID(areaID), "Area")
 			if err != nil {
 				log.Error().Err(err).Msgf("Could not get Area for exit area %s", areaID)
@@ -244,6 +312,7 @@ type areaDefinition struct {
 	Region      string            `json:"region"`
 	Description string            `json:"description"`
 	Exits       map[string]string `json:"exits"`
+	Flags       []string          `json:"flags,omitempty"`
 }
 
 func loadAreasFromFile(filename string) []areaDefinition {
@@ -0,0 +1,497 @@
+package game
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"dmud/internal/systems"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// abilityTarget is someone an ability is used on.
+type abilityTarget struct {
+	ID     common.EntityID
+	Name   string
+	Player *components.Player // Set when the target is a player
+}
+
+// registerAbilityCommands gives every loaded ability its own command, so a
+// warrior can just type "bash rat". Abilities never replace a built-in
+// command.
+func (g *Game) registerAbilityCommands() {
+	ids := make([]string, 0, len(components.Abilities))
+	for id := range components.Abilities {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if _, exists := g.commands[id]; exists {
+			log.Warn().Msgf("Ability %s clashes with a command of the same name", id)
+			continue
+		}
+		ability := components.Abilities[id]
+		g.RegisterCommand(&Command{
+			Name:        id,
+			Handler:     g.createAbilityHandler(ability),
+			Description: ability.Description,
+			Hidden:      true,
+		})
+	}
+}
+
+func (g *Game) createAbilityHandler(ability *components.Ability) CommandHandler {
+	return func(player *components.Player, args []string, game *Game) {
+		g.useAbility(player, ability, strings.Join(args, " "))
+	}
+}
+
+// useAbility checks a player can use ability right now, works out who it
+// hits and applies its effects to each of them.
+func (g *Game) useAbility(player *components.Player, ability *components.Ability, targetName string) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	skills, err := ecs.GetTypedComponent[*components.Skills](g.world, playerEntity, "Skills")
+	if err != nil || !skills.Knows(ability.ID) {
+		player.Broadcast(fmt.Sprintf("You don't know how to %s.", ability.ID))
+		return
+	}
+
+	if remaining := skills.CooldownRemaining(ability.ID, g.world.Now()); remaining > 0 {
+		player.Broadcast(fmt.Sprintf("You can't use %s again for %s.", ability.Name, formatCooldown(remaining)))
+		return
+	}
+
+	if ability.IsOffensive() && g.isDown(player, playerEntity) {
+		return
+	}
+
+	if ability.Opener && g.inCombat(playerEntity) {
+		player.Broadcast(fmt.Sprintf("You can only %s to start a fight.", ability.ID))
+		return
+	}
+
+	targets, ok := g.abilityTargets(player, playerEntity, ability, targetName)
+	if !ok {
+		return
+	}
+
+	if !g.payAbilityCost(player, playerEntity, ability) {
+		return
+	}
+	skills.StartCooldown(ability.ID, ability.Cooldown, g.world.Now())
+
+	var survivors []common.EntityID
+	for _, target := range targets {
+		if g.applyAbility(player, playerEntity, ability, target) {
+			systems.ResolveDeath(g.world, playerEntity, target.ID)
+			continue
+		}
+		if target.Player != nil {
+			target.Player.BroadcastState(g.world.AsWorldLike(), target.ID)
+		}
+		// A duel settled by this hit is over, so there's no fight to carry on
+		if ability.IsOffensive() && (target.Player == nil || systems.PvPRefusal(g.world, player.Area, playerEntity, target.ID, target.Name) == "") {
+			survivors = append(survivors, target.ID)
+		}
+	}
+
+	if len(survivors) > 0 {
+		g.engage(playerEntity, survivors)
+	}
+	player.BroadcastState(g.world.AsWorldLike(), playerEntity)
+}
+
+// abilityTargets finds who an ability will be used on, telling the player
+// why if there is no one suitable.
+func (g *Game) abilityTargets(player *components.Player, playerEntity common.EntityID, ability *components.Ability, targetName string) ([]abilityTarget, bool) {
+	switch ability.Target {
+	case components.TargetSelf:
+		return []abilityTarget{{ID: playerEntity, Name: player.Name, Player: player}}, true
+
+	case components.TargetArea:
+		var targets []abilityTarget
+		for _, npc := range player.Area.GetNPCs(g.world.AsWorldLike()) {
+			if id, ok := g.npcEntityID(npc); ok {
+				targets = append(targets, abilityTarget{ID: id, Name: npc.Name})
+			}
+		}
+		if len(targets) == 0 {
+			player.Broadcast("There's nothing here to attack.")
+			return nil, false
+		}
+		return targets, true
+	}
+
+	var target abilityTarget
+	if targetName == "" {
+		current, ok := g.currentTarget(player, playerEntity)
+		if !ok {
+			player.Broadcast(ability.Name + " whom?")
+			return nil, false
+		}
+		target = current
+	} else {
+		found, ok := g.findFoe(player, targetName)
+		if !ok {
+			player.Broadcast("They aren't here.")
+			return nil, false
+		}
+		target = found
+	}
+
+	if target.Player != nil && !g.canAttackPlayer(player, playerEntity, target) {
+		return nil, false
+	}
+	return []abilityTarget{target}, true
+}
+
+// currentTarget is whoever the player is fighting, if they are still here.
+func (g *Game) currentTarget(player *components.Player, playerEntity common.EntityID) (abilityTarget, bool) {
+	combat, err := ecs.GetTypedComponent[*components.Combat](g.world, playerEntity, "Combat")
+	if err != nil {
+		return abilityTarget{}, false
+	}
+	combat.RLock()
+	targetID := combat.TargetID
+	combat.RUnlock()
+	if targetID == "" {
+		return abilityTarget{}, false
+	}
+
+	if npc, err := ecs.GetTypedComponent[*components.NPC](g.world, targetID, "NPC"); err == nil && npc.Area == player.Area {
+		return abilityTarget{ID: targetID, Name: npc.Name}, true
+	}
+	if target, err := ecs.GetTypedComponent[*components.Player](g.world, targetID, "Player"); err == nil && target.Area == player.Area {
+		return abilityTarget{ID: targetID, Name: target.Name, Player: target}, true
+	}
+	return abilityTarget{}, false
+}
+
+// findFoe matches name against the NPCs and other players in the player's
+// area.
+func (g *Game) findFoe(player *components.Player, name string) (abilityTarget, bool) {
+	name = strings.ToLower(name)
+	for _, npc := range player.Area.GetNPCs(g.world.AsWorldLike()) {
+		if strings.Contains(strings.ToLower(npc.Name), name) {
+			if id, ok := g.npcEntityID(npc); ok {
+				return abilityTarget{ID: id, Name: npc.Name}, true
+			}
+		}
+	}
+
+	return g.findPlayerHere(player, name)
+}
+
+// findPlayerHere matches name against the other players in the player's
+// area.
+func (g *Game) findPlayerHere(player *components.Player, name string) (abilityTarget, bool) {
+	name = strings.ToLower(name)
+	player.Area.PlayersMutex.RLock()
+	others := make([]*components.Player, 0, len(player.Area.Players))
+	for _, other := range player.Area.Players {
+		if other != player && strings.ToLower(other.Name) == name {
+			others = append(others, other)
+		}
+	}
+	player.Area.PlayersMutex.RUnlock()
+
+	for _, other := range others {
+		if id, err := g.getPlayerEntity(other); err == nil {
+			return abilityTarget{ID: id, Name: other.Name, Player: other}, true
+		}
+	}
+	return abilityTarget{}, false
+}
+
+func (g *Game) npcEntityID(npc *components.NPC) (common.EntityID, bool) {
+	entities, _ := g.world.FindEntitiesByComponentPredicate("NPC", func(i interface{}) bool {
+		n, ok := i.(*components.NPC)
+		return ok && n == npc
+	})
+	if len(entities) == 0 {
+		return "", false
+	}
+	return entities[0].ID, true
+}
+
+// applyAbility applies each of an ability's effects to target, returning
+// true if the ability killed them.
+func (g *Game) applyAbility(player *components.Player, playerEntity common.EntityID, ability *components.Ability, target abilityTarget) bool {
+	health, err := ecs.GetTypedComponent[*components.Health](g.world, target.ID, "Health")
+	if err != nil {
+		return false
+	}
+	attributes, _ := ecs.GetTypedComponent[*components.Attributes](g.world, playerEntity, "Attributes")
+	bonus := ability.ScalingBonus(attributes)
+	self := target.ID == playerEntity
+	name := strings.ToLower(ability.Name)
+
+	for _, effect := range ability.Effects {
+		switch effect.Type {
+		case components.AbilityEffectDamage:
+			damage := g.abilityDamage(playerEntity, effect) + bonus
+			damageType := g.abilityDamageType(playerEntity, effect)
+			resistance := components.ResistanceMultiplier(g.world.AsWorldLike(), target.ID, damageType)
+			damage = int(math.Round(float64(damage) * resistance))
+			if damage < 1 {
+				damage = 1
+			}
+			health.Lock()
+			health.Current -= damage
+			health.Unlock()
+
+			details := systems.DamageDetails(damage, components.DescribeResistance(damageType, resistance))
+			player.Broadcast(fmt.Sprintf("Your %s hits %s. %s", name, target.Name, details))
+			if target.Player != nil {
+				target.Player.Broadcast(fmt.Sprintf("%s's %s hits you. %s", player.Name, name, details))
+			}
+			player.Area.Broadcast(fmt.Sprintf("%s's %s hits %s.", player.Name, name, target.Name), player, target.Player)
+			systems.AddThreat(g.world, target.ID, playerEntity, damage)
+			systems.ResolveDuelHit(g.world, playerEntity, target.ID)
+
+		case components.AbilityEffectHeal:
+			amount := g.rollBetween(effect.Min, effect.Max) + bonus
+			if amount < 1 {
+				amount = 1
+			}
+			health.Lock()
+			healed := health.Max - health.Current
+			if amount < healed {
+				healed = amount
+			}
+			health.Current += healed
+			health.Unlock()
+
+			if self {
+				player.Broadcast(fmt.Sprintf("Your %s restores %d health.", name, healed))
+				player.Area.Broadcast(fmt.Sprintf("%s is surrounded by a soft glow.", player.Name), player)
+			} else {
+				player.Broadcast(fmt.Sprintf("Your %s restores %d of %s's health.", name, healed, target.Name))
+			}
+			systems.AddHealingThreat(g.world, playerEntity, target.ID, healed)
+
+		case components.AbilityEffectStun:
+			health.RLock()
+			dead := health.Current <= 0
+			health.RUnlock()
+			if dead {
+				continue
+			}
+			g.stun(target.ID, ability.Name, effect.Duration)
+
+			player.Broadcast(fmt.Sprintf("%s is stunned!", target.Name))
+			if target.Player != nil {
+				target.Player.Broadcast("You are stunned!")
+			}
+			player.Area.Broadcast(fmt.Sprintf("%s is stunned!", target.Name), player, target.Player)
+
+		case components.AbilityEffectTaunt:
+			// Players choose their own fights; only NPCs can be goaded
+			if target.Player != nil {
+				continue
+			}
+			systems.Taunt(g.world, target.ID, playerEntity, effect.Duration)
+
+			player.Broadcast(fmt.Sprintf("You taunt %s into attacking you!", target.Name))
+			player.Area.Broadcast(fmt.Sprintf("%s taunts %s.", player.Name, target.Name), player)
+		}
+	}
+
+	health.RLock()
+	defer health.RUnlock()
+	return !self && health.Current <= 0
+}
+
+// abilityDamageType is the kind of damage an effect does: its own, or that
+// of the player's weapon.
+func (g *Game) abilityDamageType(playerEntity common.EntityID, effect components.AbilityEffect) components.DamageType {
+	if effect.HasDamageType {
+		return effect.DamageType
+	}
+	equipment, _ := ecs.GetTypedComponent[*components.Equipment](g.world, playerEntity, "Equipment")
+	return components.WeaponDamageType(equipment)
+}
+
+// abilityDamage rolls an effect's damage: a multiple of the player's weapon
+// damage, or its own range.
+func (g *Game) abilityDamage(playerEntity common.EntityID, effect components.AbilityEffect) int {
+	damage := g.rollBetween(effect.Min, effect.Max)
+	if effect.Multiplier > 0 {
+		minDamage, maxDamage := g.playerDamageRange(playerEntity)
+		damage = int(math.Round(float64(g.rollBetween(minDamage, maxDamage)) * effect.Multiplier))
+	}
+	if statusEffects, err := ecs.GetTypedComponent[*components.StatusEffects](g.world, playerEntity, "StatusEffects"); err == nil {
+		damage = int(float64(damage) * statusEffects.DamageMultiplier())
+	}
+	return damage
+}
+
+// stun stops an entity swinging in combat for duration.
+func (g *Game) stun(entityID common.EntityID, name string, duration time.Duration) {
+	statusEffects, err := ecs.GetTypedComponent[*components.StatusEffects](g.world, entityID, "StatusEffects")
+	if err != nil {
+		entity, err := g.world.FindEntity(entityID)
+		if err != nil {
+			return
+		}
+		statusEffects = components.NewStatusEffects()
+		g.world.AddComponent(&entity, statusEffects)
+	}
+
+	statusEffects.AddEffect(components.StatusEffect{
+		Type:      components.StatusEffectStunned,
+		Name:      name,
+		AppliedAt: g.world.Now(),
+		Duration:  duration,
+	})
+}
+
+// engage puts the player in combat with targets, adding them to the queue
+// behind whoever the player is already fighting.
+func (g *Game) engage(playerEntity common.EntityID, targets []common.EntityID) {
+	combat, err := ecs.GetTypedComponent[*components.Combat](g.world, playerEntity, "Combat")
+	if err != nil {
+		entity, err := g.world.FindEntity(playerEntity)
+		if err != nil {
+			return
+		}
+		minDamage, maxDamage := g.playerDamageRange(playerEntity)
+		g.world.AddComponent(&entity, &components.Combat{
+			TargetID:    targets[0],
+			TargetQueue: targets[1:],
+			MinDamage:   minDamage,
+			MaxDamage:   maxDamage,
+		})
+		return
+	}
+
+	combat.Lock()
+	defer combat.Unlock()
+	for _, id := range targets {
+		if combat.TargetID == "" {
+			combat.TargetID = id
+			continue
+		}
+		if combat.TargetID == id || containsEntity(combat.TargetQueue, id) {
+			continue
+		}
+		combat.TargetQueue = append(combat.TargetQueue, id)
+	}
+}
+
+// inCombat reports whether an entity is fighting someone.
+func (g *Game) inCombat(entityID common.EntityID) bool {
+	combat, err := ecs.GetTypedComponent[*components.Combat](g.world, entityID, "Combat")
+	if err != nil {
+		return false
+	}
+	combat.RLock()
+	defer combat.RUnlock()
+	return combat.TargetID != ""
+}
+
+func (g *Game) handleAbilities(player *components.Player, args []string, game *Game) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	skills, err := ecs.GetTypedComponent[*components.Skills](g.world, playerEntity, "Skills")
+	if err != nil || len(skills.GetKnown()) == 0 {
+		player.Broadcast("You haven't learned any abilities.")
+		return
+	}
+
+	var output strings.Builder
+	output.WriteString("==============================================\n")
+	output.WriteString("                 ABILITIES                    \n")
+	output.WriteString("==============================================\n\n")
+	for _, id := range skills.GetKnown() {
+		ability, ok := components.Abilities[id]
+		if !ok {
+			continue
+		}
+		status := "ready"
+		if remaining := skills.CooldownRemaining(id, g.world.Now()); remaining > 0 {
+			status = formatCooldown(remaining)
+		}
+		output.WriteString(fmt.Sprintf("  %-10s %-6s %-5s cooldown %-4s %s\n",
+			ability.ID, describeAbilityTarget(ability.Target), describeAbilityCost(ability),
+			formatCooldown(ability.Cooldown), status))
+		output.WriteString(fmt.Sprintf("  %-10s %s\n", "", ability.Description))
+	}
+
+	if character, err := ecs.GetTypedComponent[*components.Character](g.world, playerEntity, "Character"); err == nil {
+		var upcoming []string
+		for _, id := range character.Class.Abilities {
+			if ability, ok := components.Abilities[id]; ok && !skills.Knows(id) {
+				upcoming = append(upcoming, fmt.Sprintf("%s (level %d)", ability.ID, ability.Level))
+			}
+		}
+		if len(upcoming) > 0 {
+			output.WriteString(fmt.Sprintf("\n  Still to learn: %s\n", strings.Join(upcoming, ", ")))
+		}
+	}
+	output.WriteString("==============================================\n")
+
+	player.Broadcast(output.String())
+}
+
+func describeAbilityTarget(target components.AbilityTarget) string {
+	switch target {
+	case components.TargetSelf:
+		return "self"
+	case components.TargetArea:
+		return "area"
+	default:
+		return "enemy"
+	}
+}
+
+func describeAbilityCost(ability *components.Ability) string {
+	if ability.Cost == 0 || ability.Resource == "" {
+		return "free"
+	}
+	return fmt.Sprintf("%d %s", ability.Cost, ability.Resource)
+}
+
+// abilityUsage is what follows an ability's name when using it.
+func abilityUsage(ability *components.Ability) string {
+	if ability.Target == components.TargetEnemy {
+		return " [target]"
+	}
+	return ""
+}
+
+// formatCooldown rounds a cooldown up to whole seconds, like "12s".
+func formatCooldown(d time.Duration) string {
+	return fmt.Sprintf("%ds", int(math.Ceil(d.Seconds())))
+}
+
+func (g *Game) rollBetween(min, max int) int {
+	if max <= min {
+		return min
+	}
+	return min + g.world.Rand().Intn(max-min+1)
+}
+
+func containsEntity(ids []common.EntityID, id common.EntityID) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package game
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (g *Game) handleTrain(player *components.Player, args []string, game *Game) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	attributes, err := ecs.GetTypedComponent[*components.Attributes](g.world, playerEntity, "Attributes")
+	if err != nil {
+		player.Broadcast("You have no attributes to train.")
+		return
+	}
+
+	if len(args) == 0 {
+		var output strings.Builder
+		output.WriteString(fmt.Sprintf("You have %d training points.\n", attributes.GetPoints()))
+		output.WriteString(describeAttributes(attributes))
+		output.WriteString("Type 'train <attribute>' to raise one, such as 'train str'.\n")
+		player.Broadcast(output.String())
+		return
+	}
+
+	attr, ok := components.ParseAttribute(args[0])
+	if !ok {
+		player.Broadcast("Train what? Choose strength, dexterity, constitution, intelligence or wisdom.")
+		return
+	}
+
+	if attributes.GetPoints() <= 0 {
+		player.Broadcast("You have no training points. You earn more as you level up.")
+		return
+	}
+	if !attributes.Train(attr) {
+		player.Broadcast(fmt.Sprintf("Your %s can't be trained any higher.", strings.ToLower(attr.String())))
+		return
+	}
+
+	player.Broadcast(fmt.Sprintf("You train your %s to %d. (%d points left)",
+		strings.ToLower(attr.String()), attributes.Get(attr), attributes.GetPoints()))
+
+	switch attr {
+	case components.Strength:
+		g.refreshCombatDamage(playerEntity)
+	case components.Constitution:
+		g.adjustMaxHealth(player, playerEntity, components.HPPerConstitution)
+	}
+	g.refreshPools(playerEntity, false)
+	player.BroadcastState(g.world.AsWorldLike(), playerEntity)
+}
+
+func (g *Game) handleScore(player *components.Player, args []string, game *Game) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	var output strings.Builder
+	output.WriteString("==============================================\n")
+	output.WriteString("                   SCORE                      \n")
+	output.WriteString("==============================================\n\n")
+	character, _ := ecs.GetTypedComponent[*components.Character](g.world, playerEntity, "Character")
+	if character != nil {
+		output.WriteString(fmt.Sprintf("  %s the %s\n", player.Name, character.Describe()))
+	} else {
+		output.WriteString(fmt.Sprintf("  %s\n", player.Name))
+	}
+
+	if experience, err := ecs.GetTypedComponent[*components.Experience](g.world, playerEntity, "Experience"); err == nil {
+		output.WriteString(fmt.Sprintf("  Level %d (%d/%d XP)\n", experience.GetLevel(), experience.GetCurrent(), experience.GetRequiredXP()))
+	}
+	if health, err := ecs.GetTypedComponent[*components.Health](g.world, playerEntity, "Health"); err == nil {
+		health.RLock()
+		output.WriteString(fmt.Sprintf("  Health: %d/%d HP\n", health.Current, health.Max))
+		health.RUnlock()
+	}
+	output.WriteString(fmt.Sprintf("  %s\n", g.describePools(playerEntity)))
+	output.WriteString("\n")
+
+	if attributes, err := ecs.GetTypedComponent[*components.Attributes](g.world, playerEntity, "Attributes"); err == nil {
+		output.WriteString(describeAttributes(attributes))
+		if points := attributes.GetPoints(); points > 0 {
+			output.WriteString(fmt.Sprintf("  Training points: %d\n", points))
+		}
+		output.WriteString("\n")
+	}
+
+	output.WriteString(g.describeCombatStats(playerEntity))
+	if skills, err := ecs.GetTypedComponent[*components.Skills](g.world, playerEntity, "Skills"); err == nil {
+		if known := skills.GetKnown(); len(known) > 0 {
+			output.WriteString(fmt.Sprintf("  Abilities: %s\n", strings.Join(known, ", ")))
+		}
+	}
+	output.WriteString("==============================================\n")
+
+	player.Broadcast(output.String())
+}
+
+// describeAttributes lists a character's attributes one to a line.
+func describeAttributes(attributes *components.Attributes) string {
+	var output strings.Builder
+	for _, attr := range components.AllAttributes {
+		output.WriteString(fmt.Sprintf("  %s  %-13s %2d\n", attr.Abbrev(), attr.String(), attributes.Get(attr)))
+	}
+	return output.String()
+}
+
+// describeCombatStats summarises the numbers a player's attributes and gear
+// work out to.
+func (g *Game) describeCombatStats(entityID common.EntityID) string {
+	level := 1
+	if experience, err := ecs.GetTypedComponent[*components.Experience](g.world, entityID, "Experience"); err == nil {
+		level = experience.GetLevel()
+	}
+	attributes, _ := ecs.GetTypedComponent[*components.Attributes](g.world, entityID, "Attributes")
+	equipment, _ := ecs.GetTypedComponent[*components.Equipment](g.world, entityID, "Equipment")
+
+	minDamage, maxDamage := g.playerDamageRange(entityID)
+	ratings := components.PlayerCombatRatings(level, attributes, equipment)
+	carried, capacity := components.CarryingWeight(g.world.AsWorldLike(), entityID)
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("  Damage: %d-%d   Attack: %d   Defense: %d\n", minDamage, maxDamage, ratings.Attack, ratings.Defense))
+	output.WriteString(fmt.Sprintf("  Dodge: %d%%   Parry: %d%%   Block: %d%%\n", ratings.Dodge, ratings.Parry, ratings.Block))
+	output.WriteString(fmt.Sprintf("  Carrying: %.1f/%.1f\n", carried, capacity))
+	if resistances := components.EntityResistances(g.world.AsWorldLike(), entityID).Describe(); resistances != "" {
+		output.WriteString(fmt.Sprintf("  Resistances: %s\n", resistances))
+	}
+	return output.String()
+}
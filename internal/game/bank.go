@@ -0,0 +1,241 @@
+package game
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// bankVisit is everything a bank command needs: the banker, the player's
+// vault and what they are carrying.
+type bankVisit struct {
+	entityID  common.EntityID
+	npc       *components.NPC
+	vault     *components.Vault
+	inventory *components.Inventory
+	wallet    *components.Wallet
+}
+
+// visitBank looks up the banker in the player's area, telling the player
+// why if banking isn't possible.
+func (g *Game) visitBank(player *components.Player) (*bankVisit, bool) {
+	npc := g.findNPCByBehavior(player, components.BehaviorBanker)
+	if npc == nil {
+		player.Broadcast("There is no banker here.")
+		return nil, false
+	}
+
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return nil, false
+	}
+
+	inventory, err := ecs.GetTypedComponent[*components.Inventory](g.world, playerEntity, "Inventory")
+	if err != nil {
+		player.Broadcast("You don't have an inventory!")
+		return nil, false
+	}
+
+	wallet := g.playerWallet(playerEntity)
+	if wallet == nil {
+		player.Broadcast("You don't have a wallet!")
+		return nil, false
+	}
+
+	return &bankVisit{
+		entityID:  playerEntity,
+		npc:       npc,
+		vault:     g.vaults.Get(player.Account),
+		inventory: inventory,
+		wallet:    wallet,
+	}, true
+}
+
+// saveVault writes a vault to disk after it changes.
+func (g *Game) saveVault(vault *components.Vault) {
+	if err := g.vaults.Save(vault); err != nil {
+		log.Error().Err(err).Msgf("Error saving the vault of %s", vault.Owner)
+	}
+}
+
+// parseMoney reads "<amount> <gold|silver|copper>" into copper. isMoney is
+// false when args don't name an amount of money at all, and err is set when
+// they do but the amount is out of range.
+func parseMoney(args []string) (amount int, isMoney bool, err error) {
+	if len(args) != 2 {
+		return 0, false, nil
+	}
+	count, convErr := strconv.Atoi(args[0])
+	if convErr != nil || count <= 0 {
+		return 0, false, nil
+	}
+	amount, err = components.ParseCurrency(count, args[1])
+	if errors.Is(err, components.ErrUnknownDenomination) {
+		return 0, false, nil
+	}
+	return amount, true, err
+}
+
+func (g *Game) handleBank(player *components.Player, args []string, game *Game) {
+	visit, ok := g.visitBank(player)
+	if !ok {
+		return
+	}
+
+	items := visit.vault.Items.GetItems()
+
+	var output strings.Builder
+	output.WriteString("==============================================\n")
+	output.WriteString("                   VAULT                      \n")
+	output.WriteString("==============================================\n\n")
+
+	if len(items) == 0 {
+		output.WriteString("  Your vault is empty.\n")
+	}
+	for _, item := range items {
+		if item.Stackable && item.Quantity > 1 {
+			output.WriteString(fmt.Sprintf("  %-30s x%d\n", item.Name, item.Quantity))
+		} else {
+			output.WriteString(fmt.Sprintf("  %s\n", item.ColoredName()))
+		}
+	}
+
+	output.WriteString(fmt.Sprintf("\n(%d/%d slots used)\n", len(items), components.VaultSlots))
+	output.WriteString(fmt.Sprintf("Coins: %s\n", components.FormatCurrency(visit.vault.Balance())))
+	output.WriteString("==============================================\n")
+
+	player.Broadcast(output.String())
+}
+
+func (g *Game) handleDeposit(player *components.Player, args []string, game *Game) {
+	if len(args) == 0 {
+		player.Broadcast("Deposit what? Usage: deposit <item>, deposit all, deposit all.<item> or deposit <amount> <gold|silver|copper>")
+		return
+	}
+
+	visit, ok := g.visitBank(player)
+	if !ok {
+		return
+	}
+	npc, vault := visit.npc, visit.vault
+
+	if amount, isMoney, err := parseMoney(args); isMoney {
+		if err != nil {
+			player.Broadcast(currencyLimitMessage())
+			return
+		}
+		formatted := components.FormatCurrency(amount)
+		if !visit.wallet.Withdraw(amount, "deposited at "+npc.Name, g.world.Now()) {
+			player.Broadcast(fmt.Sprintf("You don't have %s.", formatted))
+			return
+		}
+		vault.Deposit(amount)
+		g.saveVault(vault)
+
+		player.Broadcast(fmt.Sprintf("You deposit %s. Your vault holds %s.", formatted, components.FormatCurrency(vault.Balance())))
+		return
+	}
+
+	all, itemName := parseItemSelector(strings.ToLower(strings.Join(args, " ")))
+	targets := matchItems(visit.inventory.GetItems(), itemName, all)
+	if len(targets) == 0 {
+		player.Broadcast("You don't have that item.")
+		return
+	}
+
+	deposited := make([]string, 0, len(targets))
+	for _, item := range targets {
+		removed := visit.inventory.RemoveItem(item.Key(), item.Quantity)
+		if removed == nil {
+			continue
+		}
+		if !vault.Items.AddItem(removed) {
+			visit.inventory.AddItem(removed)
+			player.Broadcast(fmt.Sprintf("%s says: Your vault is full.", npc.Name))
+			break
+		}
+		deposited = append(deposited, removed.DisplayName())
+	}
+
+	if len(deposited) == 0 {
+		return
+	}
+	g.saveVault(vault)
+
+	player.Broadcast(fmt.Sprintf("You deposit %s with %s.", strings.Join(deposited, ", "), npc.Name))
+	player.Area.Broadcast(fmt.Sprintf("%s hands something to %s.", player.Name, npc.Name), player)
+}
+
+func (g *Game) handleWithdraw(player *components.Player, args []string, game *Game) {
+	if len(args) == 0 {
+		player.Broadcast("Withdraw what? Usage: withdraw <item>, withdraw all, withdraw all.<item> or withdraw <amount> <gold|silver|copper>")
+		return
+	}
+
+	visit, ok := g.visitBank(player)
+	if !ok {
+		return
+	}
+	npc, vault := visit.npc, visit.vault
+
+	if amount, isMoney, err := parseMoney(args); isMoney {
+		if err != nil {
+			player.Broadcast(currencyLimitMessage())
+			return
+		}
+		formatted := components.FormatCurrency(amount)
+		if !vault.Withdraw(amount) {
+			player.Broadcast(fmt.Sprintf("%s says: Your vault doesn't hold %s.", npc.Name, formatted))
+			return
+		}
+		g.saveVault(vault)
+		visit.wallet.Deposit(amount, "withdrawn at "+npc.Name, g.world.Now())
+
+		player.Broadcast(fmt.Sprintf("You withdraw %s. Your vault holds %s.", formatted, components.FormatCurrency(vault.Balance())))
+		return
+	}
+
+	all, itemName := parseItemSelector(strings.ToLower(strings.Join(args, " ")))
+	targets := matchItems(vault.Items.GetItems(), itemName, all)
+	if len(targets) == 0 {
+		player.Broadcast(fmt.Sprintf("%s says: Your vault doesn't hold anything like that.", npc.Name))
+		return
+	}
+
+	withdrawn := make([]string, 0, len(targets))
+	for _, item := range targets {
+		if visit.inventory.IsFull() {
+			player.Broadcast("Your inventory is full!")
+			break
+		}
+		if !g.canCarry(player, visit.entityID, item) {
+			break
+		}
+
+		removed := vault.Items.RemoveItem(item.Key(), item.Quantity)
+		if removed == nil {
+			continue
+		}
+		if !visit.inventory.AddItem(removed) {
+			vault.Items.AddItem(removed)
+			player.Broadcast("Your inventory is full!")
+			break
+		}
+		withdrawn = append(withdrawn, removed.DisplayName())
+	}
+
+	if len(withdrawn) == 0 {
+		return
+	}
+	g.saveVault(vault)
+
+	player.Broadcast(fmt.Sprintf("You withdraw %s from your vault.", strings.Join(withdrawn, ", ")))
+	player.Area.Broadcast(fmt.Sprintf("%s collects something from %s.", player.Name, npc.Name), player)
+}
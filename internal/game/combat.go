@@ -4,8 +4,11 @@ import (
 	"dmud/internal/common"
 	"dmud/internal/components"
 	"dmud/internal/ecs"
+	"dmud/internal/systems"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
@@ -38,6 +41,10 @@ func (g *Game) HandleKillAll(player *components.Player) {
 		return
 	}
 
+	if g.isDown(player, playerEntity.ID) {
+		return
+	}
+
 	// Get all NPCs in the area
 	npcs := player.Area.GetNPCs(g.world.AsWorldLike())
 	if len(npcs) == 0 {
@@ -63,11 +70,13 @@ func (g *Game) HandleKillAll(player *components.Player) {
 	}
 
 	// Set player to attack the first target, queue the rest
+	minDamage, maxDamage := g.playerDamageRange(playerEntity.ID)
 	combatComponent := &components.Combat{
 		TargetID:    targetEntityIDs[0],
 		TargetQueue: targetEntityIDs[1:], // Queue up the rest
-		MinDamage:   10,
-		MaxDamage:   50,
+		MinDamage:   minDamage,
+		MaxDamage:   maxDamage,
+		NextRound:   g.nextRound(playerEntity.ID),
 	}
 	g.world.AddComponent(playerEntity, combatComponent)
 
@@ -80,55 +89,134 @@ func (g *Game) HandleKillAll(player *components.Player) {
 	}
 }
 
+// HandleKill starts a fight with an NPC or player in the same area. Players
+// can only be attacked when the PvP rules allow it.
 func (g *Game) HandleKill(player *components.Player, targetName string) {
 	log.Trace().Msgf("Kill: %s", targetName)
 
-	// First check for players
-	g.playersMu.Lock()
-	defer g.playersMu.Unlock()
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Warn().Msgf("Error getting player's own entity for %s", player.Name)
+		return
+	}
 
-	targetEntity := g.players[targetName]
-	playerEntity := g.players[player.Name]
+	if strings.EqualFold(targetName, player.Name) {
+		player.Broadcast("You can't attack yourself.")
+		return
+	}
 
-	if targetEntity == nil {
-		// Check for NPCs
-		npcs := player.Area.GetNPCs(g.world.AsWorldLike())
-		for _, npc := range npcs {
-			if strings.Contains(strings.ToLower(npc.Name), strings.ToLower(targetName)) {
-				// Find NPC entity
-				npcEntities, _ := g.world.FindEntitiesByComponentPredicate("NPC", func(i interface{}) bool {
-					n, ok := i.(*components.NPC)
-					return ok && n == npc
-				})
-
-				if len(npcEntities) > 0 {
-					targetEntity = &npcEntities[0]
-					break
-				}
-			}
-		}
+	target, ok := g.findFoe(player, targetName)
+	if !ok {
+		player.Broadcast("They aren't here.")
+		return
+	}
 
-		if targetEntity == nil {
-			player.Broadcast("They aren't here.")
-			return
-		}
+	if g.isDown(player, playerEntity) {
+		return
 	}
 
-	if playerEntity == nil {
-		log.Warn().Msgf("Error getting player's own entity for %s", player.Name)
+	if target.Player != nil && !g.canAttackPlayer(player, playerEntity, target) {
 		return
 	}
 
+	entity, err := g.world.FindEntity(playerEntity)
+	if err != nil {
+		return
+	}
+
+	minDamage, maxDamage := g.playerDamageRange(playerEntity)
 	combatComponent := &components.Combat{
-		TargetID:  targetEntity.ID,
-		MinDamage: 10,
-		MaxDamage: 50,
+		TargetID:  target.ID,
+		MinDamage: minDamage,
+		MaxDamage: maxDamage,
+		NextRound: g.nextRound(playerEntity),
 	}
 
-	g.world.AddComponent(playerEntity, combatComponent)
+	g.world.AddComponent(&entity, combatComponent)
 
 	// Announce combat
-	if npc, err := ecs.GetTypedComponent[*components.NPC](g.world, targetEntity.ID, "NPC"); err == nil {
-		player.Area.Broadcast(player.Name + " attacks " + npc.Name + "!")
+	player.Area.Broadcast(player.Name + " attacks " + target.Name + "!")
+}
+
+// canAttackPlayer checks the PvP rules before a player attacks another,
+// telling them if they can't.
+func (g *Game) canAttackPlayer(player *components.Player, playerEntity common.EntityID, target abilityTarget) bool {
+	if refusal := systems.PvPRefusal(g.world, player.Area, playerEntity, target.ID, target.Name); refusal != "" {
+		player.Broadcast(refusal)
+		return false
+	}
+	return true
+}
+
+// nextRound carries an entity's swing timer over when it picks a new
+// target, so switching targets can't be used to attack faster.
+func (g *Game) nextRound(entityID common.EntityID) time.Time {
+	combat, err := ecs.GetTypedComponent[*components.Combat](g.world, entityID, "Combat")
+	if err != nil {
+		return time.Time{}
+	}
+	combat.RLock()
+	defer combat.RUnlock()
+	return combat.NextRound
+}
+
+func (g *Game) handleFlee(player *components.Player, args []string, game *Game) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	if !g.inCombat(playerEntity) {
+		player.Broadcast("You aren't fighting anyone.")
+		return
+	}
+
+	systems.Flee(g.world, playerEntity, player)
+}
+
+func (g *Game) handleWimpy(player *components.Player, args []string, game *Game) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	wimpy, err := ecs.GetTypedComponent[*components.Wimpy](g.world, playerEntity, "Wimpy")
+	if err != nil {
+		return
+	}
+
+	if len(args) == 0 {
+		if threshold := wimpy.GetThreshold(); threshold > 0 {
+			player.Broadcast(fmt.Sprintf("You will flee when your health drops below %d.", threshold))
+		} else {
+			player.Broadcast("Wimpy is off. Type 'wimpy <hp>' to flee automatically when your health drops below <hp>.")
+		}
+		return
+	}
+
+	threshold, err := strconv.Atoi(args[0])
+	if err != nil || threshold < 0 {
+		player.Broadcast("Usage: wimpy <hp>, or wimpy 0 to turn it off.")
+		return
+	}
+
+	maxThreshold := 0
+	if health, err := ecs.GetTypedComponent[*components.Health](g.world, playerEntity, "Health"); err == nil {
+		health.RLock()
+		maxThreshold = health.Max / 2
+		health.RUnlock()
+	}
+	if threshold > maxThreshold {
+		player.Broadcast(fmt.Sprintf("Wimpy can't be set above half your maximum health (%d).", maxThreshold))
+		return
+	}
+
+	wimpy.SetThreshold(threshold)
+	if threshold == 0 {
+		player.Broadcast("Wimpy is off. You will fight to the death.")
+		return
 	}
+	player.Broadcast(fmt.Sprintf("You will flee when your health drops below %d.", threshold))
 }
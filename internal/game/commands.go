@@ -13,8 +13,21 @@ var commandHelpText = map[string]string{
 	"who":       "List all players currently online in the game.",
 	"say":       "Say something to all players in the same area. Usage: say <message>",
 	"shout":     "Shout a message that can be heard in nearby areas. Usage: shout <message>",
-	"examine":   "Examine something or someone in detail. Usage: examine <target>",
-	"kill":      "Attack another player or NPC. Usage: kill <target> or kill all (to attack everything in the area)",
+	"examine":   "Examine someone, or an item you carry, wear or see, in detail. Usage: examine <target>",
+	"score":     "Show your level, health, attributes and the combat stats they give you. (alias: sc)",
+	"train":     "Spend a training point to raise an attribute by one. Strength raises damage and carry capacity, dexterity accuracy and evasion, constitution health. Usage: train or train <attribute>",
+	"abilities": "List the abilities your class has taught you, what they cost and how long until each can be used again. Use an ability by typing its name, such as 'bash rat' or 'heal'. (alias: skills)",
+	"rest":      "Sit down and rest. Health, mana and stamina come back twice as fast, but you can't move or fight until you stand. (alias: sit)",
+	"sleep":     "Lie down and sleep. You recover three times as fast as standing, but you can't move or fight until you stand.",
+	"stand":     "Get up after resting or sleeping. Being attacked gets you up too. (alias: wake)",
+	"release":   "Return to life at your bind point after dying. You come back with half your health, some lost experience and weakened for a while. Ghosts are pulled back on their own if they wait.",
+	"bind":      "Bind yourself to the shrine you are standing at. When you die, you return to life there.",
+	"consent":   "Your corpse keeps everything you carried, and only you can loot it unless you give consent. Usage: consent, consent <player> or consent revoke <player>",
+	"kill":      "Attack another player or NPC in your area. Players can only be attacked outside safe areas, by someone they are dueling or when both of you have PvP on. Usage: kill <target> or kill all (to attack everything in the area)",
+	"flee":      "Try to escape a fight through a random exit. Quick characters get away more often, stunned ones can't run at all, and running costs some experience.",
+	"wimpy":     "Flee automatically when a hit leaves your health below a threshold, up to half your maximum health. Usage: wimpy, wimpy <hp> or wimpy 0 to turn it off",
+	"pvp":       "Choose whether to fight other players. Both sides need PvP on, and it can only be changed every five minutes and never mid-fight. Usage: pvp, pvp on or pvp off",
+	"duel":      "Challenge a player in your area to a duel, or accept their challenge by challenging them back. Duels end when one side is beaten down to 1 health, so nobody dies. Leaving the area forfeits a duel, and one still going after 5 minutes is a draw. Usage: duel <player>",
 	"exit":      "Leave the game and disconnect from the server.",
 	"north":     "Move north to the adjacent area (if an exit exists).",
 	"south":     "Move south to the adjacent area (if an exit exists).",
@@ -31,8 +44,32 @@ var commandHelpText = map[string]string{
 	"complete":  "Get instant auto-completion for commands or player names. Usage: complete <partial>",
 	"inventory": "View your inventory and see what items you are carrying. Usage: inventory (aliases: inv, i)",
 	"loot":      "Loot items from a corpse. Usage: loot <corpse_name> or loot all (to loot all corpses in the area)",
-	"get":       "Pick up an item from the ground. Usage: get <item_name> (aliases: pickup, take)",
-	"drop":      "Drop an item from your inventory onto the ground. Usage: drop <item_name>",
+	"get":       "Pick up an item from the ground, or take it out of a bag or corpse. Usage: get <item_name>, get all, get all.<item_name> or get <item_name> from <container> (aliases: pickup, take)",
+	"put":       "Put an item in a bag, backpack or corpse. Usage: put <item_name> in <container> or put all in <container>",
+	"drop":      "Drop an item from your inventory onto the ground. Usage: drop <item_name>, drop all or drop all.<item_name>",
+	"wear":      "Wear a piece of armor from your inventory. Usage: wear <item_name>",
+	"wield":     "Wield a weapon from your inventory. Equipped weapons change your damage. Usage: wield <item_name>",
+	"remove":    "Remove an equipped item and put it back in your inventory. Usage: remove <item_name|slot> (alias: rem)",
+	"equipment": "Show what you are wearing and wielding, with your damage and armor. (alias: eq)",
+	"eat":       "Eat some food from your inventory. Usage: eat <item_name>",
+	"drink":     "Drink a potion or beverage from your inventory. Usage: drink <item_name> (alias: quaff)",
+	"use":       "Use an item from your inventory, such as a recipe scroll. Usage: use <item_name>",
+	"craft":     "Craft an item from a recipe you know. Some recipes need a tool or a station such as a campfire. Usage: craft <recipe> (alias: cook)",
+	"recipes":   "List the recipes you know, what they need and your crafting skill.",
+	"balance":   "Show how much gold, silver and copper you have, with recent transactions. (aliases: bal, money)",
+	"give":      "Give money to a player in your area. Usage: give <amount> <gold|silver|copper> <player>",
+	"list":      "List the wares of a merchant in your area, with prices and stock.",
+	"buy":       "Buy an item from a merchant in your area. Usage: buy <item_name>",
+	"sell":      "Sell an item from your inventory to a merchant in your area. Usage: sell <item_name>",
+	"value":     "Ask a merchant what they would pay for an item. Usage: value <item_name>",
+	"trade":     "Start a trade with a player in your area, or show the current trade. Both players must accept before anything changes hands, and any change to an offer clears both acceptances. Usage: trade <player>, trade or trade cancel",
+	"offer":     "Offer an item or money in your current trade. Usage: offer <item_name>, offer <count> <item_name> or offer <amount> <gold|silver|copper>",
+	"retract":   "Take an item or your money back out of your current trade. Usage: retract <item_name> or retract coins",
+	"accept":    "Accept your current trade as it stands. The swap happens once both players have accepted.",
+	"repair":    "Have a smith in your area repair worn or broken gear for a fee. Usage: repair (to see prices), repair <item_name> or repair all",
+	"bank":      "List the items and money in your bank vault. You must be with a banker.",
+	"deposit":   "Put items or money in your bank vault. Banked items are kept safe when you die. Usage: deposit <item_name>, deposit all, deposit all.<item_name> or deposit <amount> <gold|silver|copper>",
+	"withdraw":  "Take items or money out of your bank vault. Usage: withdraw <item_name>, withdraw all, withdraw all.<item_name> or withdraw <amount> <gold|silver|copper>",
 	"hail":   "Hail an NPC to start a conversation. Usage: hail <npc_name>",
 	"uptime": "Show server uptime, current players, and connection statistics.",
 }
@@ -134,7 +171,24 @@ func handleHelp(player *components.Player, args []string, game *Game) {
 
 		b.WriteString("COMBAT\n")
 		b.WriteString("  kill <target>     - Attack a target\n")
-		b.WriteString("  kill all          - Attack everything in the area\n\n")
+		b.WriteString("  kill all          - Attack everything in the area\n")
+		b.WriteString("  flee              - Try to escape a fight\n")
+		b.WriteString("  wimpy <hp>        - Flee automatically at low health\n")
+		b.WriteString("  pvp [on|off]      - Choose whether to fight players\n")
+		b.WriteString("  duel <player>     - Challenge a player to a duel\n\n")
+
+		b.WriteString("CHARACTER\n")
+		b.WriteString("  score             - Show your stats (alias: sc)\n")
+		b.WriteString("  name <new_name>   - Change your name\n")
+		b.WriteString("  recall            - Return to starting area\n")
+		b.WriteString("  train <attribute> - Spend a training point\n")
+		b.WriteString("  rest / sleep      - Recover faster (alias: sit)\n")
+		b.WriteString("  stand             - Get back up (alias: wake)\n")
+		b.WriteString("  release           - Return to life after dying\n")
+		b.WriteString("  bind              - Bind yourself to a shrine\n")
+		b.WriteString("  consent <player>  - Let someone loot your corpse\n")
+		b.WriteString("  abilities         - List your abilities (alias: skills)\n")
+		b.WriteString("  <ability> [target]- Use an ability, such as bash or heal\n\n")
 
 		b.WriteString("MOVEMENT\n")
 		b.WriteString("  north, south, east, west, up, down\n\n")
@@ -144,11 +198,46 @@ func handleHelp(player *components.Player, args []string, game *Game) {
 		b.WriteString("  loot <corpse>     - Loot items from a corpse\n")
 		b.WriteString("  loot all          - Loot all corpses in the area\n")
 		b.WriteString("  get <item>        - Pick up an item (aliases: pickup, take)\n")
-		b.WriteString("  drop <item>       - Drop an item\n\n")
-
-		b.WriteString("CHARACTER\n")
-		b.WriteString("  name <new_name>   - Change your name\n")
-		b.WriteString("  recall            - Return to starting area\n\n")
+		b.WriteString("  get all[.<item>]  - Pick up everything (matching) here\n")
+		b.WriteString("  get <item> from <container> - Take an item out of a container\n")
+		b.WriteString("  put <item> in <container>   - Put an item in a container\n")
+		b.WriteString("  drop <item>       - Drop an item\n")
+		b.WriteString("  drop all[.<item>] - Drop everything (matching) you carry\n")
+		b.WriteString("  eat <item>        - Eat some food\n")
+		b.WriteString("  drink <item>      - Drink a potion (alias: quaff)\n")
+		b.WriteString("  use <item>        - Use an item\n\n")
+
+		b.WriteString("CRAFTING\n")
+		b.WriteString("  recipes           - List the recipes you know\n")
+		b.WriteString("  craft <recipe>    - Craft an item (alias: cook)\n\n")
+
+		b.WriteString("MONEY\n")
+		b.WriteString("  balance           - Show your money (aliases: bal, money)\n")
+		b.WriteString("  give <n> gold <p> - Give money to a player\n\n")
+
+		b.WriteString("TRADING\n")
+		b.WriteString("  trade <player>    - Start a trade (trade cancel to stop)\n")
+		b.WriteString("  offer <item>      - Offer an item or money\n")
+		b.WriteString("  retract <item>    - Take something back\n")
+		b.WriteString("  accept            - Accept the trade as it stands\n\n")
+
+		b.WriteString("BANKING\n")
+		b.WriteString("  bank              - List your vault (at a banker)\n")
+		b.WriteString("  deposit <item>    - Store an item or money\n")
+		b.WriteString("  withdraw <item>   - Take an item or money back\n\n")
+
+		b.WriteString("SHOPPING\n")
+		b.WriteString("  list              - List a merchant's wares\n")
+		b.WriteString("  buy <item>        - Buy an item\n")
+		b.WriteString("  sell <item>       - Sell an item\n")
+		b.WriteString("  value <item>      - Ask what a merchant would pay\n")
+		b.WriteString("  repair [item|all] - Have a smith repair your gear\n\n")
+
+		b.WriteString("EQUIPMENT\n")
+		b.WriteString("  equipment         - View your equipment (alias: eq)\n")
+		b.WriteString("  wear <item>       - Wear a piece of armor\n")
+		b.WriteString("  wield <item>      - Wield a weapon\n")
+		b.WriteString("  remove <item>     - Remove an equipped item\n\n")
 
 		b.WriteString("UTILITY\n")
 		b.WriteString("  help [command]    - Show help information\n")
@@ -175,6 +264,10 @@ func handleHelp(player *components.Player, args []string, game *Game) {
 		player.Broadcast(fmt.Sprintf("Help for '%s':", commandName))
 		player.Broadcast("=" + strings.Repeat("=", len(commandName)+8))
 		player.Broadcast(help)
+	} else if ability, exists := components.Abilities[commandName]; exists {
+		player.Broadcast(fmt.Sprintf("Help for '%s':", commandName))
+		player.Broadcast("=" + strings.Repeat("=", len(commandName)+8))
+		player.Broadcast(fmt.Sprintf("%s Usage: %s%s", ability.Description, ability.ID, abilityUsage(ability)))
 	} else {
 		player.Broadcast(fmt.Sprintf("No help available for command '%s'", commandName))
 		player.Broadcast("Type 'help' to see all available commands.")
@@ -0,0 +1,197 @@
+package game
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// useEffectHandler applies one of an item's use effects to the player using
+// it. It returns a message for the player and whether the effect took hold;
+// an item is only used up if at least one of its effects does. New kinds of
+// effect get a handler here, while new items only need data in items.json.
+type useEffectHandler func(g *Game, player *components.Player, entityID common.EntityID, effect components.UseEffect) (string, bool)
+
+var useEffectHandlers = map[components.UseEffectType]useEffectHandler{
+	components.UseEffectHeal:        applyHealEffect,
+	components.UseEffectStatus:      applyStatusEffect,
+	components.UseEffectTeachRecipe: applyTeachRecipeEffect,
+}
+
+// useVerbThirdPerson is how other players see an item being used.
+var useVerbThirdPerson = map[string]string{
+	"eat":   "eats",
+	"drink": "drinks",
+	"use":   "uses",
+}
+
+func (g *Game) handleEat(player *components.Player, args []string, game *Game) {
+	g.consumeItem(player, args, "eat")
+}
+
+func (g *Game) handleDrink(player *components.Player, args []string, game *Game) {
+	g.consumeItem(player, args, "drink")
+}
+
+func (g *Game) handleUse(player *components.Player, args []string, game *Game) {
+	g.consumeItem(player, args, "use")
+}
+
+func (g *Game) consumeItem(player *components.Player, args []string, verb string) {
+	if len(args) == 0 {
+		player.Broadcast(fmt.Sprintf("%s what? Usage: %s <item>", strings.ToUpper(verb[:1])+verb[1:], verb))
+		return
+	}
+
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	inventory, err := ecs.GetTypedComponent[*components.Inventory](g.world, playerEntity, "Inventory")
+	if err != nil {
+		player.Broadcast("You don't have an inventory!")
+		return
+	}
+
+	item := findItemByName(inventory.GetItems(), strings.ToLower(strings.Join(args, " ")))
+	if item == nil {
+		player.Broadcast("You don't have that item.")
+		return
+	}
+
+	// "use" works on anything usable; eat and drink only on matching items
+	if len(item.UseEffects) == 0 || (verb != "use" && item.UseVerb != verb) {
+		player.Broadcast(fmt.Sprintf("You can't %s %s.", verb, item.Name))
+		return
+	}
+
+	var messages []string
+	applied := false
+	for _, effect := range item.UseEffects {
+		handler, ok := useEffectHandlers[effect.Type]
+		if !ok {
+			log.Warn().Msgf("No handler for use effect %d on item %s", effect.Type, item.ID)
+			continue
+		}
+
+		msg, ok := handler(g, player, playerEntity, effect)
+		if msg != "" {
+			messages = append(messages, msg)
+		}
+		applied = applied || ok
+	}
+
+	if !applied {
+		if len(messages) == 0 {
+			messages = append(messages, "Nothing happens.")
+		}
+		player.Broadcast(strings.Join(messages, "\n"))
+		return
+	}
+
+	if inventory.RemoveItem(item.Key(), 1) == nil {
+		player.Broadcast("You don't have that item.")
+		return
+	}
+
+	messages = append([]string{fmt.Sprintf("You %s %s.", verb, item.Name)}, messages...)
+	player.Broadcast(strings.Join(messages, "\n"))
+	player.Area.Broadcast(fmt.Sprintf("%s %s %s.", player.Name, useVerbThirdPerson[verb], item.Name), player)
+	player.BroadcastState(g.world.AsWorldLike(), playerEntity)
+}
+
+func applyHealEffect(g *Game, player *components.Player, entityID common.EntityID, effect components.UseEffect) (string, bool) {
+	health, err := ecs.GetTypedComponent[*components.Health](g.world, entityID, "Health")
+	if err != nil {
+		return "", false
+	}
+
+	bonus := 0
+	if statusEffects, err := ecs.GetTypedComponent[*components.StatusEffects](g.world, entityID, "StatusEffects"); err == nil {
+		bonus = statusEffects.GetTotalHPBonus()
+	}
+
+	health.Lock()
+	defer health.Unlock()
+
+	effectiveMax := health.GetEffectiveMax(bonus)
+	if health.Current >= effectiveMax {
+		return "You are already at full health.", false
+	}
+
+	before := health.Current
+	health.Current += effect.Amount
+	if health.Current > effectiveMax {
+		health.Current = effectiveMax
+	}
+	if health.Current >= health.Max {
+		health.Status = components.Healthy
+	}
+
+	return fmt.Sprintf("You feel better. (+%d HP)", health.Current-before), true
+}
+
+func applyStatusEffect(g *Game, player *components.Player, entityID common.EntityID, effect components.UseEffect) (string, bool) {
+	health, err := ecs.GetTypedComponent[*components.Health](g.world, entityID, "Health")
+	if err != nil {
+		return "", false
+	}
+
+	statusEffects, err := ecs.GetTypedComponent[*components.StatusEffects](g.world, entityID, "StatusEffects")
+	if err != nil {
+		entity, err := g.world.FindEntity(entityID)
+		if err != nil {
+			return "", false
+		}
+		statusEffects = components.NewStatusEffects()
+		g.world.AddComponent(&entity, statusEffects)
+	}
+
+	if statusEffects.HasNamedEffect(effect.Name) {
+		return fmt.Sprintf("You are already under the effect of %s.", effect.Name), false
+	}
+
+	statusEffects.AddEffect(components.StatusEffect{
+		Type:      components.StatusEffectItem,
+		Name:      effect.Name,
+		AppliedAt: g.world.Now(),
+		Duration:  effect.Duration,
+		HPBonus:   effect.Amount,
+		Applied:   true,
+
+		Resistances: effect.Resistances,
+	})
+
+	// Like the guard's blessing, the bonus HP is granted up front and taken
+	// away again by the StatusEffectSystem when the effect wears off
+	health.Lock()
+	health.Current += effect.Amount
+	health.Unlock()
+
+	if effect.Amount > 0 {
+		return fmt.Sprintf("You feel the %s take hold. (+%d HP)", effect.Name, effect.Amount), true
+	}
+	return fmt.Sprintf("You feel the %s take hold.", effect.Name), true
+}
+
+func applyTeachRecipeEffect(g *Game, player *components.Player, entityID common.EntityID, effect components.UseEffect) (string, bool) {
+	recipeBook, err := ecs.GetTypedComponent[*components.RecipeBook](g.world, entityID, "RecipeBook")
+	if err != nil {
+		return "", false
+	}
+
+	name := effect.Name
+	if recipe, ok := components.Recipes[effect.Name]; ok {
+		name = strings.ToLower(recipe.Name)
+	}
+	if !recipeBook.Learn(effect.Name) {
+		return fmt.Sprintf("You already know how to make %s.", name), false
+	}
+	return fmt.Sprintf("You learn how to make %s.", name), true
+}
@@ -0,0 +1,191 @@
+package game
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// containerRef is something items can be put in or taken from: a bag the
+// player carries, a bag on the ground, or a corpse.
+type containerRef struct {
+	name      string
+	inventory *components.Inventory
+	carried   bool               // In the player's own inventory, so moving items doesn't change their load
+	corpse    *components.Corpse // Set when the container is a corpse
+}
+
+// canCarry reports whether the player can take on extra weight, telling
+// them if they can't.
+func (g *Game) canCarry(player *components.Player, entityID common.EntityID, item *components.Item) bool {
+	carried, capacity := components.CarryingWeight(g.world.AsWorldLike(), entityID)
+	if carried+item.TotalWeight() > capacity {
+		player.Broadcast(fmt.Sprintf("%s is too heavy for you to carry.", item.DisplayName()))
+		return false
+	}
+	return true
+}
+
+// findContainer looks for a container called name in the player's inventory,
+// then on the ground, then among the corpses in their area.
+func (g *Game) findContainer(player *components.Player, inventory *components.Inventory, name string) *containerRef {
+	for _, item := range inventory.GetItems() {
+		if item.Container != nil && strings.Contains(strings.ToLower(item.Name), name) {
+			return &containerRef{name: item.Name, inventory: item.Container, carried: true}
+		}
+	}
+
+	for _, item := range player.Area.GetGroundItems() {
+		if item.Container != nil && strings.Contains(strings.ToLower(item.Name), name) {
+			return &containerRef{name: item.Name, inventory: item.Container}
+		}
+	}
+
+	for _, corpse := range player.Area.GetCorpses(g.world.AsWorldLike()) {
+		if corpse.Inventory != nil && strings.Contains(strings.ToLower(corpse.GetDescription()), name) {
+			return &containerRef{name: corpse.GetDescription(), inventory: corpse.Inventory, corpse: corpse}
+		}
+	}
+
+	return nil
+}
+
+func (g *Game) handlePut(player *components.Player, args []string, game *Game) {
+	itemArg, containerArg, found := strings.Cut(strings.ToLower(strings.Join(args, " ")), " in ")
+	if !found || itemArg == "" || containerArg == "" {
+		player.Broadcast("Put what where? Usage: put <item> in <container>")
+		return
+	}
+
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	inventory, err := ecs.GetTypedComponent[*components.Inventory](g.world, playerEntity, "Inventory")
+	if err != nil {
+		player.Broadcast("You don't have an inventory!")
+		return
+	}
+
+	container := g.findContainer(player, inventory, strings.TrimSpace(containerArg))
+	if container == nil {
+		player.Broadcast("You don't see that container here.")
+		return
+	}
+	if container.corpse != nil && !g.canLoot(player, container.corpse) {
+		return
+	}
+
+	all, itemName := parseItemSelector(itemArg)
+	var targets []*components.Item
+	for _, item := range matchItems(inventory.GetItems(), itemName, all) {
+		if item.Container == container.inventory {
+			continue
+		}
+		targets = append(targets, item)
+	}
+
+	if len(targets) == 0 {
+		player.Broadcast("You don't have that item.")
+		return
+	}
+
+	moved := make([]string, 0, len(targets))
+	for _, item := range targets {
+		if item.Container != nil {
+			player.Broadcast(fmt.Sprintf("%s won't fit inside another container.", item.Name))
+			continue
+		}
+		if container.inventory.IsFull() {
+			player.Broadcast(fmt.Sprintf("%s is full.", container.name))
+			break
+		}
+
+		removed := inventory.RemoveItem(item.Key(), item.Quantity)
+		if removed == nil {
+			continue
+		}
+		container.inventory.AddItem(removed)
+		moved = append(moved, removed.DisplayName())
+	}
+
+	if len(moved) == 0 {
+		return
+	}
+
+	list := strings.Join(moved, ", ")
+	player.Broadcast(fmt.Sprintf("You put %s in %s.", list, container.name))
+	player.Area.Broadcast(fmt.Sprintf("%s puts %s in %s.", player.Name, list, container.name), player)
+}
+
+// getFromContainer handles "get <item> from <container>".
+func (g *Game) getFromContainer(player *components.Player, playerEntity common.EntityID, inventory *components.Inventory, itemArg, containerArg string) {
+	container := g.findContainer(player, inventory, containerArg)
+	if container == nil {
+		player.Broadcast("You don't see that container here.")
+		return
+	}
+	if container.corpse != nil && !g.canLoot(player, container.corpse) {
+		return
+	}
+
+	all, itemName := parseItemSelector(itemArg)
+	targets := matchItems(container.inventory.GetItems(), itemName, all)
+	if len(targets) == 0 {
+		if all && itemName == "" {
+			player.Broadcast(fmt.Sprintf("%s is empty.", container.name))
+		} else {
+			player.Broadcast(fmt.Sprintf("There is no such thing in %s.", container.name))
+		}
+		return
+	}
+
+	wallet := g.playerWallet(playerEntity)
+
+	taken := make([]string, 0, len(targets))
+	for _, item := range targets {
+		if name, ok := g.collectCoins(wallet, item, "took from "+container.name); ok {
+			container.inventory.RemoveItem(item.Key(), item.Quantity)
+			taken = append(taken, name)
+			continue
+		}
+
+		if inventory.IsFull() {
+			player.Broadcast("Your inventory is full!")
+			break
+		}
+		if !container.carried && !g.canCarry(player, playerEntity, item) {
+			break
+		}
+
+		removed := container.inventory.RemoveItem(item.Key(), item.Quantity)
+		if removed == nil {
+			continue
+		}
+		if !inventory.AddItem(removed) {
+			container.inventory.AddItem(removed)
+			player.Broadcast("Your inventory is full!")
+			break
+		}
+		taken = append(taken, removed.DisplayName())
+	}
+
+	if len(taken) == 0 {
+		return
+	}
+
+	// An emptied corpse decays quickly, just as if it had been looted
+	if container.corpse != nil && container.inventory.IsEmpty() {
+		container.corpse.MarkAsLooted(g.world.Now())
+	}
+
+	list := strings.Join(taken, ", ")
+	player.Broadcast(fmt.Sprintf("You get %s from %s.", list, container.name))
+	player.Area.Broadcast(fmt.Sprintf("%s gets %s from %s.", player.Name, list, container.name), player)
+}
@@ -0,0 +1,207 @@
+package game
+
+import (
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// craftingChancePerLevel is how much each crafting level above a recipe's
+// requirement adds to its success chance.
+const craftingChancePerLevel = 0.05
+
+func (g *Game) handleRecipes(player *components.Player, args []string, game *Game) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	recipeBook, _ := ecs.GetTypedComponent[*components.RecipeBook](g.world, playerEntity, "RecipeBook")
+	skill, err := ecs.GetTypedComponent[*components.CraftingSkill](g.world, playerEntity, "CraftingSkill")
+	if err != nil {
+		player.Broadcast("You don't know how to craft.")
+		return
+	}
+
+	skill.RLock()
+	level, xp := skill.Level, skill.XP
+	skill.RUnlock()
+
+	var output strings.Builder
+	output.WriteString("==============================================\n")
+	output.WriteString("                  RECIPES                     \n")
+	output.WriteString("==============================================\n\n")
+	output.WriteString(fmt.Sprintf("Crafting skill: level %d (%d/%d XP)\n\n", level, xp, components.CraftingXPForLevel(level)))
+
+	recipes := knownRecipes(recipeBook)
+	if len(recipes) == 0 {
+		output.WriteString("  You don't know any recipes.\n")
+	}
+
+	for _, recipe := range recipes {
+		output.WriteString(fmt.Sprintf("  %s (skill %d)\n", recipe.Name, recipe.SkillLevel))
+		output.WriteString(fmt.Sprintf("    needs: %s\n", describeRecipeItems(recipe.Inputs)))
+		if recipe.Tool != "" {
+			output.WriteString(fmt.Sprintf("    tool:  %s\n", itemName(recipe.Tool)))
+		}
+		if recipe.Station != "" {
+			output.WriteString(fmt.Sprintf("    at:    a %s\n", recipe.Station))
+		}
+		output.WriteString(fmt.Sprintf("    makes: %s\n", describeRecipeItems(recipe.Outputs)))
+	}
+
+	output.WriteString("==============================================\n")
+
+	player.Broadcast(output.String())
+}
+
+func (g *Game) handleCraft(player *components.Player, args []string, game *Game) {
+	if len(args) == 0 {
+		player.Broadcast("Craft what? Usage: craft <recipe>")
+		return
+	}
+
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	inventory, err := ecs.GetTypedComponent[*components.Inventory](g.world, playerEntity, "Inventory")
+	if err != nil {
+		player.Broadcast("You don't have an inventory!")
+		return
+	}
+
+	skill, err := ecs.GetTypedComponent[*components.CraftingSkill](g.world, playerEntity, "CraftingSkill")
+	if err != nil {
+		player.Broadcast("You don't know how to craft.")
+		return
+	}
+
+	recipeBook, _ := ecs.GetTypedComponent[*components.RecipeBook](g.world, playerEntity, "RecipeBook")
+
+	name := strings.ToLower(strings.Join(args, " "))
+	var recipe *components.Recipe
+	for _, r := range knownRecipes(recipeBook) {
+		if r.ID == name || strings.Contains(strings.ToLower(r.Name), name) {
+			recipe = r
+			break
+		}
+	}
+
+	if recipe == nil {
+		player.Broadcast("You don't know how to make that. Type 'recipes' to see what you can craft.")
+		return
+	}
+
+	level := skill.GetLevel()
+	if level < recipe.SkillLevel {
+		player.Broadcast(fmt.Sprintf("You need crafting skill %d to make %s.", recipe.SkillLevel, recipe.Name))
+		return
+	}
+
+	if recipe.Station != "" && !player.Area.HasFlag(recipe.Station) {
+		player.Broadcast(fmt.Sprintf("You need a %s to make %s.", recipe.Station, recipe.Name))
+		return
+	}
+
+	// A tool counts whether it is carried or wielded
+	items := inventory.GetItems()
+	var equipped []*components.Item
+	if equipment, err := ecs.GetTypedComponent[*components.Equipment](g.world, playerEntity, "Equipment"); err == nil {
+		equipped = equipment.GetItems()
+	}
+	if recipe.Tool != "" && countItem(items, recipe.Tool)+countItem(equipped, recipe.Tool) == 0 {
+		player.Broadcast(fmt.Sprintf("You need %s to make %s.", itemName(recipe.Tool), recipe.Name))
+		return
+	}
+
+	for _, input := range recipe.Inputs {
+		if countItem(items, input.ItemID) < input.Quantity {
+			player.Broadcast(fmt.Sprintf("You need %s to make %s.", describeRecipeItems(recipe.Inputs), recipe.Name))
+			return
+		}
+	}
+
+	for _, input := range recipe.Inputs {
+		inventory.RemoveQuantity(input.ItemID, input.Quantity)
+	}
+
+	chance := recipe.SuccessChance + float64(level-recipe.SkillLevel)*craftingChancePerLevel
+	if g.world.Rand().Float64() >= chance {
+		player.Broadcast(fmt.Sprintf("Your attempt to make %s fails, ruining the materials.", recipe.Name))
+		player.Area.Broadcast(fmt.Sprintf("%s tries to make %s, but fails.", player.Name, recipe.Name), player)
+		return
+	}
+
+	for _, out := range recipe.Outputs {
+		item := components.CreateItem(out.ItemID, out.Quantity)
+		if item == nil {
+			log.Error().Msgf("Recipe %s produces unknown item %s", recipe.ID, out.ItemID)
+			continue
+		}
+
+		if !inventory.AddItem(item) {
+			player.Area.AddGroundItem(item, g.world.Now())
+			player.Broadcast(fmt.Sprintf("You have no room for %s, so you set it on the ground.", item.DisplayName()))
+		}
+	}
+
+	player.Broadcast(fmt.Sprintf("You make %s. (+%d crafting XP)", describeRecipeItems(recipe.Outputs), recipe.XP))
+	player.Area.Broadcast(fmt.Sprintf("%s makes %s.", player.Name, recipe.Name), player)
+
+	if leveledUp, newLevel := skill.AddXP(recipe.XP); leveledUp {
+		player.Broadcast(fmt.Sprintf("Your crafting skill has increased to %d!", newLevel))
+	}
+}
+
+// knownRecipes returns the recipes a player can use, sorted by skill level
+// then name. Recipes that must be taught are only included once learned.
+func knownRecipes(recipeBook *components.RecipeBook) []*components.Recipe {
+	var recipes []*components.Recipe
+	for _, recipe := range components.Recipes {
+		if recipe.RequiresLearning && (recipeBook == nil || !recipeBook.Knows(recipe.ID)) {
+			continue
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	sort.Slice(recipes, func(i, j int) bool {
+		if recipes[i].SkillLevel != recipes[j].SkillLevel {
+			return recipes[i].SkillLevel < recipes[j].SkillLevel
+		}
+		return recipes[i].Name < recipes[j].Name
+	})
+	return recipes
+}
+
+func describeRecipeItems(items []components.RecipeItem) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = fmt.Sprintf("%d x %s", item.Quantity, itemName(item.ItemID))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func itemName(itemID string) string {
+	if template, ok := components.ItemTemplates[itemID]; ok {
+		return template.Name
+	}
+	return itemID
+}
+
+func countItem(items []*components.Item, itemID string) int {
+	count := 0
+	for _, item := range items {
+		if item.ID == itemID {
+			count += item.Quantity
+		}
+	}
+	return count
+}
@@ -0,0 +1,312 @@
+package game
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"dmud/internal/recording"
+	"dmud/internal/systems"
+	"dmud/internal/util"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Limits on the names and passwords players can choose at character
+// creation.
+const (
+	minNameLength     = 3
+	maxNameLength     = 16
+	minPasswordLength = 4
+)
+
+// redactedPassword is recorded in place of whatever a player typed as their
+// password.
+const redactedPassword = "********"
+
+// characterCreation returns the player's creation progress, or nil once they
+// have finished creating their character.
+func (g *Game) characterCreation(player *components.Player) (common.EntityID, *components.CharacterCreation) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		return "", nil
+	}
+	creation, err := ecs.GetTypedComponent[*components.CharacterCreation](g.world, playerEntity, "CharacterCreation")
+	if err != nil {
+		return playerEntity, nil
+	}
+	return playerEntity, creation
+}
+
+// handleCreation takes a line of input from a player who is still creating
+// their character: first their name, then the password that proves it is
+// theirs, then their race, then their class.
+func (g *Game) handleCreation(player *components.Player, entityID common.EntityID, creation *components.CharacterCreation, input string) {
+	input = strings.TrimSpace(input)
+
+	switch creation.Step {
+	case components.CreationName:
+		if problem := validateName(input); problem != "" {
+			player.Broadcast(problem)
+			player.Broadcast("By what name do you wish to be known?")
+			return
+		}
+		returning := g.accounts.Exists(input)
+		if returning && g.accountInUse(input) {
+			player.Broadcast(fmt.Sprintf("%s is already playing.", input))
+			player.Broadcast("By what name do you wish to be known?")
+			return
+		}
+		if _, ok := g.claimName(player, input); !ok {
+			player.Broadcast(fmt.Sprintf("The name %s is already taken.", input))
+			player.Broadcast("By what name do you wish to be known?")
+			return
+		}
+
+		creation.Returning = returning
+		creation.Step = components.CreationPassword
+		if returning {
+			player.Broadcast(fmt.Sprintf("Welcome back, %s. What is your password?", player.Name))
+		} else {
+			player.Broadcast(fmt.Sprintf("Choose a password for %s:", player.Name))
+		}
+
+	case components.CreationPassword:
+		if creation.Returning && !g.accounts.Check(player.Name, input) {
+			player.Broadcast(fmt.Sprintf("That is not the password for %s.", player.Name))
+			g.releaseName(player, creation)
+			return
+		}
+		if !creation.Returning {
+			if len(input) < minPasswordLength {
+				player.Broadcast(fmt.Sprintf("Passwords must be at least %d characters long.", minPasswordLength))
+				player.Broadcast(fmt.Sprintf("Choose a password for %s:", player.Name))
+				return
+			}
+			if !g.accounts.Register(player.Name, input) {
+				player.Broadcast(fmt.Sprintf("The name %s is already taken.", player.Name))
+				g.releaseName(player, creation)
+				return
+			}
+		}
+
+		player.Account = player.Name
+		creation.Step = components.CreationRace
+		player.Broadcast(describeRaces())
+
+	case components.CreationRace:
+		race := findRace(input)
+		if race == nil {
+			player.Broadcast("That isn't a race you can choose.")
+			player.Broadcast(describeRaces())
+			return
+		}
+
+		creation.Race = race
+		creation.Step = components.CreationClass
+		player.Broadcast(describeClasses())
+
+	case components.CreationClass:
+		class := findClass(input)
+		if class == nil {
+			player.Broadcast("That isn't a class you can choose.")
+			player.Broadcast(describeClasses())
+			return
+		}
+
+		g.finishCreation(player, entityID, creation.Race, class)
+	}
+}
+
+// releaseName gives up the name a player chose and asks them for another.
+func (g *Game) releaseName(player *components.Player, creation *components.CharacterCreation) {
+	g.claimName(player, util.GenerateRandomName())
+	creation.Step = components.CreationName
+	creation.Returning = false
+	player.Broadcast("By what name do you wish to be known?")
+}
+
+// accountInUse reports whether someone is already playing on account.
+func (g *Game) accountInUse(account string) bool {
+	key := components.AccountKey(account)
+	entities, _ := g.world.FindEntitiesByComponentPredicate("Player", func(i interface{}) bool {
+		p, ok := i.(*components.Player)
+		return ok && p.Account != "" && components.AccountKey(p.Account) == key
+	})
+	return len(entities) > 0
+}
+
+// validateName explains what is wrong with a name, or returns "" if it is
+// fine.
+func validateName(name string) string {
+	if len(name) < minNameLength || len(name) > maxNameLength {
+		return fmt.Sprintf("Names must be %d to %d characters long.", minNameLength, maxNameLength)
+	}
+	for i, r := range name {
+		if i == 0 && !unicode.IsLetter(r) {
+			return "Names must start with a letter."
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return "Names may only contain letters and numbers."
+		}
+	}
+	return ""
+}
+
+// finishCreation builds the character the player chose and brings them into
+// the world.
+func (g *Game) finishCreation(player *components.Player, entityID common.EntityID, race *components.Race, class *components.Class) {
+	g.playersMu.RLock()
+	playerEntity := g.players[player.Name]
+	g.playersMu.RUnlock()
+
+	character := components.NewCharacter(race, class)
+	if playerEntity != nil {
+		g.world.AddComponent(playerEntity, character)
+	}
+
+	attributes, _ := ecs.GetTypedComponent[*components.Attributes](g.world, entityID, "Attributes")
+	if attributes != nil {
+		attributes.ApplyModifiers(race.Modifiers)
+		attributes.ApplyModifiers(class.Modifiers)
+	}
+
+	inventory, _ := ecs.GetTypedComponent[*components.Inventory](g.world, entityID, "Inventory")
+	equipment, _ := ecs.GetTypedComponent[*components.Equipment](g.world, entityID, "Equipment")
+	for _, start := range class.StartingItems {
+		item := components.CreateItem(start.ItemID, start.Quantity)
+		if item == nil {
+			log.Warn().Msgf("Class %s starts with unknown item %s", class.ID, start.ItemID)
+			continue
+		}
+		if start.Equip && item.Slot != components.SlotNone && equipment != nil {
+			if previous := equipment.Equip(item); previous != nil && inventory != nil {
+				inventory.AddItem(previous)
+			}
+			continue
+		}
+		if inventory != nil {
+			inventory.AddItem(item)
+		}
+	}
+
+	level := 1
+	if experience, err := ecs.GetTypedComponent[*components.Experience](g.world, entityID, "Experience"); err == nil {
+		level = experience.GetLevel()
+	}
+
+	// Attributes and gear both change how much health a new character has
+	if health, err := ecs.GetTypedComponent[*components.Health](g.world, entityID, "Health"); err == nil {
+		health.Lock()
+		health.Max = components.PlayerMaxHP(level, attributes, equipment)
+		health.Current = health.Max
+		health.Unlock()
+	}
+
+	g.refreshPools(entityID, true)
+
+	g.world.RemoveComponent(entityID, "CharacterCreation")
+
+	area := g.startArea(race)
+	player.Area = area
+	area.AddPlayer(player)
+
+	// New characters return to life where they started until they bind
+	// themselves to a shrine
+	if bind, err := ecs.GetTypedComponent[*components.BindPoint](g.world, entityID, "BindPoint"); err == nil {
+		if areaID, ok := g.areaID(area); ok {
+			bind.Set(areaID)
+		}
+	}
+
+	player.Broadcast(fmt.Sprintf("Welcome, %s the %s!", player.Name, character.Describe()))
+	systems.LearnAbilities(g.world.AsWorldLike(), entityID, player, level)
+	player.Look(g.world.AsWorldLike())
+	player.BroadcastState(g.world.AsWorldLike(), entityID)
+
+	g.Broadcast(fmt.Sprintf("%s has joined the game.", player.Name), recording.Unwrap(player.Client))
+}
+
+// startArea is where new characters of race begin, falling back to the
+// default area if the race doesn't name one or it doesn't exist.
+func (g *Game) startArea(race *components.Race) *components.Area {
+	if race.StartArea == "" {
+		return g.defaultArea
+	}
+	area, err := ecs.GetTypedComponent[*components.Area](g.world, common.EntityID(race.StartArea), "Area")
+	if err != nil {
+		log.Warn().Msgf("Race %s starts in unknown area %s", race.ID, race.StartArea)
+		return g.defaultArea
+	}
+	return area
+}
+
+func findRace(name string) *components.Race {
+	name = strings.ToLower(name)
+	for _, id := range components.RaceOrder {
+		race := components.Races[id]
+		if race.ID == name || strings.ToLower(race.Name) == name {
+			return race
+		}
+	}
+	return nil
+}
+
+func findClass(name string) *components.Class {
+	name = strings.ToLower(name)
+	for _, id := range components.ClassOrder {
+		class := components.Classes[id]
+		if class.ID == name || strings.ToLower(class.Name) == name {
+			return class
+		}
+	}
+	return nil
+}
+
+func describeRaces() string {
+	var output strings.Builder
+	output.WriteString("==============================================\n")
+	output.WriteString("               CHOOSE YOUR RACE               \n")
+	output.WriteString("==============================================\n\n")
+	for _, id := range components.RaceOrder {
+		race := components.Races[id]
+		output.WriteString(fmt.Sprintf("  %-10s %s\n", race.Name, race.Description))
+		output.WriteString(fmt.Sprintf("  %-10s %s\n\n", "", formatModifiers(race.Modifiers)))
+	}
+	output.WriteString("Type the name of your race.\n")
+	return output.String()
+}
+
+func describeClasses() string {
+	var output strings.Builder
+	output.WriteString("==============================================\n")
+	output.WriteString("              CHOOSE YOUR CLASS               \n")
+	output.WriteString("==============================================\n\n")
+	for _, id := range components.ClassOrder {
+		class := components.Classes[id]
+		output.WriteString(fmt.Sprintf("  %-10s %s\n", class.Name, class.Description))
+		output.WriteString(fmt.Sprintf("  %-10s %s\n", "", formatModifiers(class.Modifiers)))
+		if len(class.Abilities) > 0 {
+			output.WriteString(fmt.Sprintf("  %-10s Abilities: %s\n", "", strings.Join(class.Abilities, ", ")))
+		}
+		output.WriteString("\n")
+	}
+	output.WriteString("Type the name of your class.\n")
+	return output.String()
+}
+
+// formatModifiers lists attribute modifiers like "DEX +2, CON -2".
+func formatModifiers(modifiers map[components.Attribute]int) string {
+	var parts []string
+	for _, attr := range components.AllAttributes {
+		if value := modifiers[attr]; value != 0 {
+			parts = append(parts, fmt.Sprintf("%s %+d", attr.Abbrev(), value))
+		}
+	}
+	if len(parts) == 0 {
+		return "No attribute changes"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,191 @@
+package game
+
+import (
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"dmud/internal/systems"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ghostCommands are the commands a ghost can still use while they wait to
+// return to life.
+var ghostCommands = map[string]bool{
+	"look":      true,
+	"who":       true,
+	"exit":      true,
+	"say":       true,
+	"shout":     true,
+	"examine":   true,
+	"score":     true,
+	"abilities": true,
+	"inventory": true,
+	"equipment": true,
+	"balance":   true,
+	"time":      true,
+	"history":   true,
+	"clear":     true,
+	"suggest":   true,
+	"complete":  true,
+	"help":      true,
+	"uptime":    true,
+	"release":   true,
+	"consent":   true,
+}
+
+// isGhost reports whether the player is dead and waiting to return to life.
+func (g *Game) isGhost(player *components.Player) bool {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		return false
+	}
+	_, err = g.world.GetComponent(playerEntity, "Ghost")
+	return err == nil
+}
+
+func (g *Game) handleRelease(player *components.Player, args []string, game *Game) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	if !g.isGhost(player) {
+		player.Broadcast("You aren't dead.")
+		return
+	}
+	systems.Respawn(g.world, playerEntity)
+}
+
+func (g *Game) handleBind(player *components.Player, args []string, game *Game) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	if !player.Area.HasFlag(components.ShrineFlag) {
+		player.Broadcast("There is no shrine here to bind yourself to.")
+		return
+	}
+
+	bind, err := ecs.GetTypedComponent[*components.BindPoint](g.world, playerEntity, "BindPoint")
+	if err != nil {
+		return
+	}
+	areaID, ok := g.areaID(player.Area)
+	if !ok {
+		return
+	}
+	if bind.Get() == areaID {
+		player.Broadcast("You are already bound to this shrine.")
+		return
+	}
+
+	bind.Set(areaID)
+	player.Broadcast("You kneel at the shrine. When you die, you will return to life here.")
+	player.Area.Broadcast(fmt.Sprintf("%s kneels at the shrine.", player.Name), player)
+}
+
+func (g *Game) handleConsent(player *components.Player, args []string, game *Game) {
+	consent := g.consents.Get(player.Account)
+
+	if len(args) == 0 {
+		names := consent.List()
+		if len(names) == 0 {
+			player.Broadcast("Nobody else may loot your corpse. Type 'consent <player>' to allow someone to.")
+			return
+		}
+		// Consent is kept by account, which may not match the name a
+		// player goes by now
+		player.Broadcast(fmt.Sprintf("You allow these accounts to loot your corpse: %s", strings.Join(names, ", ")))
+		return
+	}
+
+	if strings.ToLower(args[0]) == "revoke" {
+		if len(args) < 2 {
+			player.Broadcast("Usage: consent revoke <player>")
+			return
+		}
+
+		// Someone playing is looked up to their account like a grant;
+		// anyone else is taken to be named by their account
+		account := args[1]
+		if other := g.findPlayerByName(args[1]); other != nil {
+			account = other.Account
+		}
+		if !consent.Revoke(account) {
+			player.Broadcast(fmt.Sprintf("%s doesn't have your consent.", args[1]))
+			return
+		}
+		player.Broadcast(fmt.Sprintf("%s may no longer loot your corpse.", args[1]))
+		return
+	}
+
+	// Consent goes to the account behind the name, so it still holds if
+	// they rename themselves or log out and back in
+	other := g.findPlayerByName(args[0])
+	if other == nil {
+		player.Broadcast(fmt.Sprintf("There is no one called %s playing.", args[0]))
+		return
+	}
+	if other == player {
+		player.Broadcast("You can always loot your own corpse.")
+		return
+	}
+
+	consent.Grant(other.Account)
+	player.Broadcast(fmt.Sprintf("%s may now loot your corpse.", other.Name))
+}
+
+// findPlayerByName returns the player in the game called name, ignoring case.
+func (g *Game) findPlayerByName(name string) *components.Player {
+	g.playersMu.RLock()
+	defer g.playersMu.RUnlock()
+
+	for playerName, entity := range g.players {
+		if !strings.EqualFold(playerName, name) {
+			continue
+		}
+		if player, err := ecs.GetTypedComponent[*components.Player](g.world, entity.ID, "Player"); err == nil {
+			return player
+		}
+	}
+	return nil
+}
+
+// mayLoot reports whether the player may take from corpse.
+func (g *Game) mayLoot(player *components.Player, corpse *components.Corpse) bool {
+	corpse.RLock()
+	owner := corpse.Owner
+	corpse.RUnlock()
+
+	var consent *components.Consent
+	if owner != "" {
+		consent = g.consents.Get(owner)
+	}
+	return corpse.CanLoot(player.Account, consent)
+}
+
+// canLoot reports whether the player may take from corpse, telling them why
+// not if they can't.
+func (g *Game) canLoot(player *components.Player, corpse *components.Corpse) bool {
+	if g.mayLoot(player, corpse) {
+		return true
+	}
+	player.Broadcast(fmt.Sprintf("You need %s's consent to loot their corpse.", corpse.VictimName))
+	return false
+}
+
+// areaID returns the ID of the entity holding area.
+func (g *Game) areaID(area *components.Area) (string, bool) {
+	entities, _ := g.world.FindEntitiesByComponentPredicate("Area", func(i interface{}) bool {
+		return i == area
+	})
+	if len(entities) == 0 {
+		return "", false
+	}
+	return string(entities[0].ID), true
+}
@@ -0,0 +1,243 @@
+package game
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (g *Game) handleWear(player *components.Player, args []string, game *Game) {
+	if len(args) == 0 {
+		player.Broadcast("Wear what? Usage: wear <item>")
+		return
+	}
+	g.equipItem(player, strings.ToLower(strings.Join(args, " ")), false)
+}
+
+func (g *Game) handleWield(player *components.Player, args []string, game *Game) {
+	if len(args) == 0 {
+		player.Broadcast("Wield what? Usage: wield <item>")
+		return
+	}
+	g.equipItem(player, strings.ToLower(strings.Join(args, " ")), true)
+}
+
+func (g *Game) equipItem(player *components.Player, itemName string, wield bool) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	inventory, err := ecs.GetTypedComponent[*components.Inventory](g.world, playerEntity, "Inventory")
+	if err != nil {
+		player.Broadcast("You don't have an inventory!")
+		return
+	}
+
+	equipment, err := ecs.GetTypedComponent[*components.Equipment](g.world, playerEntity, "Equipment")
+	if err != nil {
+		player.Broadcast("You can't equip anything.")
+		return
+	}
+
+	item := findItemByName(inventory.GetItems(), itemName)
+	if item == nil {
+		player.Broadcast("You don't have that item.")
+		return
+	}
+
+	switch {
+	case wield && item.Type != components.ItemTypeWeapon:
+		player.Broadcast(fmt.Sprintf("You can't wield %s.", item.Name))
+		return
+	case !wield && item.Type == components.ItemTypeWeapon:
+		player.Broadcast(fmt.Sprintf("You wield weapons, not wear them. Try 'wield %s'.", itemName))
+		return
+	case item.Slot == components.SlotNone:
+		player.Broadcast(fmt.Sprintf("You can't wear %s.", item.Name))
+		return
+	}
+
+	if experience, err := ecs.GetTypedComponent[*components.Experience](g.world, playerEntity, "Experience"); err == nil {
+		if level := experience.GetLevel(); level < item.LevelRequirement {
+			player.Broadcast(fmt.Sprintf("You must be level %d to use %s.", item.LevelRequirement, item.Name))
+			return
+		}
+	}
+
+	removed := inventory.RemoveItem(item.Key(), 1)
+	if removed == nil {
+		player.Broadcast("You don't have that item.")
+		return
+	}
+
+	hpChange := removed.ActiveModifiers().MaxHP
+	if previous := equipment.Equip(removed); previous != nil {
+		inventory.AddItem(previous)
+		hpChange -= previous.ActiveModifiers().MaxHP
+		player.Broadcast(fmt.Sprintf("You stop using %s.", previous.Name))
+	}
+
+	if wield {
+		player.Broadcast(fmt.Sprintf("You wield %s.", removed.Name))
+		player.Area.Broadcast(fmt.Sprintf("%s wields %s.", player.Name, removed.Name), player)
+	} else {
+		player.Broadcast(fmt.Sprintf("You wear %s on your %s.", removed.Name, removed.Slot))
+		player.Area.Broadcast(fmt.Sprintf("%s wears %s.", player.Name, removed.Name), player)
+	}
+
+	g.refreshCombatDamage(playerEntity)
+	g.adjustMaxHealth(player, playerEntity, hpChange)
+}
+
+func (g *Game) handleRemove(player *components.Player, args []string, game *Game) {
+	if len(args) == 0 {
+		player.Broadcast("Remove what? Usage: remove <item|slot>")
+		return
+	}
+
+	target := strings.ToLower(strings.Join(args, " "))
+
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	inventory, err := ecs.GetTypedComponent[*components.Inventory](g.world, playerEntity, "Inventory")
+	if err != nil {
+		player.Broadcast("You don't have an inventory!")
+		return
+	}
+
+	equipment, err := ecs.GetTypedComponent[*components.Equipment](g.world, playerEntity, "Equipment")
+	if err != nil {
+		player.Broadcast("You aren't wearing anything.")
+		return
+	}
+
+	slot := components.SlotNone
+	for _, item := range equipment.GetItems() {
+		if item.Slot.String() == target || strings.Contains(strings.ToLower(item.Name), target) {
+			slot = item.Slot
+			break
+		}
+	}
+
+	if slot == components.SlotNone {
+		player.Broadcast("You aren't using that.")
+		return
+	}
+
+	if inventory.IsFull() {
+		player.Broadcast("You have no room to carry that.")
+		return
+	}
+
+	item := equipment.Unequip(slot)
+	inventory.AddItem(item)
+
+	player.Broadcast(fmt.Sprintf("You remove %s.", item.Name))
+	player.Area.Broadcast(fmt.Sprintf("%s removes %s.", player.Name, item.Name), player)
+
+	g.refreshCombatDamage(playerEntity)
+	g.adjustMaxHealth(player, playerEntity, -item.ActiveModifiers().MaxHP)
+}
+
+func (g *Game) handleEquipment(player *components.Player, args []string, game *Game) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	equipment, err := ecs.GetTypedComponent[*components.Equipment](g.world, playerEntity, "Equipment")
+	if err != nil {
+		player.Broadcast("You aren't wearing anything.")
+		return
+	}
+
+	var output strings.Builder
+	output.WriteString("==============================================\n")
+	output.WriteString("                 EQUIPMENT                    \n")
+	output.WriteString("==============================================\n\n")
+
+	for _, slot := range components.EquipmentSlots {
+		name := "(nothing)"
+		if item := equipment.Get(slot); item != nil {
+			name = item.ColoredName()
+			if item.IsBroken() {
+				name += " (broken)"
+			}
+		}
+		output.WriteString(fmt.Sprintf("  %-12s %s\n", slot.String()+":", name))
+	}
+
+	minDamage, maxDamage := g.playerDamageRange(playerEntity)
+	output.WriteString(fmt.Sprintf("\nDamage: %d-%d   Armor: %d\n", minDamage, maxDamage, equipment.TotalModifiers().Armor))
+	output.WriteString("==============================================\n")
+
+	player.Broadcast(output.String())
+}
+
+// playerDamageRange returns the damage range a player's current equipment
+// and Strength give them.
+func (g *Game) playerDamageRange(entityID common.EntityID) (int, int) {
+	equipment, _ := ecs.GetTypedComponent[*components.Equipment](g.world, entityID, "Equipment")
+	attributes, _ := ecs.GetTypedComponent[*components.Attributes](g.world, entityID, "Attributes")
+	return components.PlayerDamageRange(equipment, attributes)
+}
+
+// refreshCombatDamage applies an equipment change to a fight already in progress.
+func (g *Game) refreshCombatDamage(entityID common.EntityID) {
+	combat, err := ecs.GetTypedComponent[*components.Combat](g.world, entityID, "Combat")
+	if err != nil {
+		return
+	}
+
+	minDamage, maxDamage := g.playerDamageRange(entityID)
+	combat.Lock()
+	combat.MinDamage = minDamage
+	combat.MaxDamage = maxDamage
+	combat.Unlock()
+}
+
+// adjustMaxHealth applies a change in the max HP granted by equipment.
+// Putting on gear adds the HP straight away; taking it off can't leave the
+// player above their new maximum.
+func (g *Game) adjustMaxHealth(player *components.Player, entityID common.EntityID, delta int) {
+	if delta == 0 {
+		return
+	}
+
+	health, err := ecs.GetTypedComponent[*components.Health](g.world, entityID, "Health")
+	if err != nil {
+		return
+	}
+
+	health.Lock()
+	health.AdjustMax(delta)
+	health.Unlock()
+
+	if delta > 0 {
+		player.Broadcast(fmt.Sprintf("Your maximum health increases by %d.", delta))
+	} else {
+		player.Broadcast(fmt.Sprintf("Your maximum health decreases by %d.", -delta))
+	}
+	player.BroadcastState(g.world.AsWorldLike(), entityID)
+}
+
+// findItemByName returns the first item whose name contains name.
+func findItemByName(items []*components.Item, name string) *components.Item {
+	for _, item := range items {
+		if strings.Contains(strings.ToLower(item.Name), name) {
+			return item
+		}
+	}
+	return nil
+}
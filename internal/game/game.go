@@ -2,6 +2,7 @@ package game
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"dmud/internal/common"
 	"dmud/internal/components"
 	"dmud/internal/ecs"
+	"dmud/internal/recording"
 	"dmud/internal/systems"
 	"dmud/internal/util"
 
@@ -31,14 +33,57 @@ type Command struct {
 	Hidden      bool
 }
 
-var commandRegistry = make(map[string]*Command)
+// Config controls where a game loads its world data from and whether
+// sessions are recorded.
+type Config struct {
+	ResourceDir string
+
+	// RecordDir enables session recording when set.
+	RecordDir string
+
+	// SaveDir is where accounts and bank vaults are kept between restarts;
+	// empty uses the saves directory under ResourceDir.
+	SaveDir string
+
+	// Seed seeds the world's random source; zero picks one from the clock.
+	Seed int64
+
+	// Clock replaces the wall clock. A game given one runs no loop of its
+	// own: the caller drives it with HandleConnect, HandleCommand and
+	// Advance, and time only passes when Advance is called, so the same
+	// commands at the same offsets play out the same way every time.
+	Clock *ecs.ManualClock
+
+	// CombatRound is how long a combat round lasts; zero uses
+	// components.DefaultCombatRound.
+	CombatRound time.Duration
+
+	// RegenTick is how often health, mana and stamina regenerate; zero uses
+	// components.DefaultRegenTick.
+	RegenTick time.Duration
+
+	// GhostDuration is how long dead players stay ghosts before returning
+	// to life; zero uses components.DefaultGhostDuration.
+	GhostDuration time.Duration
+}
 
 type Game struct {
+	config *Config
+
+	commands map[string]*Command
+
+	recorder *recording.Recorder
+	seed     int64
+
 	defaultArea *components.Area
 
 	players   map[string]*ecs.Entity
 	playersMu sync.RWMutex
 
+	accounts *components.AccountStore
+	vaults   *components.VaultStore
+	consents *components.ConsentStore
+
 	world *ecs.World
 
 	dayCycleSystem *systems.DayCycleSystem
@@ -47,29 +92,91 @@ type Game struct {
 	RemovePlayerChan   chan common.Client
 	ExecuteCommandChan chan ClientCommand
 
+	done     chan struct{}
+	stopOnce sync.Once
+
 	// Server stats
 	StartTime      time.Time
 	UniqueIPs      map[string]bool
 	UniqueIPsMu    sync.RWMutex
 	TotalConnects  int
 	TotalConnectMu sync.RWMutex
+	TickStats      TickStats
+}
+
+// tickInterval is how often the game loop drives World.Update.
+const tickInterval = 10 * time.Millisecond
+
+// TickStats tracks how reliably the game loop services its update ticker. A
+// tick counts as slipped when it arrives more than twice the tick interval
+// after the previous one, which means the loop was busy handling commands or
+// a long world update.
+type TickStats struct {
+	sync.RWMutex
+
+	Ticks        int
+	SlippedTicks int
+	MaxGap       time.Duration
+	lastTick     time.Time
+}
+
+func (ts *TickStats) record(now time.Time) {
+	ts.Lock()
+	defer ts.Unlock()
+
+	ts.Ticks++
+	if !ts.lastTick.IsZero() {
+		gap := now.Sub(ts.lastTick)
+		if gap > 2*tickInterval {
+			ts.SlippedTicks++
+		}
+		if gap > ts.MaxGap {
+			ts.MaxGap = gap
+		}
+	}
+	ts.lastTick = now
 }
 
 func NewGame() *Game {
-	combatSystem := &systems.CombatSystem{}
+	return NewGameWithConfig(&Config{ResourceDir: "./resources"})
+}
+
+// NewGameWithConfig boots a game against the resource files in config.ResourceDir
+// and starts its main loop.
+func NewGameWithConfig(config *Config) *Game {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	combatSystem := systems.NewCombatSystem(config.CombatRound)
 	movementSystem := &systems.MovementSystem{}
 	spawnSystem := systems.NewSpawnSystem()
 	aiSystem := systems.NewAISystem()
 	corpseSystem := systems.NewCorpseSystem()
+	groundItemSystem := systems.NewGroundItemSystem()
+	shopSystem := systems.NewShopSystem()
 	statusEffectSystem := systems.NewStatusEffectSystem()
-
-	world := ecs.NewWorld()
+	regenerationSystem := systems.NewRegenerationSystem(config.RegenTick)
+	respawnSystem := systems.NewRespawnSystem(config.GhostDuration)
+	duelSystem := systems.NewDuelSystem()
+
+	world := ecs.NewWorldFromFile(filepath.Join(config.ResourceDir, "areas.json"))
+	world.Seed(seed)
+	if config.Clock != nil {
+		world.SetClock(config.Clock)
+	}
 	world.AddSystem(combatSystem)
 	world.AddSystem(movementSystem)
 	world.AddSystem(spawnSystem)
 	world.AddSystem(aiSystem)
 	world.AddSystem(corpseSystem)
+	world.AddSystem(groundItemSystem)
+	world.AddSystem(shopSystem)
 	world.AddSystem(statusEffectSystem)
+	world.AddSystem(regenerationSystem)
+	world.AddSystem(respawnSystem)
+	world.AddSystem(duelSystem)
 
 	defaultAreaUntyped, err := world.GetComponent("1", "Area")
 	if err != nil {
@@ -81,13 +188,29 @@ func NewGame() *Game {
 		log.Fatal().Msg("Failed to cast default area to *components.Area")
 	}
 
+	saveDir := config.SaveDir
+	if saveDir == "" {
+		saveDir = filepath.Join(config.ResourceDir, "saves")
+	}
+	accounts, err := components.NewAccountStore(filepath.Join(saveDir, "accounts.json"))
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Failed to load accounts from %s", saveDir)
+	}
+
 	game := &Game{
+		config:             config,
+		accounts:           accounts,
+		commands:           make(map[string]*Command),
 		defaultArea:        defaultArea,
 		players:            make(map[string]*ecs.Entity),
 		world:              world,
 		AddPlayerChan:      make(chan common.Client, 64),
 		RemovePlayerChan:   make(chan common.Client, 64),
 		ExecuteCommandChan: make(chan ClientCommand, 256),
+		done:               make(chan struct{}),
+		seed:               seed,
+		vaults:             components.NewVaultStore(filepath.Join(saveDir, "vaults")),
+		consents:           components.NewConsentStore(),
 		StartTime:          time.Now(),
 		UniqueIPs:          make(map[string]bool),
 		TotalConnects:      0,
@@ -103,10 +226,21 @@ func NewGame() *Game {
 	// Give spawn system access to day cycle for night-only spawns
 	spawnSystem.SetDayCycle(dayCycleSystem.GetDayCycle())
 
+	if config.RecordDir != "" {
+		recorder, err := recording.NewRecorder(config.RecordDir, seed, world.Now)
+		if err != nil {
+			log.Error().Err(err).Msgf("Session recording disabled, could not use %s", config.RecordDir)
+		} else {
+			game.recorder = recorder
+		}
+	}
+
 	game.initCommands()
 	game.initializeSpawns()
 
-	go game.loop()
+	if config.Clock == nil {
+		go game.loop()
+	}
 
 	return game
 }
@@ -127,7 +261,7 @@ type areaSpawnJSON struct {
 
 func (g *Game) initializeSpawns() {
 	var areaSpawns []areaSpawnJSON
-	if err := util.ParseJSON("./resources/spawns.json", &areaSpawns); err != nil {
+	if err := util.ParseJSON(filepath.Join(g.config.ResourceDir, "spawns.json"), &areaSpawns); err != nil {
 		log.Error().Err(err).Msg("Failed to load spawns.json")
 		return
 	}
@@ -205,6 +339,75 @@ func (g *Game) initCommands() {
 		Handler:     handleExamine,
 		Description: "Examine something or someone in detail.",
 	})
+	g.RegisterCommand(&Command{
+		Name:        "score",
+		Aliases:     []string{"sc"},
+		Handler:     g.handleScore,
+		Description: "Show your level, attributes and combat stats.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "train",
+		Handler:     g.handleTrain,
+		Description: "Spend training points on your attributes.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "flee",
+		Handler:     g.handleFlee,
+		Description: "Try to escape a fight through a random exit.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "wimpy",
+		Handler:     g.handleWimpy,
+		Description: "Flee automatically when your health drops too low.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "pvp",
+		Handler:     g.handlePvP,
+		Description: "Choose whether to fight other players.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "duel",
+		Handler:     g.handleDuel,
+		Description: "Challenge another player to a duel.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "release",
+		Handler:     g.handleRelease,
+		Description: "Return to life at your bind point after dying.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "bind",
+		Handler:     g.handleBind,
+		Description: "Bind yourself to a shrine to return to life there.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "consent",
+		Handler:     g.handleConsent,
+		Description: "Allow another player to loot your corpse.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "rest",
+		Aliases:     []string{"sit"},
+		Handler:     g.handleRest,
+		Description: "Sit down and rest to recover faster.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "sleep",
+		Handler:     g.handleSleep,
+		Description: "Go to sleep to recover fastest of all.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "stand",
+		Aliases:     []string{"wake"},
+		Handler:     g.handleStand,
+		Description: "Get up after resting or sleeping.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "abilities",
+		Aliases:     []string{"skills"},
+		Handler:     g.handleAbilities,
+		Description: "List the abilities you know and their cooldowns.",
+	})
 	g.RegisterCommand(&Command{
 		Name:        "time",
 		Handler:     handleTime,
@@ -256,11 +459,136 @@ func (g *Game) initCommands() {
 		Handler:     g.handleGet,
 		Description: "Pick up an item from the ground.",
 	})
+	g.RegisterCommand(&Command{
+		Name:        "put",
+		Handler:     g.handlePut,
+		Description: "Put an item in a container.",
+	})
 	g.RegisterCommand(&Command{
 		Name:        "drop",
 		Handler:     g.handleDrop,
 		Description: "Drop an item from your inventory.",
 	})
+	g.RegisterCommand(&Command{
+		Name:        "eat",
+		Handler:     g.handleEat,
+		Description: "Eat some food.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "drink",
+		Aliases:     []string{"quaff"},
+		Handler:     g.handleDrink,
+		Description: "Drink a potion or beverage.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "use",
+		Handler:     g.handleUse,
+		Description: "Use an item.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "craft",
+		Aliases:     []string{"cook"},
+		Handler:     g.handleCraft,
+		Description: "Craft an item from a recipe.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "recipes",
+		Handler:     g.handleRecipes,
+		Description: "List the recipes you know.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "balance",
+		Aliases:     []string{"bal", "money"},
+		Handler:     g.handleBalance,
+		Description: "Show how much money you have.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "give",
+		Handler:     g.handleGive,
+		Description: "Give money to another player.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "list",
+		Handler:     g.handleList,
+		Description: "List a merchant's wares.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "buy",
+		Handler:     g.handleBuy,
+		Description: "Buy an item from a merchant.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "sell",
+		Handler:     g.handleSell,
+		Description: "Sell an item to a merchant.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "value",
+		Handler:     g.handleValue,
+		Description: "Ask a merchant what they'd pay for an item.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "repair",
+		Handler:     g.handleRepair,
+		Description: "Have a smith repair your gear.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "bank",
+		Handler:     g.handleBank,
+		Description: "See what is in your bank vault.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "deposit",
+		Handler:     g.handleDeposit,
+		Description: "Put items or money in your bank vault.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "withdraw",
+		Handler:     g.handleWithdraw,
+		Description: "Take items or money out of your bank vault.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "trade",
+		Handler:     g.handleTrade,
+		Description: "Trade items and money with another player.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "offer",
+		Handler:     g.handleOffer,
+		Description: "Offer an item or money in a trade.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "retract",
+		Handler:     g.handleRetract,
+		Description: "Take something back out of a trade.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "accept",
+		Handler:     g.handleAccept,
+		Description: "Accept a trade as it stands.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "wear",
+		Handler:     g.handleWear,
+		Description: "Wear a piece of armor.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "wield",
+		Handler:     g.handleWield,
+		Description: "Wield a weapon.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "remove",
+		Aliases:     []string{"rem"},
+		Handler:     g.handleRemove,
+		Description: "Remove an equipped item.",
+	})
+	g.RegisterCommand(&Command{
+		Name:        "equipment",
+		Aliases:     []string{"eq"},
+		Handler:     g.handleEquipment,
+		Description: "Show what you are wearing and wielding.",
+	})
 	g.RegisterCommand(&Command{
 		Name:        "hail",
 		Handler:     g.handleHail,
@@ -293,16 +621,20 @@ func (g *Game) initCommands() {
 			Description: "Move " + dir,
 		})
 	}
+
+	g.registerAbilityCommands()
 }
 
 func (g *Game) RegisterCommand(cmd *Command) {
-	commandRegistry[cmd.Name] = cmd
+	g.commands[cmd.Name] = cmd
 	for _, alias := range cmd.Aliases {
-		commandRegistry[alias] = cmd
+		g.commands[alias] = cmd
 	}
 }
 
-func (g *Game) handleCommand(c ClientCommand) {
+// HandleCommand runs a command a client sent. The game loop calls it for
+// everything on ExecuteCommandChan.
+func (g *Game) HandleCommand(c ClientCommand) {
 	client := c.Client
 
 	cmdInput := c.Cmd
@@ -319,10 +651,31 @@ func (g *Game) handleCommand(c ClientCommand) {
 	if len(cmdArgs) > 0 {
 		fullCommand = cmdInput + " " + strings.Join(cmdArgs, " ")
 	}
-	player.CommandHistory.AddCommand(fullCommand)
+	playerEntity, creation := g.characterCreation(player)
+
+	// Passwords are kept out of the history and session recordings
+	if creation != nil && creation.Step == components.CreationPassword {
+		if rc, ok := player.Client.(*recording.Client); ok {
+			rc.RecordInput(redactedPassword)
+		}
+	} else {
+		player.CommandHistory.AddCommand(fullCommand)
+		if rc, ok := player.Client.(*recording.Client); ok {
+			rc.RecordInput(fullCommand)
+		}
+	}
+
+	// Players still creating their character answer prompts, not commands
+	if creation != nil {
+		g.handleCreation(player, playerEntity, creation, fullCommand)
+		if client.SupportsPrompt() {
+			player.Client.SendMessage("> ")
+		}
+		return
+	}
 
 	// Update auto-complete with all available commands
-	for cmdName, cmd := range commandRegistry {
+	for cmdName, cmd := range g.commands {
 		if cmd.Hidden {
 			continue
 		}
@@ -336,8 +689,10 @@ func (g *Game) handleCommand(c ClientCommand) {
 	}
 	g.playersMu.RUnlock()
 
-	cmd, exists := commandRegistry[cmdInput]
-	if exists {
+	cmd, exists := g.commands[cmdInput]
+	if exists && !ghostCommands[cmd.Name] && g.isGhost(player) {
+		player.Broadcast("You are a ghost and can't do that. Type 'release' to return to life.")
+	} else if exists {
 		cmd.Handler(player, cmdArgs, g)
 	} else {
 		player.Broadcast(fmt.Sprintf("What do you mean, \"%s\"?", cmdInput))
@@ -345,31 +700,65 @@ func (g *Game) handleCommand(c ClientCommand) {
 
 	// Send prompt after command is processed
 	if client.SupportsPrompt() {
-		client.SendMessage("> ")
+		player.Client.SendMessage(g.prompt(player))
 	}
 }
 
 func (g *Game) HandleConnect(c common.Client) {
+	// Commands keep arriving with the raw client; getPlayer unwraps to match
+	if g.recorder != nil {
+		c = g.recorder.Wrap(c)
+	}
+
 	playerComponent := &components.Player{
 		Client:         c,
-		Name:           util.GenerateRandomName(),
-		Area:           g.defaultArea,
+		Name:           util.GenerateRandomName(), // Placeholder until they choose a name
 		CommandHistory: components.NewCommandHistory(),
 		AutoComplete:   util.NewAutoComplete(),
 	}
 	experienceComponent := components.NewExperience()
+	attributesComponent := components.NewAttributes()
 	healthComponent := components.NewHealth(experienceComponent.Level)
+	manaComponent := components.NewMana(components.PlayerMaxMana(experienceComponent.Level, attributesComponent))
+	staminaComponent := components.NewStamina(components.PlayerMaxStamina(experienceComponent.Level, attributesComponent))
+	postureComponent := components.NewPosture()
 	inventoryComponent := components.NewInventory(20) // 20 slot inventory
+	equipmentComponent := components.NewEquipment()
+	walletComponent := components.NewWallet()
+	recipeBookComponent := components.NewRecipeBook()
+	craftingSkillComponent := components.NewCraftingSkill()
 	questsComponent := components.NewPlayerQuests()
+	tradeComponent := components.NewTrade()
+	skillsComponent := components.NewSkills()
+	wimpyComponent := components.NewWimpy()
+	bindPointComponent := components.NewBindPoint(systems.DefaultBindArea)
+	pvpComponent := components.NewPvP()
+	duelComponent := components.NewDuel()
+	creationComponent := components.NewCharacterCreation()
 
 	playerEntity := ecs.NewEntity()
 	g.world.AddEntity(playerEntity)
 
 	g.world.AddComponent(&playerEntity, playerComponent)
 	g.world.AddComponent(&playerEntity, experienceComponent)
+	g.world.AddComponent(&playerEntity, attributesComponent)
 	g.world.AddComponent(&playerEntity, healthComponent)
+	g.world.AddComponent(&playerEntity, manaComponent)
+	g.world.AddComponent(&playerEntity, staminaComponent)
+	g.world.AddComponent(&playerEntity, postureComponent)
 	g.world.AddComponent(&playerEntity, inventoryComponent)
+	g.world.AddComponent(&playerEntity, equipmentComponent)
+	g.world.AddComponent(&playerEntity, walletComponent)
+	g.world.AddComponent(&playerEntity, recipeBookComponent)
+	g.world.AddComponent(&playerEntity, craftingSkillComponent)
 	g.world.AddComponent(&playerEntity, questsComponent)
+	g.world.AddComponent(&playerEntity, tradeComponent)
+	g.world.AddComponent(&playerEntity, skillsComponent)
+	g.world.AddComponent(&playerEntity, wimpyComponent)
+	g.world.AddComponent(&playerEntity, bindPointComponent)
+	g.world.AddComponent(&playerEntity, pvpComponent)
+	g.world.AddComponent(&playerEntity, duelComponent)
+	g.world.AddComponent(&playerEntity, creationComponent)
 
 	g.playersMu.Lock()
 	g.players[playerComponent.Name] = &playerEntity
@@ -394,13 +783,9 @@ func (g *Game) HandleConnect(c common.Client) {
 	g.UniqueIPs[ipAddr] = true
 	g.UniqueIPsMu.Unlock()
 
-	g.defaultArea.AddPlayer(playerComponent)
-
+	// New players enter the world once they have created their character
 	playerComponent.Broadcast(util.WelcomeBanner)
-	playerComponent.Look(g.world.AsWorldLike())
-	playerComponent.BroadcastState(g.world.AsWorldLike(), playerEntity.ID)
-
-	g.Broadcast(fmt.Sprintf("%s has joined the game.", playerComponent.Name), c)
+	playerComponent.Broadcast("By what name do you wish to be known?")
 
 	// Send initial prompt
 	if c.SupportsPrompt() {
@@ -425,12 +810,19 @@ func (g *Game) HandleDisconnect(c common.Client) {
 		log.Error().Msg("Player entity was nil")
 		return
 	}
+	_, err = g.world.GetComponent(playerEntity.ID, "CharacterCreation")
+	created := err != nil
+	g.cancelTradesFor(player, playerEntity.ID)
 	g.world.RemoveEntity(playerEntity.ID)
 	delete(g.players, player.Name)
 	g.playersMu.Unlock()
 
-	c.CloseConnection()
-	g.Broadcast(fmt.Sprintf("%s has left the game.", player.Name), c)
+	player.Client.CloseConnection()
+
+	// Nobody saw a player who left before finishing their character
+	if created {
+		g.Broadcast(fmt.Sprintf("%s has left the game.", player.Name), c)
+	}
 }
 
 func (g *Game) getPlayer(c common.Client) (*components.Player, error) {
@@ -446,7 +838,7 @@ func (g *Game) getPlayer(c common.Client) (*components.Player, error) {
 		if !ok {
 			return nil, fmt.Errorf("unable to cast component to Player")
 		}
-		if player.Client == c {
+		if recording.Unwrap(player.Client) == c {
 			return player, nil
 		}
 	}
@@ -472,25 +864,40 @@ func (g *Game) Broadcast(m string, excludeClients ...common.Client) {
 			continue
 		}
 
-		if !util.ContainsClient(excludeClients, player.Client) {
+		if !util.ContainsClient(excludeClients, recording.Unwrap(player.Client)) {
 			player.Broadcast(m)
 		}
 	}
 }
 
+// World exposes the game's ECS world, mainly for tests and tooling.
+func (g *Game) World() *ecs.World {
+	return g.world
+}
+
+// Stop ends the game loop. It is safe to call more than once.
+func (g *Game) Stop() {
+	g.stopOnce.Do(func() {
+		close(g.done)
+	})
+}
+
 func (g *Game) loop() {
-	updateTicker := time.NewTicker(10 * time.Millisecond)
+	updateTicker := time.NewTicker(tickInterval)
 	defer updateTicker.Stop()
 
 	for {
 		select {
+		case <-g.done:
+			return
 		case client := <-g.AddPlayerChan:
 			g.HandleConnect(client)
 		case client := <-g.RemovePlayerChan:
 			g.HandleDisconnect(client)
 		case command := <-g.ExecuteCommandChan:
-			g.handleCommand(command)
-		case <-updateTicker.C:
+			g.HandleCommand(command)
+		case now := <-updateTicker.C:
+			g.TickStats.record(now)
 			g.world.Update()
 		}
 	}
@@ -0,0 +1,661 @@
+package game_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"dmud/internal/components"
+	"dmud/internal/game/gametest"
+	"dmud/internal/recording"
+)
+
+func TestConnectAndLook(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+
+	alice.Expect("look", "TEST CROSSROADS")
+}
+
+func TestPlayersSeeEachOther(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+	bob := h.Connect("bob")
+
+	alice.Expect("look", "bob is here.")
+	bob.Expect("say hello", "You say: hello")
+	alice.WaitFor("bob says: hello")
+}
+
+func TestKillRatAndLootCorpse(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+	h.SpawnNPC("rat", "2")
+
+	alice.Expect("north", "TEST FIELD")
+	alice.Expect("kill rat", "You have defeated a small rat!")
+	alice.Expect("look", "the corpse of a small rat is here.")
+	alice.Expect("loot corpse", "You looted: Rat Fur, Rat Tail")
+	alice.Expect("inventory", "Rat Tail")
+}
+
+func TestUnknownCommand(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+
+	alice.Expect("dance", `What do you mean, "dance"?`)
+}
+
+func TestWieldWearAndRemove(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+	h.GiveItem("alice", "rusty_dagger", 1)
+	h.GiveItem("alice", "leather_chest", 1)
+
+	alice.Expect("wear dagger", "You wield weapons, not wear them.")
+	alice.Expect("wield dagger", "You wield Rusty Dagger.")
+	alice.Expect("wear chestpiece", "You wear Leather Chestpiece on your chest.")
+	alice.Expect("equipment", "Damage: 12-56   Armor: 3")
+
+	alice.Expect("remove chest", "You remove Leather Chestpiece.")
+	alice.Expect("inventory", "Leather Chestpiece")
+	alice.Expect("equipment", "Damage: 12-56   Armor: 0")
+}
+
+func TestDropAndGetGroundItems(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+	bob := h.Connect("bob")
+	h.GiveItem("alice", "rat_fur", 1)
+	h.GiveItem("alice", "rat_tail", 3)
+
+	alice.Expect("drop tail", "You dropped Rat Tail.")
+	alice.Expect("drop all", "You dropped Rat Fur, Rat Tail (x2).")
+	bob.Expect("look", "Rat Tail (x3) is lying here.")
+	bob.Expect("get all.tail", "You pick up Rat Tail (x3).")
+	alice.WaitFor("bob picks up Rat Tail (x3).")
+	bob.Expect("get fur", "You pick up Rat Fur.")
+	bob.Expect("get all", "There is nothing here to pick up.")
+}
+
+func TestMerchantBuySellAndValue(t *testing.T) {
+	h := gametest.New(t)
+	h.SpawnNPC("merchant", "1")
+	alice := h.Connect("alice")
+	h.GiveItem("alice", "goblin_ear", 2)
+	h.GiveMoney("alice", 14)
+
+	alice.Expect("value ear", "I'll give you 3 copper for Goblin Ear.")
+	alice.Expect("sell ear", "You sell Goblin Ear for 3 copper.")
+	alice.Expect("buy helmet", "Leather Helmet costs 7 silver, 5 copper. You can't afford it.")
+	alice.Expect("buy dagger", "You buy Rusty Dagger for 1 silver, 5 copper.")
+	alice.Expect("list", "Rusty Dagger             1 silver, 5 copper (1 left)")
+	alice.Expect("balance", "You are carrying 2 copper.")
+
+	// A bag is only bought empty, so nothing inside is lost with it
+	h.GiveItem("alice", "small_bag", 1)
+	alice.Expect("put ear in bag", "You put Goblin Ear in Small Bag.")
+	alice.Expect("value bag", "Empty Small Bag first; I only buy the bag.")
+	alice.Expect("sell bag", "Empty Small Bag first; I only buy the bag.")
+	alice.Expect("get ear from bag", "You get Goblin Ear from Small Bag.")
+	alice.Expect("sell bag", "You sell Small Bag for 1 silver.")
+
+	// The merchant was already holding all the bags they stock
+	alice.Expect("list", "Small Bag                3 silver         (2 left)")
+}
+
+func TestLootCoinsAndGiveMoney(t *testing.T) {
+	h := gametest.New(t)
+	h.SpawnNPC("goblin", "1")
+	alice := h.Connect("alice")
+	bob := h.Connect("bob")
+
+	alice.Expect("kill goblin", "You have defeated a sneaky goblin!")
+	alice.Expect("loot goblin", "You looted: Goblin Ear, 3 silver")
+	alice.Expect("give 1 gold bob", "You don't have 1 gold.")
+	alice.Expect("give 2 silver bob", "You give 2 silver to bob.")
+	bob.WaitFor("alice gives you 2 silver.")
+	alice.Expect("give 92233720368547759 gold bob", "You can't handle more than")
+	alice.Expect("balance", "You are carrying 1 silver.")
+	bob.Expect("balance", "received from alice")
+}
+
+func TestConsumables(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+	h.GiveItem("alice", "healing_potion", 2)
+	h.GiveItem("alice", "vigor_tonic", 1)
+	h.GiveItem("alice", "recipe_cooked_chicken", 1)
+	h.GiveItem("alice", "rat_fur", 1)
+
+	alice.Expect("drink potion", "You are already at full health.")
+	alice.Expect("eat potion", "You can't eat Minor Healing Potion.")
+	alice.Expect("use fur", "You can't use Rat Fur.")
+	alice.Expect("quaff tonic", "You feel the Vigor take hold. (+50 HP)")
+	alice.Expect("drink tonic", "You don't have that item.")
+	alice.Expect("use recipe", "You learn how to make cooked chicken.")
+	alice.Expect("inventory", "Minor Healing Potion           x2")
+}
+
+func TestCraftingRecipes(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+	h.GiveItem("alice", "raw_chicken", 2)
+	h.GiveItem("alice", "recipe_cooked_chicken", 1)
+	h.GiveItem("alice", "chicken_feather", 3)
+	h.GiveItem("alice", "bone", 1)
+
+	alice.Expect("craft cooked chicken", "You don't know how to make that.")
+	alice.Expect("craft arrows", "You need Rusty Dagger to make Bundle of Arrows.")
+	alice.Expect("craft fur cap", "You need crafting skill 3 to make Fur Cap.")
+	alice.Expect("use recipe", "You learn how to make cooked chicken.")
+	alice.Expect("cook chicken", "You need a campfire to make Cooked Chicken.")
+	alice.Expect("north", "with a campfire")
+	alice.Expect("cook chicken", "You make 1 x Cooked Chicken. (+10 crafting XP)")
+
+	// A wielded tool works as well as a carried one
+	h.GiveItem("alice", "rusty_dagger", 1)
+	alice.Expect("wield dagger", "You wield Rusty Dagger.")
+	alice.Expect("craft arrows", "You make 10 x Arrow. (+15 crafting XP)")
+	alice.Expect("recipes", "Crafting skill: level 1 (25/50 XP)")
+	alice.Expect("inventory", "Arrow                          x10")
+}
+
+func TestContainersAndCorpses(t *testing.T) {
+	h := gametest.New(t)
+	h.SpawnNPC("rat", "2")
+	alice := h.Connect("alice")
+	h.GiveItem("alice", "small_bag", 1)
+	h.GiveItem("alice", "bone", 2)
+
+	alice.Expect("put bone in bag", "You put Bone (x2) in Small Bag.")
+	alice.Expect("inventory", "      Bone (x2)")
+	alice.Expect("get bone from bag", "You get Bone (x2) from Small Bag.")
+	alice.Expect("north", "Exits:")
+	alice.Expect("kill rat", "You have defeated a small rat!")
+	alice.Expect("get tail from corpse", "You get Rat Tail from the corpse of a small rat.")
+	alice.Expect("put bone in corpse", "You put Bone (x2) in the corpse of a small rat.")
+	alice.Expect("get all from corpse", "You get Rat Fur, Bone (x2) from the corpse of a small rat.")
+}
+
+func TestEncumbrance(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+	h.GiveItem("alice", "raw_chicken", 40)
+
+	alice.Expect("inventory", "Carrying 40.0 of 35.0 weight - overburdened!")
+	alice.Expect("north", "You are carrying too much to move.")
+	alice.Expect("drop all", "You dropped Raw Chicken (x40).")
+	alice.Expect("get chicken", "Raw Chicken (x40) is too heavy for you to carry.")
+}
+
+func TestLootRarityAndAffixes(t *testing.T) {
+	h := gametest.New(t)
+	h.SpawnNPC("skeleton", "2")
+	alice := h.Connect("alice")
+
+	alice.Expect("north", "TEST FIELD")
+	alice.Expect("kill skeleton", "You have defeated a rattling skeleton!")
+	alice.Expect("loot corpse", "You looted: Leather Helmet of the Bear")
+	alice.Expect("inventory", "Leather Helmet of the Bear [")
+	alice.Expect("examine helmet", "Max HP: +10")
+	alice.Expect("wear helmet", "Your maximum health increases by 10.")
+	alice.Expect("examine me", "/110 HP")
+	alice.Expect("remove helmet", "Your maximum health decreases by 10.")
+}
+
+func TestDurabilityAndRepair(t *testing.T) {
+	h := gametest.New(t)
+	h.SpawnNPC("rat", "2")
+	h.SpawnNPC("blacksmith", "1")
+	alice := h.Connect("alice")
+	h.GiveItem("alice", "rusty_dagger", 1)
+	h.GiveMoney("alice", 10)
+
+	alice.Expect("repair", "Your gear is in fine shape.")
+	alice.Expect("wield dagger", "You wield Rusty Dagger.")
+	alice.Expect("north", "TEST FIELD")
+	alice.Expect("kill rat", "You have defeated a small rat!")
+	alice.Expect("examine dagger", "Condition: excellent (")
+	alice.Expect("south", "TEST CROSSROADS")
+	alice.Expect("repair", "Rusty Dagger")
+	alice.Expect("repair dagger", "a burly blacksmith repairs your Rusty Dagger for 1 copper.")
+	alice.Expect("examine dagger", "Condition: excellent (40/40)")
+}
+
+func TestTradeBetweenPlayers(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+	bob := h.Connect("bob")
+	h.GiveItem("alice", "rusty_dagger", 1)
+	h.GiveMoney("bob", 50)
+
+	alice.Expect("trade bob", "You ask bob to trade.")
+	bob.WaitFor("alice wants to trade with you.")
+	bob.Expect("trade alice", "You begin trading with alice.")
+	alice.WaitFor("You begin trading with bob.")
+
+	alice.Expect("offer dagger", "You offer Rusty Dagger.")
+	bob.WaitFor("alice offers Rusty Dagger.")
+	bob.Expect("offer 6 gold", "You don't have 6 gold.")
+	bob.Expect("offer 3 silver", "You offer 3 silver.")
+	alice.WaitFor("bob offers 3 silver.")
+
+	alice.Expect("accept", "Waiting for bob to accept.")
+	bob.Expect("offer 1 copper", "You offer 1 copper.")
+	alice.WaitFor("bob offers 1 copper.")
+	bob.Expect("accept", "Waiting for alice to accept.")
+	alice.Expect("accept", "The trade is complete. You receive 3 silver, 1 copper.")
+	bob.WaitFor("The trade is complete. You receive Rusty Dagger.")
+
+	bob.Expect("inventory", "Rusty Dagger")
+	alice.Expect("balance", "You are carrying 3 silver, 1 copper.")
+	bob.Expect("balance", "You are carrying 1 silver, 9 copper.")
+}
+
+func TestTradeOfChangedBag(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+	bob := h.Connect("bob")
+	h.GiveItem("alice", "small_bag", 1)
+	h.GiveItem("alice", "bone", 1)
+	h.GiveMoney("bob", 50)
+
+	alice.Expect("trade bob", "You ask bob to trade.")
+	bob.WaitFor("alice wants to trade with you.")
+	bob.Expect("trade alice", "You begin trading with alice.")
+	alice.WaitFor("You begin trading with bob.")
+
+	alice.Expect("put bone in bag", "You put Bone in Small Bag.")
+	alice.Expect("offer bag", "Empty Small Bag before offering it.")
+	alice.Expect("get bone from bag", "You get Bone from Small Bag.")
+	alice.Expect("offer bag", "You offer Small Bag.")
+	bob.Expect("offer 3 silver", "You offer 3 silver.")
+	bob.Expect("accept", "Waiting for alice to accept.")
+
+	// Filling the bag after bob accepted means he has to look again
+	alice.Expect("put bone in bag", "You put Bone in Small Bag.")
+	alice.Expect("accept", "alice's Small Bag has changed since it was offered. Both of you must accept again.")
+	alice.WaitFor("Waiting for bob to accept.")
+	bob.WaitFor("alice's Small Bag has changed since it was offered.")
+}
+
+func TestBankVault(t *testing.T) {
+	h := gametest.New(t)
+	h.SpawnNPC("banker", "1")
+	alice := h.Connect("alice")
+	h.GiveItem("alice", "rusty_dagger", 1)
+	h.GiveMoney("alice", 50)
+
+	alice.Expect("bank", "Your vault is empty.")
+	alice.Expect("deposit dagger", "You deposit Rusty Dagger with a prim banker.")
+	alice.Expect("deposit 2 silver", "Your vault holds 2 silver.")
+	alice.Expect("inventory", "Your inventory is empty")
+	alice.Expect("bank", "(1/30 slots used)")
+	alice.Expect("withdraw dagger", "You withdraw Rusty Dagger from your vault.")
+	alice.Expect("withdraw 5 copper", "Your vault holds 1 silver, 5 copper.")
+	alice.Expect("balance", "3 silver, 5 copper")
+
+	alice.Expect("deposit dagger", "You deposit Rusty Dagger with a prim banker.")
+
+	// The vault belongs to the account, so it is still there after alice
+	// logs out, and only her password gets her back in
+	bob := h.Connect("bob")
+	alice.Send("exit")
+	bob.WaitFor("alice has left the game.")
+
+	impostor := h.Dial()
+	impostor.Expect("alice", "Welcome back, alice. What is your password?")
+	impostor.Expect("guess", "That is not the password for alice.")
+	impostor.WaitFor("By what name do you wish to be known?")
+
+	alice = h.Connect("alice")
+	alice.Expect("bank", "Rusty Dagger")
+	alice.Expect("bank", "Coins: 1 silver, 5 copper")
+	bob.Expect("name alice", "The name alice is already taken.")
+
+	// Vaults and the accounts that own them are saved, so both outlast a
+	// restart
+	h.Restart()
+	h.SpawnNPC("banker", "1")
+	impostor = h.Dial()
+	impostor.Expect("alice", "Welcome back, alice. What is your password?")
+	impostor.Expect("guess", "That is not the password for alice.")
+	alice = h.Connect("alice")
+	alice.Expect("bank", "Rusty Dagger")
+	alice.Expect("bank", "Coins: 1 silver, 5 copper")
+}
+
+func TestFullVaultTakesMoreOfAStack(t *testing.T) {
+	h := gametest.New(t)
+	h.SpawnNPC("banker", "1")
+	alice := h.Connect("alice")
+
+	h.GiveItem("alice", "rat_tail", 1)
+	alice.Expect("deposit tail", "You deposit Rat Tail with a prim banker.")
+	for _, count := range []int{15, 14} {
+		for i := 0; i < count; i++ {
+			h.GiveItem("alice", "rusty_dagger", 1)
+		}
+		alice.Expect("deposit all.dagger", "You deposit Rusty Dagger")
+	}
+	alice.Expect("bank", "(30/30 slots used)")
+
+	h.GiveItem("alice", "rusty_dagger", 1)
+	alice.Expect("deposit dagger", "a prim banker says: Your vault is full.")
+	h.GiveItem("alice", "rat_tail", 2)
+	alice.Expect("deposit tail", "You deposit Rat Tail (x2) with a prim banker.")
+	alice.Expect("bank", "x3")
+}
+
+func TestFailedResourceLoadKeepsTemplates(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+
+	// Resources that rename the dagger but then fail on a class teaching an
+	// ability that doesn't exist
+	dir := t.TempDir()
+	entries, err := os.ReadDir(gametest.FixtureDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(gametest.FixtureDir(), entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch entry.Name() {
+		case "items.json":
+			data = []byte(strings.Replace(string(data), "Rusty Dagger", "Shiny Dagger", 1))
+		case "classes.json":
+			data = []byte(strings.Replace(string(data), `"bash"`, `"juggle"`, 1))
+		}
+		if err := os.WriteFile(filepath.Join(dir, entry.Name()), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := components.LoadResources(dir); err == nil {
+		t.Fatal("loading resources with an unknown ability succeeded")
+	}
+
+	h.GiveItem("alice", "rusty_dagger", 1)
+	alice.Expect("inventory", "Rusty Dagger")
+}
+
+func TestCombatRounds(t *testing.T) {
+	h := gametest.New(t)
+	h.SpawnNPC("rat", "2")
+	alice := h.Connect("alice")
+
+	alice.Expect("north", "TEST FIELD")
+	alice.Send("kill rat")
+	alice.WaitFor("a small rat. (")
+	alice.WaitFor("You have defeated a small rat!")
+}
+
+func TestRefeedIsDeterministic(t *testing.T) {
+	session := []recording.Event{
+		{Offset: 0, Input: "alice"},
+		{Offset: 1000, Input: "********"}, // Recordings hide the password
+		{Offset: 1500, Input: "human"},
+		{Offset: 3000, Input: "adventurer"},
+		{Offset: 4000, Input: "north"},
+		{Offset: 5000, Input: "kill goblin"},
+		{Offset: 30000, Input: "loot corpse"},
+		{Offset: 31000, Input: "kill skeleton"},
+		{Offset: 50000, Input: "loot corpse"},
+		{Offset: 51000, Input: "inventory"},
+	}
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	refeed := func() string {
+		h := gametest.NewManual(t, 42, start)
+		h.SpawnNPC("goblin", "2")
+		h.SpawnNPC("skeleton", "2")
+		client := gametest.NewClient(t, h.Game, "10.0.0.1:4000")
+		if err := h.Game.Refeed(client, session, 10*time.Second, nil); err != nil {
+			t.Fatal(err)
+		}
+		return strings.Join(client.Messages(), "\n")
+	}
+
+	first, second := refeed(), refeed()
+	if !strings.Contains(first, "You have defeated a rattling skeleton!") {
+		t.Fatalf("the re-fed fight never finished:\n%s", first)
+	}
+	if first != second {
+		t.Fatalf("the same session gave different output:\n%s\n\n-- versus --\n\n%s", first, second)
+	}
+}
+
+func TestTrainAttributes(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+
+	alice.Expect("score", "Training points: 5")
+	alice.Expect("score", "Damage: 10-50")
+	alice.Expect("train str", "You train your strength to 11. (4 points left)")
+	alice.Expect("score", "Damage: 11-52")
+	alice.Expect("score", "Carrying: 0.0/38.0")
+	alice.Expect("train con", "Your maximum health increases by 5.")
+	alice.Expect("examine me", "/105 HP")
+	alice.Expect("examine me", "CON  Constitution  11")
+	alice.Expect("train luck", "Train what?")
+}
+
+func TestCharacterCreation(t *testing.T) {
+	h := gametest.New(t)
+	bob := h.Connect("bob")
+
+	addr := "10.0.9.1:4000"
+	alice := gametest.NewClient(t, h.Game, addr)
+	h.Game.AddPlayerChan <- alice
+	alice.WaitFor("By what name do you wish to be known?")
+
+	alice.Expect("x", "Names must be 3 to 16 characters long.")
+	alice.Expect("bob", "bob is already playing.")
+	alice.Expect("alice", "Choose a password for alice:")
+	alice.Expect("abc", "Passwords must be at least 4 characters long.")
+	alice.Expect("swordfish", "CHOOSE YOUR RACE")
+	alice.Expect("orc", "That isn't a race you can choose.")
+	alice.Expect("elf", "CHOOSE YOUR CLASS")
+	alice.Expect("warrior", "Welcome, alice the Elf Warrior!")
+	alice.WaitFor("TEST FIELD")
+	bob.WaitFor("alice has joined the game.")
+
+	alice.Expect("equipment", "Rusty Dagger")
+	alice.Expect("examine me", "CON  Constitution   8")
+	alice.Expect("examine me", "/90 HP")
+	alice.Expect("score", "Abilities: bash")
+	alice.Expect("who", "Warrior")
+	bob.Expect("who", "Adventurer")
+}
+
+func TestRename(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+	bob := h.Connect("bob")
+
+	bob.Expect("name x", "Names must be 3 to 16 characters long.")
+	bob.Expect("name b0b!", "Names may only contain letters and numbers.")
+	bob.Expect("name ALICE", "The name ALICE is already taken.")
+	bob.Expect("name Bob", "bob has changed their name to Bob")
+	bob.Expect("name robert", "Bob has changed their name to robert")
+	alice.WaitFor("Bob has changed their name to robert")
+	alice.Expect("name Robert", "The name Robert is already taken.")
+}
+
+func TestAbilities(t *testing.T) {
+	h := gametest.New(t)
+	h.SpawnNPC("rat", "2")
+	alice := h.ConnectAs("alice", "human", "warrior")
+
+	alice.Expect("abilities", "Still to learn: mend (level 2)")
+	alice.Expect("mend", "You don't know how to mend.")
+	alice.Expect("bash", "Bash whom?")
+	alice.Expect("north", "TEST FIELD")
+	alice.Expect("bash rat", "Your bash hits a small rat. (100 damage)")
+	alice.WaitFor("You have defeated a small rat!")
+	alice.Expect("bash rat", "You can't use Bash again for 30s.")
+	alice.Expect("score", "Abilities: bash")
+	alice.Expect("help bash", "Slam into a foe, stunning them. Usage: bash [target]")
+}
+
+func TestRestAndResources(t *testing.T) {
+	h := gametest.New(t)
+	h.SpawnNPC("rat", "2")
+	alice := h.ConnectAs("alice", "human", "warrior")
+
+	alice.Expect("score", "Mana: 50/50   Stamina: 50/50")
+	alice.Expect("rest", "You sit down and rest.")
+	alice.Expect("rest", "You are already resting.")
+	alice.Expect("north", "You need to stand up first.")
+	alice.Expect("sleep", "You lie down and go to sleep.")
+	alice.Expect("wake", "You stand up.")
+	alice.Expect("stand", "You are already standing.")
+
+	alice.Expect("north", "TEST FIELD")
+	alice.Expect("bash rat", "Your bash hits a small rat.")
+	alice.Expect("score", "Stamina: 4")
+}
+
+func TestFleeAndWimpy(t *testing.T) {
+	h := gametest.New(t)
+	h.SpawnNPC("goblin", "2")
+	alice := h.Connect("alice")
+
+	alice.Expect("flee", "You aren't fighting anyone.")
+	alice.Expect("wimpy", "Wimpy is off.")
+	alice.Expect("wimpy 80", "Wimpy can't be set above half your maximum health (50).")
+	alice.Expect("wimpy 30", "You will flee when your health drops below 30.")
+	alice.Expect("wimpy 0", "Wimpy is off. You will fight to the death.")
+
+	alice.Expect("north", "TEST FIELD")
+	alice.Send("kill goblin")
+	alice.Expect("recall", "You can't focus enough to recall while fighting!")
+	fled := false
+	for i := 0; i < 20 && !fled; i++ {
+		fled = strings.Contains(alice.Expect("flee", "flee"), "You flee south!")
+	}
+	if !fled {
+		t.Fatal("alice never got away")
+	}
+	alice.WaitFor("TEST CROSSROADS")
+}
+
+func TestDeathAndRespawn(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+	bob := h.Connect("bob")
+	h.GiveItem("alice", "rusty_dagger", 1)
+
+	alice.Expect("bind", "You are already bound to this shrine.")
+	alice.Expect("north", "TEST FIELD")
+	alice.Expect("bind", "There is no shrine here")
+
+	// The troll is far too tough for alice to kill first, so it always
+	// lands the killing blow
+	h.SpawnNPC("troll", "2")
+	h.SetHealth("alice", 1)
+	alice.Send("kill troll")
+	alice.WaitFor("You have died!")
+	alice.WaitFor("You rise from your body as a ghost.")
+	alice.Expect("south", "You are a ghost and can't do that.")
+
+	bob.Expect("north", "the corpse of alice")
+	bob.Expect("loot alice", "You need alice's consent to loot their corpse.")
+	h.GiveItem("bob", "bone", 1)
+	bob.Expect("put bone in alice", "You need alice's consent to loot their corpse.")
+
+	alice.Expect("release", "You return to life, weakened by your death.")
+	alice.WaitFor("TEST CROSSROADS")
+	alice.Expect("inventory", "empty")
+
+	// The corpse still belongs to alice after she logs out and back in
+	alice.Send("exit")
+	bob.WaitFor("alice has left the game.")
+	bob.Expect("loot alice", "You need alice's consent to loot their corpse.")
+	alice = h.Connect("alice")
+	alice.Expect("consent BOB", "bob may now loot your corpse.")
+	alice.Expect("consent", "You allow these accounts to loot your corpse: bob")
+
+	bob.Expect("loot alice", "You looted: Rusty Dagger")
+
+	// Consent follows bob's account when he goes by another name
+	bob.Expect("name robert", "bob has changed their name to robert")
+	alice.Expect("consent revoke robert", "robert may no longer loot your corpse.")
+	alice.Expect("consent", "Nobody else may loot your corpse.")
+}
+
+func TestThreatAndTaunt(t *testing.T) {
+	h := gametest.New(t)
+	h.SpawnNPC("troll", "2")
+	alice := h.Connect("alice")
+	bob := h.ConnectAs("bob", "human", "warrior")
+
+	alice.Expect("north", "TEST FIELD")
+	bob.Expect("north", "TEST FIELD")
+
+	alice.Send("kill troll")
+	alice.WaitFor("a hulking troll")
+
+	bob.Expect("taunt troll", "You taunt a hulking troll into attacking you!")
+	bob.WaitFor("a hulking troll turns to attack you!")
+	alice.WaitFor("a hulking troll turns to attack bob!")
+}
+
+func TestPvPRules(t *testing.T) {
+	h := gametest.New(t)
+	alice := h.Connect("alice")
+	bob := h.Connect("bob")
+
+	alice.Expect("kill bob", "This is a safe place.")
+	alice.Expect("duel bob", "This is a safe place.")
+
+	alice.Expect("north", "TEST FIELD")
+	alice.Expect("kill bob", "They aren't here.")
+	bob.Expect("north", "TEST FIELD")
+
+	alice.Expect("kill bob", "You haven't turned PvP on.")
+	alice.Expect("pvp on", "PvP is now on.")
+	alice.Expect("pvp off", "You can't change your PvP flag again for")
+	alice.Expect("kill bob", "bob hasn't turned PvP on.")
+
+	h.SetHealth("alice", 5)
+	h.SetHealth("bob", 5)
+	alice.Expect("duel bob", "You challenge bob to a duel.")
+	bob.WaitFor("alice challenges you to a duel.")
+	bob.Expect("duel alice", "You accept alice's challenge. The duel begins!")
+	alice.WaitFor("won the duel")
+	bob.WaitFor("won the duel")
+
+	// Nobody died, and the duel no longer lets them fight
+	bob.Expect("kill alice", "You haven't turned PvP on.")
+
+	// Leaving the area, by any means, forfeits a duel
+	h.SetHealth("alice", 100)
+	h.SetHealth("bob", 100)
+	alice.Expect("duel bob", "You challenge bob to a duel.")
+	bob.Expect("duel alice", "The duel begins!")
+	bob.Send("south")
+	bob.WaitFor("You forfeit the duel.")
+	alice.WaitFor("bob abandons the duel. You win!")
+}
+
+func TestDamageTypesAndResistances(t *testing.T) {
+	h := gametest.New(t)
+	h.SpawnNPC("skeleton", "2")
+	alice := h.ConnectAs("alice", "human", "warrior")
+
+	alice.Expect("examine dagger", "Damage type: pierce")
+	alice.Expect("north", "TEST FIELD")
+	alice.Send("kill skeleton")
+	alice.WaitFor("pierce resisted x0.5)")
+	alice.WaitFor("You have defeated a rattling skeleton!")
+
+	h.SpawnNPC("skeleton", "2")
+	alice.Expect("bash skeleton", "Your bash hits a rattling skeleton. (150 damage, bludgeon weakness x1.5)")
+}
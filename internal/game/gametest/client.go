@@ -0,0 +1,142 @@
+package gametest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dmud/internal/common"
+	"dmud/internal/game"
+)
+
+// DefaultTimeout is how long WaitFor blocks before failing the test.
+var DefaultTimeout = 3 * time.Second
+
+// Client is a headless common.Client that records every message the game
+// sends it and lets a test script commands into the game loop.
+type Client struct {
+	t    testing.TB
+	game *game.Game
+	addr string
+
+	mu       sync.Mutex
+	messages []string
+	cursor   int
+	closed   bool
+	notify   chan struct{}
+}
+
+var _ common.Client = (*Client)(nil)
+
+func NewClient(t testing.TB, g *game.Game, addr string) *Client {
+	return &Client{
+		t:      t,
+		game:   g,
+		addr:   addr,
+		notify: make(chan struct{}, 1),
+	}
+}
+
+func (c *Client) CloseConnection() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	return nil
+}
+
+// HandleRequest is a no-op; commands are injected with Send instead of read
+// from a connection.
+func (c *Client) HandleRequest() {}
+
+func (c *Client) SendMessage(msg string) {
+	c.mu.Lock()
+	c.messages = append(c.messages, msg)
+	c.mu.Unlock()
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Client) RemoteAddr() string { return c.addr }
+
+func (c *Client) SupportsPrompt() bool { return false }
+
+// Send parses line the same way the network clients do and queues it on the
+// game's command channel.
+func (c *Client) Send(line string) {
+	parts := strings.SplitN(strings.TrimSpace(line), " ", 2)
+	var args []string
+	if len(parts) > 1 {
+		args = strings.Split(parts[1], " ")
+	}
+
+	c.game.ExecuteCommandChan <- game.ClientCommand{
+		Client: c,
+		Cmd:    parts[0],
+		Args:   args,
+	}
+}
+
+// Messages returns a copy of everything the client has received so far.
+func (c *Client) Messages() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	messages := make([]string, len(c.messages))
+	copy(messages, c.messages)
+	return messages
+}
+
+// Closed reports whether the game closed this client's connection.
+func (c *Client) Closed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// WaitFor blocks until a message containing substr arrives and returns it.
+// Messages are consumed in order, so a later WaitFor never matches output
+// that was seen before an earlier match.
+func (c *Client) WaitFor(substr string) string {
+	c.t.Helper()
+
+	msg, ok := c.waitFor(substr, DefaultTimeout)
+	if !ok {
+		c.t.Fatalf("timed out waiting for %q; received:\n%s", substr, strings.Join(c.Messages(), "\n"))
+	}
+	return msg
+}
+
+// Expect sends line and waits for a reply containing substr.
+func (c *Client) Expect(line, substr string) string {
+	c.t.Helper()
+
+	c.Send(line)
+	return c.WaitFor(substr)
+}
+
+func (c *Client) waitFor(substr string, timeout time.Duration) (string, bool) {
+	deadline := time.After(timeout)
+
+	for {
+		c.mu.Lock()
+		for i := c.cursor; i < len(c.messages); i++ {
+			if strings.Contains(c.messages[i], substr) {
+				c.cursor = i + 1
+				msg := c.messages[i]
+				c.mu.Unlock()
+				return msg, true
+			}
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-c.notify:
+		case <-deadline:
+			return "", false
+		}
+	}
+}
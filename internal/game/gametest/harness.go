@@ -0,0 +1,203 @@
+// Package gametest boots a full game against fixture resources and drives it
+// with headless clients, so end-to-end scenarios can be written as ordinary
+// Go tests.
+package gametest
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"dmud/internal/game"
+	"dmud/internal/systems"
+
+	"github.com/rs/zerolog"
+)
+
+var clientCounter int64
+
+// Password is the password Connect and ConnectAs register and log players in
+// with.
+const Password = "hunter2"
+
+// Harness owns a running game for the lifetime of a single test.
+type Harness struct {
+	t      testing.TB
+	config *game.Config
+	Game   *game.Game
+}
+
+// FixtureDir returns the directory holding the bundled fixture resources.
+func FixtureDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata")
+}
+
+// New boots a game against the bundled fixture resources.
+func New(t testing.TB) *Harness {
+	return NewWithResources(t, FixtureDir())
+}
+
+// NewWithResources boots a game against the resource files in dir. The game
+// loop is stopped when the test finishes, and anything it saves goes to a
+// directory of the test's own.
+func NewWithResources(t testing.TB, dir string) *Harness {
+	t.Helper()
+
+	if !testing.Verbose() {
+		zerolog.SetGlobalLevel(zerolog.Disabled)
+	}
+
+	if err := components.LoadResources(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Short rounds keep fights quick enough to test
+	config := &game.Config{ResourceDir: dir, SaveDir: t.TempDir(), CombatRound: 100 * time.Millisecond}
+	g := game.NewGameWithConfig(config)
+	t.Cleanup(g.Stop)
+
+	return &Harness{t: t, config: config, Game: g}
+}
+
+// Restart stops the game and boots a new one from the same resources and
+// save directory, as restarting the server would. Everyone must connect
+// again; only what the game saves, such as accounts and bank vaults, is
+// still there.
+func (h *Harness) Restart() {
+	h.t.Helper()
+
+	h.Game.Stop()
+	h.Game = game.NewGameWithConfig(h.config)
+	h.t.Cleanup(h.Game.Stop)
+}
+
+// NewManual boots a game against the bundled fixture resources that rolls
+// from seed and runs on a manual clock starting at start. It has no game
+// loop; drive it with the game's Advance and Refeed.
+func NewManual(t testing.TB, seed int64, start time.Time) *Harness {
+	t.Helper()
+
+	if !testing.Verbose() {
+		zerolog.SetGlobalLevel(zerolog.Disabled)
+	}
+
+	dir := FixtureDir()
+	if err := components.LoadResources(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &game.Config{ResourceDir: dir, SaveDir: t.TempDir(), Seed: seed, Clock: ecs.NewManualClock(start)}
+	return &Harness{t: t, config: config, Game: game.NewGameWithConfig(config)}
+}
+
+// Connect joins a new player to the game as a human adventurer called name.
+func (h *Harness) Connect(name string) *Client {
+	h.t.Helper()
+	return h.ConnectAs(name, "human", "adventurer")
+}
+
+// ConnectAs joins a new player and takes them through character creation
+// with the given name, race and class. A name that is already registered
+// is logged into with Password.
+func (h *Harness) ConnectAs(name, race, class string) *Client {
+	h.t.Helper()
+
+	client := h.Dial()
+	client.Expect(name, "password")
+	client.Expect(Password, "CHOOSE YOUR RACE")
+	client.Expect(race, "CHOOSE YOUR CLASS")
+	client.Expect(class, "Exits:")
+	return client
+}
+
+// Dial connects a new client and waits until they are asked for a name,
+// for tests that take them through character creation themselves.
+func (h *Harness) Dial() *Client {
+	h.t.Helper()
+
+	addr := fmt.Sprintf("10.0.0.%d:4000", atomic.AddInt64(&clientCounter, 1))
+	client := NewClient(h.t, h.Game, addr)
+	h.Game.AddPlayerChan <- client
+	client.WaitFor("By what name do you wish to be known?")
+	return client
+}
+
+// SpawnNPC places a new NPC built from templateID in areaID.
+func (h *Harness) SpawnNPC(templateID, areaID string) common.EntityID {
+	h.t.Helper()
+
+	template, ok := components.NPCTemplates[templateID]
+	if !ok {
+		h.t.Fatalf("unknown NPC template %q", templateID)
+	}
+
+	area, err := ecs.GetTypedComponent[*components.Area](h.Game.World(), common.EntityID(areaID), "Area")
+	if err != nil {
+		h.t.Fatalf("unknown area %q: %v", areaID, err)
+	}
+
+	return systems.CreateNPC(h.Game.World(), area, template).ID
+}
+
+// PlayerEntity returns the entity ID of the connected player called name.
+func (h *Harness) PlayerEntity(name string) common.EntityID {
+	h.t.Helper()
+
+	entities, _ := h.Game.World().FindEntitiesByComponentPredicate("Player", func(i interface{}) bool {
+		p, ok := i.(*components.Player)
+		return ok && p.Name == name
+	})
+	if len(entities) == 0 {
+		h.t.Fatalf("no player named %q", name)
+	}
+	return entities[0].ID
+}
+
+// GiveItem puts quantity of itemID into the named player's inventory.
+func (h *Harness) GiveItem(name, itemID string, quantity int) {
+	h.t.Helper()
+
+	item := components.CreateItem(itemID, quantity)
+	if item == nil {
+		h.t.Fatalf("unknown item template %q", itemID)
+	}
+
+	inventory, err := ecs.GetTypedComponent[*components.Inventory](h.Game.World(), h.PlayerEntity(name), "Inventory")
+	if err != nil {
+		h.t.Fatalf("player %q has no inventory: %v", name, err)
+	}
+	if !inventory.AddItem(item) {
+		h.t.Fatalf("player %q has no room for %s", name, itemID)
+	}
+}
+
+// SetHealth sets the named player's current health.
+func (h *Harness) SetHealth(name string, hp int) {
+	h.t.Helper()
+
+	health, err := ecs.GetTypedComponent[*components.Health](h.Game.World(), h.PlayerEntity(name), "Health")
+	if err != nil {
+		h.t.Fatalf("player %q has no health: %v", name, err)
+	}
+	health.Lock()
+	health.Current = hp
+	health.Unlock()
+}
+
+// GiveMoney puts copper into the named player's wallet.
+func (h *Harness) GiveMoney(name string, copper int) {
+	h.t.Helper()
+
+	wallet, err := ecs.GetTypedComponent[*components.Wallet](h.Game.World(), h.PlayerEntity(name), "Wallet")
+	if err != nil {
+		h.t.Fatalf("player %q has no wallet: %v", name, err)
+	}
+	wallet.Deposit(copper, "test harness", h.Game.World().Now())
+}
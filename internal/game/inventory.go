@@ -3,6 +3,7 @@ package game
 import (
 	"dmud/internal/common"
 	"dmud/internal/components"
+	"dmud/internal/ecs"
 	"fmt"
 	"strings"
 
@@ -63,6 +64,10 @@ func (g *Game) handleLoot(player *components.Player, args []string, game *Game)
 		return
 	}
 
+	if !g.canLoot(player, targetCorpse) {
+		return
+	}
+
 	playerInvComp, err := g.world.GetComponent(playerEntity, "Inventory")
 	if err != nil {
 		player.Broadcast("You don't have an inventory!")
@@ -84,27 +89,47 @@ func (g *Game) handleLoot(player *components.Player, args []string, game *Game)
 		return
 	}
 
+	wallet := g.playerWallet(playerEntity)
+
 	lootedItems := make([]string, 0)
+	remaining := make([]*components.Item, 0)
+	stopped := false
 	for _, item := range targetCorpse.Inventory.Items {
+		if stopped {
+			remaining = append(remaining, item)
+			continue
+		}
+
+		if name, ok := g.collectCoins(wallet, item, "looted "+targetCorpse.GetDescription()); ok {
+			lootedItems = append(lootedItems, name)
+			continue
+		}
+
 		if playerInventory.IsFull() {
 			player.Broadcast("Your inventory is full!")
-			break
+			stopped = true
+		} else if !g.canCarry(player, playerEntity, item) {
+			stopped = true
 		}
 
-		if playerInventory.AddItem(item.Clone()) {
-			lootedItems = append(lootedItems, item.Name)
+		if stopped || !playerInventory.AddItem(item.Clone()) {
+			remaining = append(remaining, item)
+			continue
 		}
+		lootedItems = append(lootedItems, item.Name)
 	}
 
-	// Clear corpse inventory
-	targetCorpse.Inventory.Items = make([]*components.Item, 0)
+	// Whatever the player couldn't carry stays on the corpse
+	targetCorpse.Inventory.Items = remaining
 
 	if len(lootedItems) > 0 {
 		player.Broadcast(fmt.Sprintf("You looted: %s", strings.Join(lootedItems, ", ")))
 		player.Area.Broadcast(fmt.Sprintf("%s loots %s.", player.Name, targetCorpse.GetDescription()), player)
 
 		// Mark corpse as looted so it decays in 5 seconds
-		targetCorpse.MarkAsLooted()
+		if len(remaining) == 0 {
+			targetCorpse.MarkAsLooted(g.world.Now())
+		}
 	} else {
 		player.Broadcast("You couldn't loot anything.")
 	}
@@ -136,8 +161,11 @@ func (g *Game) handleLootAll(player *components.Player, game *Game) {
 	}
 	playerInventory := playerInvComp.(*components.Inventory)
 
+	wallet := g.playerWallet(playerEntity)
+
 	totalLootedItems := make([]string, 0)
 	corpsesLooted := 0
+	stopped := false
 
 	for _, corpseEntity := range corpses {
 		corpseComp, err := g.world.GetComponent(corpseEntity.ID, "Corpse")
@@ -147,8 +175,9 @@ func (g *Game) handleLootAll(player *components.Player, game *Game) {
 
 		corpse := corpseComp.(*components.Corpse)
 
-		// Skip corpses with no inventory or empty inventory
-		if corpse.Inventory == nil {
+		// Skip corpses with no inventory or empty inventory, and other
+		// players' corpses without their consent
+		if corpse.Inventory == nil || !g.mayLoot(player, corpse) {
 			continue
 		}
 
@@ -161,31 +190,51 @@ func (g *Game) handleLootAll(player *components.Player, game *Game) {
 
 		// Loot items from this corpse
 		lootedFromCorpse := false
+		remaining := make([]*components.Item, 0)
 		for _, item := range corpse.Inventory.Items {
+			if stopped {
+				remaining = append(remaining, item)
+				continue
+			}
+
+			if name, ok := g.collectCoins(wallet, item, "looted "+corpse.GetDescription()); ok {
+				totalLootedItems = append(totalLootedItems, name)
+				lootedFromCorpse = true
+				continue
+			}
+
 			if playerInventory.IsFull() {
-				corpse.Inventory.Unlock()
 				player.Broadcast("Your inventory is full!")
-				goto done
+				stopped = true
+			} else if !g.canCarry(player, playerEntity, item) {
+				stopped = true
 			}
 
-			if playerInventory.AddItem(item.Clone()) {
-				totalLootedItems = append(totalLootedItems, item.Name)
-				lootedFromCorpse = true
+			if stopped || !playerInventory.AddItem(item.Clone()) {
+				remaining = append(remaining, item)
+				continue
 			}
+			totalLootedItems = append(totalLootedItems, item.Name)
+			lootedFromCorpse = true
 		}
 
-		// Clear this corpse's inventory
-		corpse.Inventory.Items = make([]*components.Item, 0)
+		// Whatever the player couldn't carry stays on the corpse
+		corpse.Inventory.Items = remaining
 		corpse.Inventory.Unlock()
 
 		if lootedFromCorpse {
 			corpsesLooted++
 			// Mark corpse as looted so it decays in 5 seconds
-			corpse.MarkAsLooted()
+			if len(remaining) == 0 {
+				corpse.MarkAsLooted(g.world.Now())
+			}
+		}
+
+		if stopped {
+			break
 		}
 	}
 
-done:
 	if len(totalLootedItems) > 0 {
 		player.Broadcast(fmt.Sprintf("You looted %d corpse(s) and found: %s", corpsesLooted, strings.Join(totalLootedItems, ", ")))
 		player.Area.Broadcast(fmt.Sprintf("%s loots all the corpses.", player.Name), player)
@@ -223,8 +272,16 @@ func (g *Game) handleInventory(player *components.Player, args []string, game *G
 	for _, item := range items {
 		if item.Stackable && item.Quantity > 1 {
 			output.WriteString(fmt.Sprintf("  %-30s x%d\n", item.Name, item.Quantity))
+		} else if item.Container != nil {
+			contents := item.Container.GetItems()
+			output.WriteString(fmt.Sprintf("  %-30s (%d/%d)\n", item.Name, len(contents), item.Container.MaxSlots))
+			for _, content := range contents {
+				output.WriteString(fmt.Sprintf("      %s\n", content.DisplayName()))
+			}
+		} else if item.IsBroken() {
+			output.WriteString(fmt.Sprintf("  %s (broken)\n", item.ColoredName()))
 		} else {
-			output.WriteString(fmt.Sprintf("  %s\n", item.Name))
+			output.WriteString(fmt.Sprintf("  %s\n", item.ColoredName()))
 		}
 	}
 
@@ -234,23 +291,103 @@ func (g *Game) handleInventory(player *components.Player, args []string, game *G
 	}
 	inventory.RUnlock()
 
+	carried, capacity := components.CarryingWeight(g.world.AsWorldLike(), playerEntity)
+	output.WriteString(fmt.Sprintf("Carrying %.1f of %.1f weight", carried, capacity))
+	if carried > capacity {
+		output.WriteString(" - overburdened!")
+	}
+	output.WriteString("\n")
+
 	output.WriteString("==============================================\n")
 
 	player.Broadcast(output.String())
 }
 
 func (g *Game) handleGet(player *components.Player, args []string, game *Game) {
-	player.Broadcast("Item pickup from ground not yet implemented. Use 'loot' for corpses.")
+	if len(args) == 0 {
+		player.Broadcast("Get what? Usage: get <item>, get all or get all.<item>")
+		return
+	}
+
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	invComp, err := g.world.GetComponent(playerEntity, "Inventory")
+	if err != nil {
+		player.Broadcast("You don't have an inventory!")
+		return
+	}
+	inventory := invComp.(*components.Inventory)
+
+	arg := strings.ToLower(strings.Join(args, " "))
+	if itemArg, containerArg, found := strings.Cut(arg, " from "); found {
+		g.getFromContainer(player, playerEntity, inventory, itemArg, strings.TrimSpace(containerArg))
+		return
+	}
+
+	all, itemName := parseItemSelector(arg)
+	targets := matchItems(player.Area.GetGroundItems(), itemName, all)
+	if len(targets) == 0 {
+		if all && itemName == "" {
+			player.Broadcast("There is nothing here to pick up.")
+		} else {
+			player.Broadcast("You don't see that here.")
+		}
+		return
+	}
+
+	wallet := g.playerWallet(playerEntity)
+
+	pickedUp := make([]string, 0, len(targets))
+	for _, item := range targets {
+		if item.Type == components.ItemTypeCurrency && wallet != nil {
+			if player.Area.RemoveGroundItem(item) {
+				name, _ := g.collectCoins(wallet, item, "picked up from the ground")
+				pickedUp = append(pickedUp, name)
+			}
+			continue
+		}
+
+		if inventory.IsFull() {
+			player.Broadcast("Your inventory is full!")
+			break
+		}
+		if !g.canCarry(player, playerEntity, item) {
+			break
+		}
+
+		if !player.Area.RemoveGroundItem(item) {
+			continue
+		}
+
+		if !inventory.AddItem(item) {
+			// Stackables can be refused by a full inventory; put it back
+			player.Area.AddGroundItem(item, g.world.Now())
+			player.Broadcast("Your inventory is full!")
+			break
+		}
+
+		pickedUp = append(pickedUp, item.DisplayName())
+	}
+
+	if len(pickedUp) == 0 {
+		return
+	}
+
+	list := strings.Join(pickedUp, ", ")
+	player.Broadcast(fmt.Sprintf("You pick up %s.", list))
+	player.Area.Broadcast(fmt.Sprintf("%s picks up %s.", player.Name, list), player)
 }
 
 func (g *Game) handleDrop(player *components.Player, args []string, game *Game) {
 	if len(args) == 0 {
-		player.Broadcast("Drop what? Usage: drop <item>")
+		player.Broadcast("Drop what? Usage: drop <item>, drop all or drop all.<item>")
 		return
 	}
 
-	itemName := strings.ToLower(strings.Join(args, " "))
-
 	playerEntity, err := g.getPlayerEntity(player)
 	if err != nil {
 		log.Error().Err(err).Msg("Error getting player entity")
@@ -264,36 +401,95 @@ func (g *Game) handleDrop(player *components.Player, args []string, game *Game)
 	}
 
 	inventory := invComp.(*components.Inventory)
-	items := inventory.GetItems()
 
-	// Find matching item
-	var targetItem *components.Item
-	for _, item := range items {
-		if strings.Contains(strings.ToLower(item.Name), itemName) {
-			targetItem = item
-			break
+	all, itemName := parseItemSelector(strings.Join(args, " "))
+	targets := matchItems(inventory.GetItems(), itemName, all)
+	if len(targets) == 0 {
+		if all && itemName == "" {
+			player.Broadcast("You aren't carrying anything.")
+		} else {
+			player.Broadcast("You don't have that item.")
+		}
+		return
+	}
+
+	dropped := make([]string, 0, len(targets))
+	for _, targetItem := range targets {
+		// A single drop takes one from a stack, drop all takes the whole stack
+		quantity := targetItem.Quantity
+		if targetItem.Stackable && !all {
+			quantity = 1
 		}
+
+		removed := inventory.RemoveItem(targetItem.Key(), quantity)
+		if removed == nil {
+			continue
+		}
+
+		player.Area.AddGroundItem(removed, g.world.Now())
+		dropped = append(dropped, removed.DisplayName())
 	}
 
-	if targetItem == nil {
-		player.Broadcast("You don't have that item.")
+	if len(dropped) == 0 {
+		player.Broadcast("Failed to drop item.")
 		return
 	}
 
-	// Remove item from inventory (quantity 1 if stackable)
-	quantity := 1
-	if !targetItem.Stackable {
-		quantity = targetItem.Quantity
+	list := strings.Join(dropped, ", ")
+	player.Broadcast(fmt.Sprintf("You dropped %s.", list))
+	player.Area.Broadcast(fmt.Sprintf("%s dropped %s.", player.Name, list), player)
+}
+
+// parseItemSelector splits "all", "all.<name>" and "<name>" item arguments.
+func parseItemSelector(arg string) (all bool, name string) {
+	arg = strings.ToLower(strings.TrimSpace(arg))
+	if arg == "all" {
+		return true, ""
+	}
+	if strings.HasPrefix(arg, "all.") {
+		return true, strings.TrimPrefix(arg, "all.")
 	}
+	return false, arg
+}
 
-	removed := inventory.RemoveItem(targetItem.ID, quantity)
-	if removed != nil {
-		player.Broadcast(fmt.Sprintf("You dropped %s.", removed.Name))
-		player.Area.Broadcast(fmt.Sprintf("%s dropped %s.", player.Name, removed.Name), player)
-		// TODO: Add item to ground/area when we have ground items system
-	} else {
-		player.Broadcast("Failed to drop item.")
+// matchItems returns the items whose names contain name: every match when
+// all is set, otherwise just the first one.
+func matchItems(items []*components.Item, name string, all bool) []*components.Item {
+	if !all {
+		if item := findItemByName(items, name); item != nil {
+			return []*components.Item{item}
+		}
+		return nil
 	}
+
+	var matches []*components.Item
+	for _, item := range items {
+		if strings.Contains(strings.ToLower(item.Name), name) {
+			matches = append(matches, item)
+		}
+	}
+	return matches
+}
+
+// playerWallet returns the player's wallet, or nil if they don't have one.
+func (g *Game) playerWallet(entityID common.EntityID) *components.Wallet {
+	wallet, err := ecs.GetTypedComponent[*components.Wallet](g.world, entityID, "Wallet")
+	if err != nil {
+		return nil
+	}
+	return wallet
+}
+
+// collectCoins moves currency items into the wallet, returning how much was
+// collected for display.
+func (g *Game) collectCoins(wallet *components.Wallet, item *components.Item, source string) (string, bool) {
+	if wallet == nil || item.Type != components.ItemTypeCurrency {
+		return "", false
+	}
+
+	amount := item.Value * item.Quantity
+	wallet.Collect(item, source, g.world.Now())
+	return components.FormatCurrency(amount), true
 }
 
 func (g *Game) getPlayerEntity(player *components.Player) (common.EntityID, error) {
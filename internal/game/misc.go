@@ -2,6 +2,7 @@ package game
 
 import (
 	"dmud/internal/components"
+	"dmud/internal/ecs"
 	"fmt"
 	"strings"
 
@@ -19,7 +20,7 @@ func handleWho(player *components.Player, args []string, game *Game) {
 
 	tw := table.NewWriter()
 	tw.SetStyle(table.StyleLight)
-	tw.AppendHeader(table.Row{"Player", "Race", "Level", "Online Since"})
+	tw.AppendHeader(table.Row{"Player", "Race", "Class", "Level", "Online Since"})
 
 	for _, playerEntity := range game.players {
 		playerComponent, err := game.world.GetComponent(playerEntity.ID, "Player")
@@ -33,6 +34,12 @@ func handleWho(player *components.Player, args []string, game *Game) {
 			continue
 		}
 
+		// Players still creating their character aren't in the world yet
+		character, err := ecs.GetTypedComponent[*components.Character](game.world, playerEntity.ID, "Character")
+		if err != nil {
+			continue
+		}
+
 		// Get player level
 		level := 1
 		expComponent, err := game.world.GetComponent(playerEntity.ID, "Experience")
@@ -42,7 +49,7 @@ func handleWho(player *components.Player, args []string, game *Game) {
 			}
 		}
 
-		tw.AppendRow(table.Row{playerData.Name, "??", level, playerEntity.CreatedAt.DiffForHumans()})
+		tw.AppendRow(table.Row{playerData.Name, character.Race.Name, character.Class.Name, level, playerEntity.CreatedAt.DiffForHumans()})
 	}
 
 	player.Broadcast(tw.Render())
@@ -85,6 +92,12 @@ func handleRecall(player *components.Player, args []string, game *Game) {
 		return
 	}
 
+	// Recall is no escape from a fight; fleeing is
+	if playerEntity != nil && game.inCombat(playerEntity.ID) {
+		player.Broadcast("You can't focus enough to recall while fighting! Try to flee instead.")
+		return
+	}
+
 	player.Area.RemovePlayer(player)
 	player.Area = game.defaultArea
 	game.defaultArea.AddPlayer(player)
@@ -101,16 +114,41 @@ func (g *Game) HandleRename(player *components.Player, newName string) {
 		player.Broadcast("Usage: name <new_name>")
 		return
 	}
+	if problem := validateName(newName); problem != "" {
+		player.Broadcast(problem)
+		return
+	}
+
+	// Registered names belong to their accounts, even while they are away
+	if g.accounts.Exists(newName) && components.AccountKey(newName) != components.AccountKey(player.Account) {
+		player.Broadcast(fmt.Sprintf("The name %s is already taken.", newName))
+		return
+	}
+
+	oldName, ok := g.claimName(player, newName)
+	if !ok {
+		player.Broadcast(fmt.Sprintf("The name %s is already taken.", newName))
+		return
+	}
+
+	g.Broadcast(fmt.Sprintf("%s has changed their name to %s", oldName, newName))
+}
 
+// claimName gives the player newName if nobody else has it, returning the
+// name they had before.
+func (g *Game) claimName(player *components.Player, newName string) (string, bool) {
 	player.Lock()
 	oldName := player.Name
 	player.Unlock()
 
+	// Names differing only in case would be impossible to tell apart, though
+	// players may change the case of their own
 	g.playersMu.Lock()
-	if _, exists := g.players[newName]; exists {
-		g.playersMu.Unlock()
-		player.Broadcast(fmt.Sprintf("The name %s is already taken.", newName))
-		return
+	for name := range g.players {
+		if name != oldName && strings.EqualFold(name, newName) {
+			g.playersMu.Unlock()
+			return oldName, false
+		}
 	}
 	ent := g.players[oldName]
 	delete(g.players, oldName)
@@ -121,7 +159,7 @@ func (g *Game) HandleRename(player *components.Player, newName string) {
 	player.Name = newName
 	player.Unlock()
 
-	g.Broadcast(fmt.Sprintf("%s has changed their name to %s", oldName, newName))
+	return oldName, true
 }
 
 func handleTime(player *components.Player, args []string, game *Game) {
@@ -160,6 +198,9 @@ func handleExamine(player *components.Player, args []string, game *Game) {
 		if playerEntity != nil {
 			var msg strings.Builder
 			msg.WriteString("You examine yourself.\n")
+			if character, err := ecs.GetTypedComponent[*components.Character](game.world, playerEntity.ID, "Character"); err == nil {
+				msg.WriteString(fmt.Sprintf("You are %s.\n", withArticle(character.Describe())))
+			}
 
 			health, err := game.world.GetComponent(playerEntity.ID, "Health")
 			if err == nil {
@@ -174,6 +215,11 @@ func handleExamine(player *components.Player, args []string, game *Game) {
 				msg.WriteString(fmt.Sprintf("Health: %d/%d HP\n", h.Current, effectiveMax))
 			}
 
+			if attributes, err := ecs.GetTypedComponent[*components.Attributes](game.world, playerEntity.ID, "Attributes"); err == nil {
+				msg.WriteString("\nAttributes:\n")
+				msg.WriteString(describeAttributes(attributes))
+			}
+
 			statusEffects, err := game.world.GetComponent(playerEntity.ID, "StatusEffects")
 			if err == nil && statusEffects != nil {
 				se := statusEffects.(*components.StatusEffects)
@@ -237,9 +283,96 @@ func handleExamine(player *components.Player, args []string, game *Game) {
 	// Check for players
 	targetPlayer := player.Area.GetPlayer(target)
 	if targetPlayer != nil {
-		player.Broadcast("You see " + targetPlayer.Name + ", a fellow adventurer.")
+		description := "a fellow adventurer"
+		if targetEntity, err := game.getPlayerEntity(targetPlayer); err == nil {
+			if character, err := ecs.GetTypedComponent[*components.Character](game.world, targetEntity, "Character"); err == nil {
+				description = withArticle(character.Describe())
+			}
+		}
+		player.Broadcast("You see " + targetPlayer.Name + ", " + description + ".")
+		return
+	}
+
+	if item := game.findExaminableItem(player, strings.ToLower(target)); item != nil {
+		player.Broadcast(describeItem(item))
 		return
 	}
 
 	player.Broadcast("You don't see that here.")
 }
+
+// findExaminableItem looks for an item the player is carrying, wearing or
+// can see on the ground.
+func (g *Game) findExaminableItem(player *components.Player, name string) *components.Item {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err == nil {
+		if inventory, err := ecs.GetTypedComponent[*components.Inventory](g.world, playerEntity, "Inventory"); err == nil {
+			if item := findItemByName(inventory.GetItems(), name); item != nil {
+				return item
+			}
+		}
+		if equipment, err := ecs.GetTypedComponent[*components.Equipment](g.world, playerEntity, "Equipment"); err == nil {
+			if item := findItemByName(equipment.GetItems(), name); item != nil {
+				return item
+			}
+		}
+	}
+
+	return findItemByName(player.Area.GetGroundItems(), name)
+}
+
+// describeItem lists an item's rarity, affixes, stats and condition.
+func describeItem(item *components.Item) string {
+	condition := item.Condition()
+
+	item.RLock()
+	defer item.RUnlock()
+
+	var msg strings.Builder
+	msg.WriteString(item.Rarity.Colorize(item.Name) + "\n")
+	if item.Description != "" {
+		msg.WriteString(item.Description + "\n")
+	}
+
+	if item.Slot != components.SlotNone {
+		msg.WriteString(fmt.Sprintf("Rarity: %s\n", item.Rarity.Colorize(item.Rarity.Label())))
+		msg.WriteString(fmt.Sprintf("Slot: %s\n", item.Slot))
+	}
+	if len(item.Affixes) > 0 {
+		msg.WriteString(fmt.Sprintf("Affixes: %s\n", strings.Join(item.Affixes, ", ")))
+	}
+
+	mods := item.Modifiers
+	if mods.MinDamage != 0 || mods.MaxDamage != 0 {
+		msg.WriteString(fmt.Sprintf("Damage: +%d-%d\n", mods.MinDamage, mods.MaxDamage))
+	}
+	if item.Type == components.ItemTypeWeapon {
+		msg.WriteString(fmt.Sprintf("Damage type: %s\n", item.DamageType))
+	}
+	if resistances := item.Resistances.Describe(); resistances != "" {
+		msg.WriteString(fmt.Sprintf("Resistances: %s\n", resistances))
+	}
+	if mods.Armor != 0 {
+		msg.WriteString(fmt.Sprintf("Armor: +%d\n", mods.Armor))
+	}
+	if mods.MaxHP != 0 {
+		msg.WriteString(fmt.Sprintf("Max HP: +%d\n", mods.MaxHP))
+	}
+	if condition != "" {
+		msg.WriteString(fmt.Sprintf("Condition: %s (%d/%d)\n", condition, item.Durability, item.MaxDurability))
+	}
+	if item.LevelRequirement > 0 {
+		msg.WriteString(fmt.Sprintf("Requires level %d\n", item.LevelRequirement))
+	}
+	msg.WriteString(fmt.Sprintf("Weight: %.1f  Value: %s", item.Weight, components.FormatCurrency(item.Value)))
+
+	return msg.String()
+}
+
+// withArticle puts "a" or "an" in front of a noun phrase.
+func withArticle(phrase string) string {
+	if phrase != "" && strings.ContainsRune("AEIOUaeiou", rune(phrase[0])) {
+		return "an " + phrase
+	}
+	return "a " + phrase
+}
@@ -22,6 +22,10 @@ func (g *Game) HandleMove(player *components.Player, direction string) {
 		return
 	}
 
+	if g.isDown(player, playerEntity.ID) {
+		return
+	}
+
 	movement := &components.Movement{
 		Direction: direction,
 		Status:    components.Walking,
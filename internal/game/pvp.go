@@ -0,0 +1,137 @@
+package game
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"dmud/internal/systems"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (g *Game) handlePvP(player *components.Player, args []string, game *Game) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	pvp, err := ecs.GetTypedComponent[*components.PvP](g.world, playerEntity, "PvP")
+	if err != nil {
+		return
+	}
+
+	if len(args) == 0 {
+		if pvp.IsEnabled() {
+			player.Broadcast("PvP is on. Other players who have turned it on can attack you.")
+		} else {
+			player.Broadcast("PvP is off. Type 'pvp on' to fight other players.")
+		}
+		return
+	}
+
+	var enable bool
+	switch strings.ToLower(args[0]) {
+	case "on":
+		enable = true
+	case "off":
+		enable = false
+	default:
+		player.Broadcast("Usage: pvp [on|off]")
+		return
+	}
+
+	if enable == pvp.IsEnabled() {
+		player.Broadcast(fmt.Sprintf("PvP is already %s.", args[0]))
+		return
+	}
+	if g.inCombat(playerEntity) {
+		player.Broadcast("You can't change your PvP flag in the middle of a fight.")
+		return
+	}
+	if remaining := pvp.CooldownRemaining(g.world.Now()); remaining > 0 {
+		player.Broadcast(fmt.Sprintf("You can't change your PvP flag again for %s.", remaining.Round(time.Second)))
+		return
+	}
+
+	pvp.Set(enable, g.world.Now())
+	if enable {
+		player.Broadcast("PvP is now on. Other players who have turned it on can attack you.")
+	} else {
+		player.Broadcast("PvP is now off. Other players can no longer attack you.")
+	}
+}
+
+// handleDuel challenges another player in the area to a duel, or accepts
+// their challenge. A duel is fought to 1 health, so nobody dies.
+func (g *Game) handleDuel(player *components.Player, args []string, game *Game) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	duel, err := ecs.GetTypedComponent[*components.Duel](g.world, playerEntity, "Duel")
+	if err != nil {
+		return
+	}
+
+	if len(args) == 0 {
+		player.Broadcast("Duel whom?")
+		return
+	}
+
+	name := strings.Join(args, " ")
+	if strings.EqualFold(name, player.Name) {
+		player.Broadcast("You can't duel yourself.")
+		return
+	}
+
+	target, ok := g.findPlayerHere(player, name)
+	if !ok {
+		player.Broadcast("They aren't here.")
+		return
+	}
+
+	if g.isDown(player, playerEntity) {
+		return
+	}
+
+	if player.Area.HasFlag(components.SafeFlag) {
+		player.Broadcast("This is a safe place. Nobody can fight other players here.")
+		return
+	}
+
+	targetDuel, err := ecs.GetTypedComponent[*components.Duel](g.world, target.ID, "Duel")
+	if err != nil {
+		return
+	}
+	if opponent := duel.GetOpponent(); opponent != "" && systems.Dueling(g.world, playerEntity, opponent) {
+		player.Broadcast("You are already fighting a duel.")
+		return
+	}
+	if opponent := targetDuel.GetOpponent(); opponent != "" && systems.Dueling(g.world, target.ID, opponent) {
+		player.Broadcast(fmt.Sprintf("%s is already fighting a duel.", target.Name))
+		return
+	}
+
+	// Challenging someone who has challenged you accepts their challenge
+	if duel.HasChallengeFrom(target.ID, g.world.Now()) {
+		duel.Start(target.ID, player.Area, g.world.Now())
+		targetDuel.Start(playerEntity, player.Area, g.world.Now())
+		g.engage(playerEntity, []common.EntityID{target.ID})
+		g.engage(target.ID, []common.EntityID{playerEntity})
+
+		player.Broadcast(fmt.Sprintf("You accept %s's challenge. The duel begins!", target.Name))
+		target.Player.Broadcast(fmt.Sprintf("%s accepts your challenge. The duel begins!", player.Name))
+		player.Area.Broadcast(fmt.Sprintf("%s and %s begin a duel!", target.Name, player.Name), player, target.Player)
+		return
+	}
+
+	targetDuel.Challenge(playerEntity, g.world.Now())
+	player.Broadcast(fmt.Sprintf("You challenge %s to a duel.", target.Name))
+	target.Player.Broadcast(fmt.Sprintf("%s challenges you to a duel. Type 'duel %s' to accept.", player.Name, player.Name))
+}
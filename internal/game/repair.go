@@ -0,0 +1,166 @@
+package game
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultRepairRate is used for smiths whose template doesn't set a rate.
+const defaultRepairRate = 0.5
+
+// repairJob is a damaged item a smith could fix. Equipped items are
+// repaired in place; carried ones through the inventory.
+type repairJob struct {
+	item     *components.Item
+	equipped bool
+	cost     int
+}
+
+// findNPCByBehavior returns an NPC in the player's area that behaves as
+// given, such as a smith or a banker.
+func (g *Game) findNPCByBehavior(player *components.Player, behavior components.NPCBehavior) *components.NPC {
+	entities, err := g.world.FindEntitiesByComponentPredicate("NPC", func(i interface{}) bool {
+		npc, ok := i.(*components.NPC)
+		return ok && npc.Area == player.Area && npc.Behavior == behavior
+	})
+	if err != nil || len(entities) == 0 {
+		return nil
+	}
+
+	npc, err := ecs.GetTypedComponent[*components.NPC](g.world, entities[0].ID, "NPC")
+	if err != nil {
+		return nil
+	}
+	return npc
+}
+
+// findSmith returns an NPC in the player's area who repairs gear, along with
+// the rate they charge.
+func (g *Game) findSmith(player *components.Player) (*components.NPC, float64) {
+	npc := g.findNPCByBehavior(player, components.BehaviorSmith)
+	if npc == nil {
+		return nil, 0
+	}
+
+	rate := defaultRepairRate
+	if template, ok := components.NPCTemplates[npc.TemplateID]; ok && template.RepairRate > 0 {
+		rate = template.RepairRate
+	}
+	return npc, rate
+}
+
+// repairJobs lists the player's damaged gear, equipped items first.
+func repairJobs(inventory *components.Inventory, equipment *components.Equipment, rate float64) []repairJob {
+	var jobs []repairJob
+	if equipment != nil {
+		for _, item := range equipment.GetItems() {
+			if cost := item.RepairCost(rate); cost > 0 {
+				jobs = append(jobs, repairJob{item: item, equipped: true, cost: cost})
+			}
+		}
+	}
+	for _, item := range inventory.GetItems() {
+		if cost := item.RepairCost(rate); cost > 0 {
+			jobs = append(jobs, repairJob{item: item, cost: cost})
+		}
+	}
+	return jobs
+}
+
+func (g *Game) handleRepair(player *components.Player, args []string, game *Game) {
+	npc, rate := g.findSmith(player)
+	if npc == nil {
+		player.Broadcast("There is no smith here.")
+		return
+	}
+
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	inventory, err := ecs.GetTypedComponent[*components.Inventory](g.world, playerEntity, "Inventory")
+	if err != nil {
+		player.Broadcast("You don't have an inventory!")
+		return
+	}
+	equipment, _ := ecs.GetTypedComponent[*components.Equipment](g.world, playerEntity, "Equipment")
+
+	jobs := repairJobs(inventory, equipment, rate)
+	if len(jobs) == 0 {
+		player.Broadcast(fmt.Sprintf("%s says: Your gear is in fine shape.", npc.Name))
+		return
+	}
+
+	if len(args) == 0 {
+		var output strings.Builder
+		output.WriteString("==============================================\n")
+		output.WriteString("                  REPAIRS                     \n")
+		output.WriteString("==============================================\n\n")
+		for _, job := range jobs {
+			output.WriteString(fmt.Sprintf("  %-30s %-14s %s\n", job.item.Name, job.item.Condition(), components.FormatCurrency(job.cost)))
+		}
+		output.WriteString("\nType 'repair <item>' or 'repair all'.\n")
+		output.WriteString("==============================================\n")
+		player.Broadcast(output.String())
+		return
+	}
+
+	target := strings.ToLower(strings.Join(args, " "))
+	if target != "all" {
+		var selected []repairJob
+		for _, job := range jobs {
+			if strings.Contains(strings.ToLower(job.item.Name), target) {
+				selected = append(selected, job)
+				break
+			}
+		}
+		if len(selected) == 0 {
+			player.Broadcast(fmt.Sprintf("%s says: I don't see anything like that needing repair.", npc.Name))
+			return
+		}
+		jobs = selected
+	}
+
+	wallet := g.playerWallet(playerEntity)
+	if wallet == nil {
+		player.Broadcast("You don't have any money.")
+		return
+	}
+
+	for _, job := range jobs {
+		if !wallet.Withdraw(job.cost, fmt.Sprintf("repaired %s at %s", job.item.Name, npc.Name), g.world.Now()) {
+			player.Broadcast(fmt.Sprintf("Repairing %s costs %s. You can't afford it.", job.item.Name, components.FormatCurrency(job.cost)))
+			break
+		}
+
+		g.repairItem(player, playerEntity, inventory, job)
+
+		player.Broadcast(fmt.Sprintf("%s repairs your %s for %s.", npc.Name, job.item.Name, components.FormatCurrency(job.cost)))
+		player.Area.Broadcast(fmt.Sprintf("%s has %s repair %s.", player.Name, npc.Name, job.item.Name), player)
+	}
+}
+
+// repairItem restores an item to full durability. Mending equipped gear
+// that had broken brings its bonuses back at once.
+func (g *Game) repairItem(player *components.Player, entityID common.EntityID, inventory *components.Inventory, job repairJob) {
+	if !job.equipped {
+		inventory.UpdateItem(job.item.Key(), func(item *components.Item) {
+			item.Repair()
+		})
+		return
+	}
+
+	wasBroken := job.item.IsBroken()
+	job.item.Repair()
+	if wasBroken {
+		g.refreshCombatDamage(entityID)
+		g.adjustMaxHealth(player, entityID, job.item.ActiveModifiers().MaxHP)
+	}
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"dmud/internal/common"
+	"dmud/internal/recording"
+)
+
+// Advance moves a game made with a Clock forward to t, ticking the world
+// every tickInterval on the way as the game loop would. It does nothing for
+// games that run on the wall clock.
+func (g *Game) Advance(t time.Time) {
+	clock := g.config.Clock
+	if clock == nil {
+		return
+	}
+
+	for now := clock.Now(); now.Before(t); {
+		now = now.Add(tickInterval)
+		if now.After(t) {
+			now = t
+		}
+		clock.Set(now)
+		g.world.Update()
+	}
+}
+
+// Refeed connects client to a game made with a Clock and plays a recorded
+// session's commands as them. Each command is issued once the world clock
+// reaches the offset it was recorded at, and the world runs on for settle
+// after the last one so fights in progress can finish. before, if set, is
+// called with each command just before it is issued.
+//
+// Given the same seed, start time, resources and session, the client is
+// sent the same output every time.
+func (g *Game) Refeed(client common.Client, events []recording.Event, settle time.Duration, before func(recording.Event)) error {
+	clock := g.config.Clock
+	if clock == nil {
+		return fmt.Errorf("refeeding a session needs a game with a manual clock")
+	}
+
+	start := clock.Now()
+	g.HandleConnect(client)
+
+	var last time.Duration
+	for _, event := range events {
+		if !event.IsInput() {
+			continue
+		}
+
+		g.Advance(start.Add(event.Elapsed()))
+		if before != nil {
+			before(event)
+		}
+		g.HandleCommand(commandFromLine(client, event.Input))
+		last = event.Elapsed()
+	}
+
+	g.Advance(start.Add(last + settle))
+	return nil
+}
+
+// commandFromLine splits a line of input the same way the network clients do.
+func commandFromLine(client common.Client, line string) ClientCommand {
+	parts := strings.SplitN(strings.TrimSpace(line), " ", 2)
+	var args []string
+	if len(parts) > 1 {
+		args = strings.Split(parts[1], " ")
+	}
+	return ClientCommand{Client: client, Cmd: parts[0], Args: args}
+}
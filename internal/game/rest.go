@@ -0,0 +1,167 @@
+package game
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (g *Game) handleRest(player *components.Player, args []string, game *Game) {
+	g.lieDown(player, false)
+}
+
+func (g *Game) handleSleep(player *components.Player, args []string, game *Game) {
+	g.lieDown(player, true)
+}
+
+// lieDown puts the player down to rest or sleep, which speeds up their
+// regeneration until they stand.
+func (g *Game) lieDown(player *components.Player, asleep bool) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	posture, err := ecs.GetTypedComponent[*components.Posture](g.world, playerEntity, "Posture")
+	if err != nil {
+		return
+	}
+
+	if g.inCombat(playerEntity) {
+		player.Broadcast("You can't rest while fighting!")
+		return
+	}
+
+	switch {
+	case asleep && posture.IsAsleep():
+		player.Broadcast("You are already asleep.")
+	case !asleep && posture.IsDown() && !posture.IsAsleep():
+		player.Broadcast("You are already resting.")
+	case asleep:
+		posture.LieDown(true)
+		player.Broadcast("You lie down and go to sleep.")
+		player.Area.Broadcast(fmt.Sprintf("%s lies down and goes to sleep.", player.Name), player)
+	default:
+		posture.LieDown(false)
+		player.Broadcast("You sit down and rest.")
+		player.Area.Broadcast(fmt.Sprintf("%s sits down and rests.", player.Name), player)
+	}
+}
+
+func (g *Game) handleStand(player *components.Player, args []string, game *Game) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	posture, err := ecs.GetTypedComponent[*components.Posture](g.world, playerEntity, "Posture")
+	if err != nil {
+		return
+	}
+
+	if !posture.Stand() {
+		player.Broadcast("You are already standing.")
+		return
+	}
+	player.Broadcast("You stand up.")
+	player.Area.Broadcast(fmt.Sprintf("%s stands up.", player.Name), player)
+}
+
+// isDown reports whether the player is resting or asleep, telling them to
+// stand up first if so.
+func (g *Game) isDown(player *components.Player, entityID common.EntityID) bool {
+	posture, err := ecs.GetTypedComponent[*components.Posture](g.world, entityID, "Posture")
+	if err != nil || !posture.IsDown() {
+		return false
+	}
+	player.Broadcast("You need to stand up first.")
+	return true
+}
+
+// refreshPools recalculates a player's maximum mana and stamina from their
+// level and attributes, filling them up if refill is set.
+func (g *Game) refreshPools(entityID common.EntityID, refill bool) {
+	level := 1
+	if experience, err := ecs.GetTypedComponent[*components.Experience](g.world, entityID, "Experience"); err == nil {
+		level = experience.GetLevel()
+	}
+	attributes, _ := ecs.GetTypedComponent[*components.Attributes](g.world, entityID, "Attributes")
+
+	if mana, err := ecs.GetTypedComponent[*components.Mana](g.world, entityID, "Mana"); err == nil {
+		mana.SetMax(components.PlayerMaxMana(level, attributes), refill)
+	}
+	if stamina, err := ecs.GetTypedComponent[*components.Stamina](g.world, entityID, "Stamina"); err == nil {
+		stamina.SetMax(components.PlayerMaxStamina(level, attributes), refill)
+	}
+}
+
+// payAbilityCost takes an ability's cost from the pool it uses, telling the
+// player if they can't afford it.
+func (g *Game) payAbilityCost(player *components.Player, entityID common.EntityID, ability *components.Ability) bool {
+	if ability.Cost == 0 {
+		return true
+	}
+
+	var pool *components.Pool
+	switch ability.Resource {
+	case components.ResourceMana:
+		if mana, err := ecs.GetTypedComponent[*components.Mana](g.world, entityID, "Mana"); err == nil {
+			pool = &mana.Pool
+		}
+	case components.ResourceStamina:
+		if stamina, err := ecs.GetTypedComponent[*components.Stamina](g.world, entityID, "Stamina"); err == nil {
+			pool = &stamina.Pool
+		}
+	default:
+		return true
+	}
+
+	if pool == nil || !pool.Spend(ability.Cost) {
+		player.Broadcast(fmt.Sprintf("You don't have enough %s to %s.", ability.Resource, ability.ID))
+		return false
+	}
+	return true
+}
+
+// describePools reads like "Mana: 40/50   Stamina: 50/50".
+func (g *Game) describePools(entityID common.EntityID) string {
+	manaCurrent, manaMax := 0, 0
+	if mana, err := ecs.GetTypedComponent[*components.Mana](g.world, entityID, "Mana"); err == nil {
+		manaCurrent, manaMax = mana.Get()
+	}
+	staminaCurrent, staminaMax := 0, 0
+	if stamina, err := ecs.GetTypedComponent[*components.Stamina](g.world, entityID, "Stamina"); err == nil {
+		staminaCurrent, staminaMax = stamina.Get()
+	}
+	return fmt.Sprintf("Mana: %d/%d   Stamina: %d/%d", manaCurrent, manaMax, staminaCurrent, staminaMax)
+}
+
+// prompt shows a player's health, mana and stamina, like
+// "<95/100hp 40/50mn 50/50st> ".
+func (g *Game) prompt(player *components.Player) string {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		return "> "
+	}
+	health, err := ecs.GetTypedComponent[*components.Health](g.world, playerEntity, "Health")
+	if err != nil {
+		return "> "
+	}
+	health.RLock()
+	hp := fmt.Sprintf("%d/%dhp", health.Current, health.Max)
+	health.RUnlock()
+
+	mana, _ := ecs.GetTypedComponent[*components.Mana](g.world, playerEntity, "Mana")
+	stamina, _ := ecs.GetTypedComponent[*components.Stamina](g.world, playerEntity, "Stamina")
+	if mana == nil || stamina == nil {
+		return fmt.Sprintf("<%s> ", hp)
+	}
+	manaCurrent, manaMax := mana.Get()
+	staminaCurrent, staminaMax := stamina.Get()
+	return fmt.Sprintf("<%s %d/%dmn %d/%dst> ", hp, manaCurrent, manaMax, staminaCurrent, staminaMax)
+}
@@ -0,0 +1,244 @@
+package game
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// findMerchant returns a trading NPC in the player's area along with its shop.
+func (g *Game) findMerchant(player *components.Player) (*components.NPC, *components.Shop) {
+	entities, err := g.world.FindEntitiesByComponentPredicate("NPC", func(i interface{}) bool {
+		npc, ok := i.(*components.NPC)
+		return ok && npc.Area == player.Area && npc.Behavior == components.BehaviorMerchant
+	})
+	if err != nil {
+		return nil, nil
+	}
+
+	for _, entity := range entities {
+		shop, err := ecs.GetTypedComponent[*components.Shop](g.world, entity.ID, "Shop")
+		if err != nil {
+			continue
+		}
+		npc, err := ecs.GetTypedComponent[*components.NPC](g.world, entity.ID, "NPC")
+		if err != nil {
+			continue
+		}
+		return npc, shop
+	}
+
+	return nil, nil
+}
+
+// shopVisit is everything a shop command needs: the merchant and the
+// customer's belongings.
+type shopVisit struct {
+	entityID  common.EntityID
+	npc       *components.NPC
+	shop      *components.Shop
+	inventory *components.Inventory
+	wallet    *components.Wallet
+}
+
+// visitShop looks up the merchant in the player's area, telling the player
+// why if trading isn't possible.
+func (g *Game) visitShop(player *components.Player) (*shopVisit, bool) {
+	npc, shop := g.findMerchant(player)
+	if shop == nil {
+		player.Broadcast("There is no merchant here.")
+		return nil, false
+	}
+
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return nil, false
+	}
+
+	inventory, err := ecs.GetTypedComponent[*components.Inventory](g.world, playerEntity, "Inventory")
+	if err != nil {
+		player.Broadcast("You don't have an inventory!")
+		return nil, false
+	}
+
+	wallet := g.playerWallet(playerEntity)
+	if wallet == nil {
+		player.Broadcast("You don't have any money.")
+		return nil, false
+	}
+
+	return &shopVisit{entityID: playerEntity, npc: npc, shop: shop, inventory: inventory, wallet: wallet}, true
+}
+
+func (g *Game) handleList(player *components.Player, args []string, game *Game) {
+	visit, ok := g.visitShop(player)
+	if !ok {
+		return
+	}
+	npc, shop := visit.npc, visit.shop
+
+	var output strings.Builder
+	output.WriteString("==============================================\n")
+	output.WriteString(fmt.Sprintf("  Wares of %s\n", npc.Name))
+	output.WriteString("==============================================\n\n")
+
+	for _, entry := range shop.GetStock() {
+		template, exists := components.ItemTemplates[entry.ItemID]
+		if !exists {
+			continue
+		}
+
+		stock := fmt.Sprintf("%d left", entry.Quantity)
+		if entry.Quantity == 0 {
+			stock = "sold out"
+		}
+		output.WriteString(fmt.Sprintf("  %-24s %-16s (%s)\n", template.Name, components.FormatCurrency(shop.BuyPrice(template)), stock))
+	}
+
+	output.WriteString("==============================================\n")
+
+	player.Broadcast(output.String())
+}
+
+func (g *Game) handleBuy(player *components.Player, args []string, game *Game) {
+	if len(args) == 0 {
+		player.Broadcast("Buy what? Usage: buy <item>")
+		return
+	}
+
+	visit, ok := g.visitShop(player)
+	if !ok {
+		return
+	}
+	npc, shop, inventory := visit.npc, visit.shop, visit.inventory
+
+	itemName := strings.ToLower(strings.Join(args, " "))
+
+	var template *components.Item
+	var stock components.ShopStock
+	for _, entry := range shop.GetStock() {
+		t, exists := components.ItemTemplates[entry.ItemID]
+		if exists && strings.Contains(strings.ToLower(t.Name), itemName) {
+			template = t
+			stock = entry
+			break
+		}
+	}
+
+	if template == nil {
+		player.Broadcast(fmt.Sprintf("%s says: I don't sell that.", npc.Name))
+		return
+	}
+	if stock.Quantity == 0 {
+		player.Broadcast(fmt.Sprintf("%s says: I'm sold out of %s. Come back later.", npc.Name, template.Name))
+		return
+	}
+
+	price := shop.BuyPrice(template)
+	if inventory.IsFull() {
+		player.Broadcast("Your inventory is full!")
+		return
+	}
+	item := components.CreateItem(template.ID, 1)
+	if !g.canCarry(player, visit.entityID, item) {
+		return
+	}
+	if !shop.TakeStock(template.ID) {
+		player.Broadcast(fmt.Sprintf("%s says: I'm sold out of %s. Come back later.", npc.Name, template.Name))
+		return
+	}
+	if !visit.wallet.Withdraw(price, fmt.Sprintf("bought %s from %s", template.Name, npc.Name), g.world.Now()) {
+		shop.ReturnStock(template.ID, 1)
+		player.Broadcast(fmt.Sprintf("%s costs %s. You can't afford it.", template.Name, components.FormatCurrency(price)))
+		return
+	}
+
+	inventory.AddItem(item)
+
+	player.Broadcast(fmt.Sprintf("You buy %s for %s.", template.Name, components.FormatCurrency(price)))
+	player.Area.Broadcast(fmt.Sprintf("%s buys %s from %s.", player.Name, template.Name, npc.Name), player)
+}
+
+func (g *Game) handleSell(player *components.Player, args []string, game *Game) {
+	if len(args) == 0 {
+		player.Broadcast("Sell what? Usage: sell <item>")
+		return
+	}
+
+	visit, ok := g.visitShop(player)
+	if !ok {
+		return
+	}
+	npc, shop, inventory := visit.npc, visit.shop, visit.inventory
+
+	item, price, ok := g.appraise(player, visit, strings.ToLower(strings.Join(args, " ")))
+	if !ok {
+		return
+	}
+
+	if inventory.RemoveItem(item.Key(), 1) == nil {
+		player.Broadcast("You don't have that item.")
+		return
+	}
+
+	visit.wallet.Deposit(price, fmt.Sprintf("sold %s to %s", item.Name, npc.Name), g.world.Now())
+	shop.ReturnStock(item.ID, 1)
+
+	player.Broadcast(fmt.Sprintf("You sell %s for %s.", item.Name, components.FormatCurrency(price)))
+	player.Area.Broadcast(fmt.Sprintf("%s sells %s to %s.", player.Name, item.Name, npc.Name), player)
+}
+
+func (g *Game) handleValue(player *components.Player, args []string, game *Game) {
+	if len(args) == 0 {
+		player.Broadcast("Value what? Usage: value <item>")
+		return
+	}
+
+	visit, ok := g.visitShop(player)
+	if !ok {
+		return
+	}
+
+	item, price, ok := g.appraise(player, visit, strings.ToLower(strings.Join(args, " ")))
+	if !ok {
+		return
+	}
+
+	player.Broadcast(fmt.Sprintf("%s says: I'll give you %s for %s.", visit.npc.Name, components.FormatCurrency(price), item.Name))
+}
+
+// appraise finds an item the player wants to sell and what the merchant will
+// pay for it.
+func (g *Game) appraise(player *components.Player, visit *shopVisit, itemName string) (*components.Item, int, bool) {
+	npc := visit.npc
+
+	item := findItemByName(visit.inventory.GetItems(), itemName)
+	if item == nil {
+		player.Broadcast("You don't have that item.")
+		return nil, 0, false
+	}
+
+	if item.Type == components.ItemTypeCurrency {
+		player.Broadcast(fmt.Sprintf("%s says: I don't buy money.", npc.Name))
+		return nil, 0, false
+	}
+
+	// The sale only pays for the bag, so whatever is inside would be lost
+	if item.Container != nil && !item.Container.IsEmpty() {
+		player.Broadcast(fmt.Sprintf("%s says: Empty %s first; I only buy the bag.", npc.Name, item.Name))
+		return nil, 0, false
+	}
+
+	price := visit.shop.SellPrice(item)
+	if price <= 0 {
+		player.Broadcast(fmt.Sprintf("%s says: %s is worthless to me.", npc.Name, item.Name))
+		return nil, 0, false
+	}
+
+	return item, price, true
+}
@@ -0,0 +1,611 @@
+package game
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// trader is one side of a trade with everything needed to carry it out.
+type trader struct {
+	entityID  common.EntityID
+	player    *components.Player
+	inventory *components.Inventory
+	wallet    *components.Wallet
+	offer     components.TradeOffer
+}
+
+// loadTrader gathers a trading player's components.
+func (g *Game) loadTrader(entityID common.EntityID, session *components.TradeSession) (*trader, error) {
+	player, err := ecs.GetTypedComponent[*components.Player](g.world, entityID, "Player")
+	if err != nil {
+		return nil, err
+	}
+	inventory, err := ecs.GetTypedComponent[*components.Inventory](g.world, entityID, "Inventory")
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := ecs.GetTypedComponent[*components.Wallet](g.world, entityID, "Wallet")
+	if err != nil {
+		return nil, err
+	}
+
+	t := &trader{entityID: entityID, player: player, inventory: inventory, wallet: wallet}
+	if session != nil {
+		t.offer = session.GetOffer(entityID)
+	}
+	return t, nil
+}
+
+// activeTrade returns the player's trade session and their partner, telling
+// the player why if they can't trade right now. A trade whose partner has
+// wandered off is cancelled.
+func (g *Game) activeTrade(player *components.Player) (common.EntityID, *components.TradeSession, *trader, bool) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return "", nil, nil, false
+	}
+
+	trade, err := ecs.GetTypedComponent[*components.Trade](g.world, playerEntity, "Trade")
+	if err != nil || trade.GetSession() == nil {
+		player.Broadcast("You aren't trading with anyone. Usage: trade <player>")
+		return "", nil, nil, false
+	}
+	session := trade.GetSession()
+
+	partnerID, partnerName := session.Partner(playerEntity)
+	partner, err := g.loadTrader(partnerID, session)
+	if err != nil || partner.player.Area != player.Area {
+		g.endTrade(session)
+		player.Broadcast(fmt.Sprintf("%s is no longer here. The trade is cancelled.", partnerName))
+		if partner != nil {
+			partner.player.Broadcast(fmt.Sprintf("You are no longer with %s. The trade is cancelled.", player.Name))
+		}
+		return "", nil, nil, false
+	}
+
+	self, err := g.loadTrader(playerEntity, session)
+	if err != nil {
+		log.Error().Err(err).Msg("Error loading trader")
+		return "", nil, nil, false
+	}
+	g.checkOfferedItems(session, self, partner)
+	g.checkOfferedItems(session, partner, self)
+	partner.offer = session.GetOffer(partnerID)
+
+	return playerEntity, session, partner, true
+}
+
+// checkOfferedItems looks for anything owner has offered that has changed
+// since, such as a bag that has been filled or a weapon that has worn down.
+// The offer is updated to match and both players have to accept again.
+func (g *Game) checkOfferedItems(session *components.TradeSession, owner, other *trader) {
+	items := owner.inventory.GetItems()
+	for _, offered := range owner.offer.Items {
+		item := findItemByKey(items, offered.Key)
+		if item == nil || !offered.Changed(item) {
+			continue
+		}
+
+		session.UpdateItem(owner.entityID, components.NewTradeItem(item, offered.Quantity))
+		msg := fmt.Sprintf("%s's %s has changed since it was offered. Both of you must accept again.", owner.player.Name, offered.Name)
+		owner.player.Broadcast(msg)
+		other.player.Broadcast(msg)
+	}
+}
+
+func (g *Game) handleTrade(player *components.Player, args []string, game *Game) {
+	if len(args) == 0 {
+		playerEntity, session, partner, ok := g.activeTrade(player)
+		if !ok {
+			return
+		}
+		player.Broadcast(describeTrade(session, playerEntity, partner.player.Name))
+		return
+	}
+
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	trade, err := ecs.GetTypedComponent[*components.Trade](g.world, playerEntity, "Trade")
+	if err != nil {
+		player.Broadcast("You can't trade.")
+		return
+	}
+
+	if strings.EqualFold(args[0], "cancel") {
+		g.cancelTrade(player, playerEntity, trade)
+		return
+	}
+
+	if session := trade.GetSession(); session != nil {
+		_, partnerName := session.Partner(playerEntity)
+		player.Broadcast(fmt.Sprintf("You are already trading with %s. Type 'trade cancel' to stop.", partnerName))
+		return
+	}
+
+	var target *components.Player
+	player.Area.PlayersMutex.RLock()
+	for _, p := range player.Area.Players {
+		if strings.EqualFold(p.Name, args[0]) {
+			target = p
+			break
+		}
+	}
+	player.Area.PlayersMutex.RUnlock()
+
+	if target == nil {
+		player.Broadcast("You don't see them here.")
+		return
+	}
+	if target == player {
+		player.Broadcast("You can't trade with yourself.")
+		return
+	}
+
+	targetEntity, err := g.getPlayerEntity(target)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting target player entity")
+		return
+	}
+	targetTrade, err := ecs.GetTypedComponent[*components.Trade](g.world, targetEntity, "Trade")
+	if err != nil {
+		player.Broadcast(fmt.Sprintf("%s can't trade.", target.Name))
+		return
+	}
+	if targetTrade.GetSession() != nil {
+		player.Broadcast(fmt.Sprintf("%s is busy trading with someone else.", target.Name))
+		return
+	}
+
+	targetTrade.RLock()
+	invitedUs := targetTrade.Invited == playerEntity
+	targetTrade.RUnlock()
+
+	if !invitedUs {
+		trade.Lock()
+		trade.Invited = targetEntity
+		trade.Unlock()
+
+		player.Broadcast(fmt.Sprintf("You ask %s to trade.", target.Name))
+		target.Broadcast(fmt.Sprintf("%s wants to trade with you. Type 'trade %s' to begin.", player.Name, player.Name))
+		return
+	}
+
+	// They asked us first, so accepting their invitation opens the trade
+	session := components.NewTradeSession(targetEntity, target.Name, playerEntity, player.Name)
+	for _, t := range []*components.Trade{trade, targetTrade} {
+		t.Lock()
+		t.Invited = ""
+		t.Session = session
+		t.Unlock()
+	}
+
+	help := "Use 'offer <item>' or 'offer <amount> <gold|silver|copper>', 'retract', and 'accept' when you are happy."
+	player.Broadcast(fmt.Sprintf("You begin trading with %s. %s", target.Name, help))
+	target.Broadcast(fmt.Sprintf("You begin trading with %s. %s", player.Name, help))
+}
+
+// cancelTrade ends the player's trade or withdraws their invitation.
+func (g *Game) cancelTrade(player *components.Player, playerEntity common.EntityID, trade *components.Trade) {
+	session := trade.GetSession()
+	if session == nil {
+		trade.Lock()
+		invited := trade.Invited
+		trade.Invited = ""
+		trade.Unlock()
+
+		if invited == "" {
+			player.Broadcast("You aren't trading with anyone.")
+		} else {
+			player.Broadcast("You withdraw your offer to trade.")
+		}
+		return
+	}
+
+	partnerID, partnerName := session.Partner(playerEntity)
+	g.endTrade(session)
+
+	player.Broadcast(fmt.Sprintf("You cancel the trade with %s.", partnerName))
+	if partner, err := ecs.GetTypedComponent[*components.Player](g.world, partnerID, "Player"); err == nil {
+		partner.Broadcast(fmt.Sprintf("%s cancels the trade.", player.Name))
+	}
+}
+
+// endTrade closes a session for both players.
+func (g *Game) endTrade(session *components.TradeSession) {
+	for _, id := range session.Players {
+		trade, err := ecs.GetTypedComponent[*components.Trade](g.world, id, "Trade")
+		if err != nil {
+			continue
+		}
+		trade.Lock()
+		if trade.Session == session {
+			trade.Session = nil
+		}
+		trade.Unlock()
+	}
+}
+
+// cancelTradesFor cancels the trade of a player who is leaving the game.
+func (g *Game) cancelTradesFor(player *components.Player, playerEntity common.EntityID) {
+	trade, err := ecs.GetTypedComponent[*components.Trade](g.world, playerEntity, "Trade")
+	if err != nil || trade.GetSession() == nil {
+		return
+	}
+
+	session := trade.GetSession()
+	partnerID, _ := session.Partner(playerEntity)
+	g.endTrade(session)
+
+	if partner, err := ecs.GetTypedComponent[*components.Player](g.world, partnerID, "Player"); err == nil {
+		partner.Broadcast(fmt.Sprintf("%s has left. The trade is cancelled.", player.Name))
+	}
+}
+
+func (g *Game) handleOffer(player *components.Player, args []string, game *Game) {
+	if len(args) == 0 {
+		player.Broadcast("Offer what? Usage: offer <item>, offer <count> <item> or offer <amount> <gold|silver|copper>")
+		return
+	}
+
+	playerEntity, session, partner, ok := g.activeTrade(player)
+	if !ok {
+		return
+	}
+	self, err := g.loadTrader(playerEntity, session)
+	if err != nil {
+		log.Error().Err(err).Msg("Error loading trader")
+		return
+	}
+
+	count := 0
+	if n, err := strconv.Atoi(args[0]); err == nil && len(args) > 1 {
+		if n <= 0 {
+			player.Broadcast("You must offer a positive amount.")
+			return
+		}
+		count = n
+
+		if amount, err := components.ParseCurrency(n, args[1]); !errors.Is(err, components.ErrUnknownDenomination) && len(args) == 2 {
+			if err != nil {
+				player.Broadcast(currencyLimitMessage())
+				return
+			}
+			total := self.offer.Copper + amount
+			if self.wallet.Balance() < total {
+				player.Broadcast(fmt.Sprintf("You don't have %s.", components.FormatCurrency(total)))
+				return
+			}
+			session.SetCopper(playerEntity, total)
+			money := components.FormatCurrency(amount)
+			g.announceTradeChange(session, self, partner, "You offer "+money+".", fmt.Sprintf("%s offers %s.", player.Name, money))
+			return
+		}
+		args = args[1:]
+	}
+
+	item := findItemByName(self.inventory.GetItems(), strings.ToLower(strings.Join(args, " ")))
+	if item == nil {
+		player.Broadcast("You don't have that item.")
+		return
+	}
+
+	key := item.Key()
+	available := item.Quantity - session.OfferedQuantity(playerEntity, key)
+	if count == 0 {
+		count = available
+		if !item.Stackable {
+			count = 1
+		}
+	}
+	if available <= 0 || count > available {
+		player.Broadcast(fmt.Sprintf("You don't have that many %s to offer.", item.Name))
+		return
+	}
+
+	if item.Container != nil && !item.Container.IsEmpty() {
+		player.Broadcast(fmt.Sprintf("Empty %s before offering it.", item.Name))
+		return
+	}
+
+	session.OfferItem(playerEntity, components.NewTradeItem(item, count))
+	offered := tradeItemName(item.Name, count)
+	g.announceTradeChange(session, self, partner, "You offer "+offered+".", fmt.Sprintf("%s offers %s.", player.Name, offered))
+}
+
+func (g *Game) handleRetract(player *components.Player, args []string, game *Game) {
+	if len(args) == 0 {
+		player.Broadcast("Retract what? Usage: retract <item> or retract coins")
+		return
+	}
+
+	playerEntity, session, partner, ok := g.activeTrade(player)
+	if !ok {
+		return
+	}
+	self, err := g.loadTrader(playerEntity, session)
+	if err != nil {
+		log.Error().Err(err).Msg("Error loading trader")
+		return
+	}
+
+	name := strings.ToLower(strings.Join(args, " "))
+	if name == "coins" || name == "money" {
+		if self.offer.Copper == 0 {
+			player.Broadcast("You haven't offered any money.")
+			return
+		}
+		session.SetCopper(playerEntity, 0)
+		g.announceTradeChange(session, self, partner, "You take back your money.", player.Name+" takes back their money.")
+		return
+	}
+
+	for _, item := range self.offer.Items {
+		if strings.Contains(strings.ToLower(item.Name), name) {
+			session.RetractItem(playerEntity, item.Key)
+			retracted := tradeItemName(item.Name, item.Quantity)
+			g.announceTradeChange(session, self, partner, "You take back "+retracted+".", fmt.Sprintf("%s takes back %s.", player.Name, retracted))
+			return
+		}
+	}
+
+	player.Broadcast("You haven't offered that.")
+}
+
+func (g *Game) handleAccept(player *components.Player, args []string, game *Game) {
+	playerEntity, session, partner, ok := g.activeTrade(player)
+	if !ok {
+		return
+	}
+
+	if !session.Accept(playerEntity) {
+		player.Broadcast(fmt.Sprintf("You accept the trade. Waiting for %s to accept.", partner.player.Name))
+		partner.player.Broadcast(fmt.Sprintf("%s accepts the trade. Type 'accept' to complete it, or 'trade' to review it.", player.Name))
+		return
+	}
+
+	self, err := g.loadTrader(playerEntity, session)
+	if err != nil {
+		log.Error().Err(err).Msg("Error loading trader")
+		return
+	}
+
+	if reason := g.exchange(self, partner); reason != "" {
+		session.ResetAcceptance()
+		self.player.Broadcast("The trade could not be completed: " + reason)
+		partner.player.Broadcast("The trade could not be completed: " + reason)
+		return
+	}
+
+	g.endTrade(session)
+
+	log.Info().
+		Str("audit", "trade").
+		Str("player_a", self.player.Name).
+		Str("player_b", partner.player.Name).
+		Str("a_gave", describeOffer(self.offer)).
+		Str("b_gave", describeOffer(partner.offer)).
+		Msg("Trade completed")
+
+	self.player.Broadcast(fmt.Sprintf("The trade is complete. You receive %s.", describeOffer(partner.offer)))
+	partner.player.Broadcast(fmt.Sprintf("The trade is complete. You receive %s.", describeOffer(self.offer)))
+	player.Area.Broadcast(fmt.Sprintf("%s and %s complete a trade.", self.player.Name, partner.player.Name), self.player, partner.player)
+
+	self.player.BroadcastState(g.world.AsWorldLike(), self.entityID)
+	partner.player.BroadcastState(g.world.AsWorldLike(), partner.entityID)
+}
+
+// exchange swaps both offers, returning why not if it can't. Everything is
+// checked before anything moves, and if a step still fails partway the
+// items already moved are put back, so either the whole trade happens or
+// none of it does. Commands run one at a time on the game loop, so nothing
+// else can touch either inventory in between.
+func (g *Game) exchange(a, b *trader) string {
+	for _, pair := range [][2]*trader{{a, b}, {b, a}} {
+		if reason := g.checkTradeSide(pair[0], pair[1]); reason != "" {
+			return reason
+		}
+	}
+
+	removedA, ok := g.takeOffered(a)
+	if !ok {
+		return fmt.Sprintf("%s no longer has everything they offered.", a.player.Name)
+	}
+	removedB, ok := g.takeOffered(b)
+	if !ok {
+		g.restore(a, removedA)
+		return fmt.Sprintf("%s no longer has everything they offered.", b.player.Name)
+	}
+
+	if a.offer.Copper > 0 && !a.wallet.Withdraw(a.offer.Copper, "traded to "+b.player.Name, g.world.Now()) {
+		g.restore(a, removedA)
+		g.restore(b, removedB)
+		return fmt.Sprintf("%s no longer has the money they offered.", a.player.Name)
+	}
+	if b.offer.Copper > 0 && !b.wallet.Withdraw(b.offer.Copper, "traded to "+a.player.Name, g.world.Now()) {
+		if a.offer.Copper > 0 {
+			a.wallet.Deposit(a.offer.Copper, "trade with "+b.player.Name+" cancelled", g.world.Now())
+		}
+		g.restore(a, removedA)
+		g.restore(b, removedB)
+		return fmt.Sprintf("%s no longer has the money they offered.", b.player.Name)
+	}
+
+	g.restore(b, removedA)
+	g.restore(a, removedB)
+	if a.offer.Copper > 0 {
+		b.wallet.Deposit(a.offer.Copper, "traded by "+a.player.Name, g.world.Now())
+	}
+	if b.offer.Copper > 0 {
+		a.wallet.Deposit(b.offer.Copper, "traded by "+b.player.Name, g.world.Now())
+	}
+	return ""
+}
+
+// checkTradeSide makes sure from still has what they offered and that to
+// has room for it.
+func (g *Game) checkTradeSide(from, to *trader) string {
+	if from.wallet.Balance() < from.offer.Copper {
+		return fmt.Sprintf("%s no longer has the money they offered.", from.player.Name)
+	}
+
+	items := from.inventory.GetItems()
+	incoming := 0
+	weight := 0.0
+	for _, offered := range from.offer.Items {
+		item := findItemByKey(items, offered.Key)
+		if item == nil || item.Quantity < offered.Quantity {
+			return fmt.Sprintf("%s no longer has %s.", from.player.Name, tradeItemName(offered.Name, offered.Quantity))
+		}
+		if offered.Changed(item) {
+			return fmt.Sprintf("%s's %s has changed since it was offered.", from.player.Name, offered.Name)
+		}
+		incoming++
+		weight += item.TotalWeight() * float64(offered.Quantity) / float64(item.Quantity)
+	}
+
+	// Slots the receiver frees by giving whole items away count towards
+	// the room they have
+	toItems := to.inventory.GetItems()
+	freed := 0
+	outgoingWeight := 0.0
+	for _, offered := range to.offer.Items {
+		if item := findItemByKey(toItems, offered.Key); item != nil {
+			if item.Quantity == offered.Quantity {
+				freed++
+			}
+			outgoingWeight += item.TotalWeight() * float64(offered.Quantity) / float64(item.Quantity)
+		}
+	}
+
+	to.inventory.RLock()
+	maxSlots, used := to.inventory.MaxSlots, len(to.inventory.Items)
+	to.inventory.RUnlock()
+	if maxSlots > 0 && used-freed+incoming > maxSlots {
+		return fmt.Sprintf("%s doesn't have room for everything.", to.player.Name)
+	}
+
+	carried, capacity := components.CarryingWeight(g.world.AsWorldLike(), to.entityID)
+	if weight > outgoingWeight && carried-outgoingWeight+weight > capacity {
+		return fmt.Sprintf("%s can't carry that much.", to.player.Name)
+	}
+	return ""
+}
+
+// takeOffered removes a trader's offered items from their inventory. If any
+// can't be removed, the ones already taken are put back.
+func (g *Game) takeOffered(t *trader) ([]*components.Item, bool) {
+	removed := make([]*components.Item, 0, len(t.offer.Items))
+	for _, offered := range t.offer.Items {
+		item := t.inventory.RemoveItem(offered.Key, offered.Quantity)
+		if item == nil {
+			g.restore(t, removed)
+			return nil, false
+		}
+		removed = append(removed, item)
+	}
+	return removed, true
+}
+
+// restore puts items into a trader's inventory. The room checks come first,
+// so everything should fit, but anything that somehow doesn't is set on the
+// ground at their feet rather than lost.
+func (g *Game) restore(t *trader, items []*components.Item) {
+	for _, item := range items {
+		if !t.inventory.AddItem(item) {
+			t.player.Area.AddGroundItem(item, g.world.Now())
+			t.player.Broadcast(fmt.Sprintf("You have no room for %s, so you set it on the ground.", item.DisplayName()))
+		}
+	}
+}
+
+// announceTradeChange tells both players about a change to an offer and
+// shows each of them the trade as it now stands.
+func (g *Game) announceTradeChange(session *components.TradeSession, self, partner *trader, selfMsg, partnerMsg string) {
+	self.player.Broadcast(selfMsg)
+	partner.player.Broadcast(partnerMsg)
+
+	self.player.Broadcast(describeTrade(session, self.entityID, partner.player.Name))
+	partner.player.Broadcast(describeTrade(session, partner.entityID, self.player.Name))
+}
+
+// describeTrade shows both sides of a trade from one player's point of view.
+func describeTrade(session *components.TradeSession, viewer common.EntityID, partnerName string) string {
+	partnerID, _ := session.Partner(viewer)
+
+	var output strings.Builder
+	output.WriteString("==============================================\n")
+	output.WriteString("                   TRADE                      \n")
+	output.WriteString("==============================================\n\n")
+
+	sides := []struct {
+		title string
+		offer components.TradeOffer
+	}{
+		{"You offer", session.GetOffer(viewer)},
+		{partnerName + " offers", session.GetOffer(partnerID)},
+	}
+	for _, side := range sides {
+		status := ""
+		if side.offer.Accepted {
+			status = " (accepted)"
+		}
+		output.WriteString(fmt.Sprintf("%s:%s\n", side.title, status))
+		for _, item := range side.offer.Items {
+			output.WriteString(fmt.Sprintf("  %s\n", tradeItemName(item.Name, item.Quantity)))
+		}
+		if side.offer.Copper > 0 {
+			output.WriteString(fmt.Sprintf("  %s\n", components.FormatCurrency(side.offer.Copper)))
+		}
+		if len(side.offer.Items) == 0 && side.offer.Copper == 0 {
+			output.WriteString("  (nothing)\n")
+		}
+		output.WriteString("\n")
+	}
+
+	output.WriteString("==============================================\n")
+	return output.String()
+}
+
+// describeOffer lists an offer on one line.
+func describeOffer(offer components.TradeOffer) string {
+	var parts []string
+	for _, item := range offer.Items {
+		parts = append(parts, tradeItemName(item.Name, item.Quantity))
+	}
+	if offer.Copper > 0 {
+		parts = append(parts, components.FormatCurrency(offer.Copper))
+	}
+	if len(parts) == 0 {
+		return "nothing"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func tradeItemName(name string, quantity int) string {
+	if quantity > 1 {
+		return fmt.Sprintf("%s (x%d)", name, quantity)
+	}
+	return name
+}
+
+// findItemByKey returns the item with the given Key.
+func findItemByKey(items []*components.Item, key string) *components.Item {
+	for _, item := range items {
+		if item.Key() == key {
+			return item
+		}
+	}
+	return nil
+}
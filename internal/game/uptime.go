@@ -42,6 +42,13 @@ func handleUptime(player *components.Player, args []string, game *Game) {
 	totalConnects := game.TotalConnects
 	game.TotalConnectMu.RUnlock()
 
+	// Get loop health
+	game.TickStats.RLock()
+	ticks := game.TickStats.Ticks
+	slipped := game.TickStats.SlippedTicks
+	maxGap := game.TickStats.MaxGap
+	game.TickStats.RUnlock()
+
 	// Format output
 	var output string
 	output += "==============================================\n"
@@ -52,6 +59,7 @@ func handleUptime(player *components.Player, args []string, game *Game) {
 	output += fmt.Sprintf("Current players: %d\n", currentPlayers)
 	output += fmt.Sprintf("Total connects:  %d\n", totalConnects)
 	output += fmt.Sprintf("Unique players:  %d\n", uniqueIPs)
+	output += fmt.Sprintf("Loop ticks:      %d (%d slipped, worst gap %dms)\n", ticks, slipped, maxGap.Milliseconds())
 	output += "\n==============================================\n"
 
 	player.Broadcast(output)
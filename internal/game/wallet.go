@@ -0,0 +1,132 @@
+package game
+
+import (
+	"dmud/internal/components"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (g *Game) handleBalance(player *components.Player, args []string, game *Game) {
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+
+	wallet := g.playerWallet(playerEntity)
+	if wallet == nil {
+		player.Broadcast("You don't have any money.")
+		return
+	}
+
+	var output strings.Builder
+	output.WriteString("==============================================\n")
+	output.WriteString("                  BALANCE                     \n")
+	output.WriteString("==============================================\n\n")
+	output.WriteString(fmt.Sprintf("  You are carrying %s.\n", components.FormatCurrency(wallet.Balance())))
+
+	if history := wallet.GetHistory(); len(history) > 0 {
+		output.WriteString("\nRecent transactions:\n")
+		for i := len(history) - 1; i >= 0; i-- {
+			tx := history[i]
+			sign := "+"
+			if tx.Amount < 0 {
+				sign = ""
+			}
+			output.WriteString(fmt.Sprintf("  %s  %-22s %s\n", tx.Time.Format("15:04:05"), sign+components.FormatCurrency(tx.Amount), tx.Description))
+		}
+	}
+
+	output.WriteString("==============================================\n")
+
+	player.Broadcast(output.String())
+}
+
+func (g *Game) handleGive(player *components.Player, args []string, game *Game) {
+	if len(args) != 3 {
+		player.Broadcast("Give what? Usage: give <amount> <gold|silver|copper> <player>")
+		return
+	}
+
+	count, err := strconv.Atoi(args[0])
+	if err != nil || count <= 0 {
+		player.Broadcast("You must give a positive amount.")
+		return
+	}
+
+	amount, err := components.ParseCurrency(count, args[1])
+	if errors.Is(err, components.ErrUnknownDenomination) {
+		player.Broadcast("You can give gold, silver or copper.")
+		return
+	}
+	if err != nil {
+		player.Broadcast(currencyLimitMessage())
+		return
+	}
+
+	var target *components.Player
+	player.Area.PlayersMutex.RLock()
+	for _, p := range player.Area.Players {
+		if strings.EqualFold(p.Name, args[2]) {
+			target = p
+			break
+		}
+	}
+	player.Area.PlayersMutex.RUnlock()
+
+	if target == nil {
+		player.Broadcast("You don't see them here.")
+		return
+	}
+	if target == player {
+		player.Broadcast("You shuffle coins from one pocket to the other.")
+		return
+	}
+
+	playerEntity, err := g.getPlayerEntity(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting player entity")
+		return
+	}
+	targetEntity, err := g.getPlayerEntity(target)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting target player entity")
+		return
+	}
+
+	wallet := g.playerWallet(playerEntity)
+	targetWallet := g.playerWallet(targetEntity)
+	if wallet == nil || targetWallet == nil {
+		player.Broadcast("You can't give money to them.")
+		return
+	}
+
+	formatted := components.FormatCurrency(amount)
+	if !wallet.Withdraw(amount, "gave to "+target.Name, g.world.Now()) {
+		player.Broadcast(fmt.Sprintf("You don't have %s.", formatted))
+		return
+	}
+	targetWallet.Deposit(amount, "received from "+player.Name, g.world.Now())
+
+	log.Info().
+		Str("audit", "currency_transfer").
+		Str("from", player.Name).
+		Str("to", target.Name).
+		Int("copper", amount).
+		Int("from_balance", wallet.Balance()).
+		Int("to_balance", targetWallet.Balance()).
+		Msg("Currency transferred")
+
+	player.Broadcast(fmt.Sprintf("You give %s to %s.", formatted, target.Name))
+	target.Broadcast(fmt.Sprintf("%s gives you %s.", player.Name, formatted))
+	player.Area.Broadcast(fmt.Sprintf("%s gives some coins to %s.", player.Name, target.Name), player, target)
+}
+
+// currencyLimitMessage refuses an amount of money too large to handle.
+func currencyLimitMessage() string {
+	return fmt.Sprintf("You can't handle more than %s at once.", components.FormatCurrency(components.MaxCurrency))
+}
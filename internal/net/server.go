@@ -20,13 +20,17 @@ type ServerConfig struct {
 
 	WSHost string
 	WSPort string
+
+	// Game configures the game the server hosts; nil uses the defaults.
+	Game *game.Config
 }
 
 type Server struct {
 	connectionMu sync.Mutex
 	connections  map[string]common.Client
 
-	game *game.Game
+	game       *game.Game
+	gameConfig *game.Config
 
 	tcpListener net.Listener
 	tcpHost     string
@@ -42,7 +46,11 @@ type Server struct {
 
 func (s *Server) Run() {
 	var wg sync.WaitGroup
-	s.game = game.NewGame()
+	if s.gameConfig != nil {
+		s.game = game.NewGameWithConfig(s.gameConfig)
+	} else {
+		s.game = game.NewGame()
+	}
 
 	started := 0
 
@@ -215,6 +223,7 @@ func NewServer(config *ServerConfig) *Server {
 		wsHost:      config.WSHost,
 		wsPort:      config.WSPort,
 		connections: make(map[string]common.Client),
+		gameConfig:  config.Game,
 	}
 }
 
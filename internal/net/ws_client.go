@@ -170,8 +170,12 @@ func (c *WSClient) RemoteAddr() string {
 	return c.conn.RemoteAddr().String()
 }
 
+// ansiEscape matches the colour codes used for item rarities. Browsers show
+// them as junk, so they are stripped before messages go out over WebSocket.
+var ansiEscape = regexp.MustCompile("\033\\[[0-9;]*m")
+
 func (c *WSClient) SendMessage(msg string) {
-	s := msg
+	s := ansiEscape.ReplaceAllString(msg, "")
 
 	c.writeMu.Lock()
 	_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
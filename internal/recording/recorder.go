@@ -0,0 +1,157 @@
+// Package recording captures each player session's input commands and output
+// frames to a compact log so sessions can be replayed for bug reports and
+// moderation review.
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"dmud/internal/common"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Recorder hands out sequential session IDs and writes one log per session.
+type Recorder struct {
+	mu     sync.Mutex
+	dir    string
+	nextID int
+	seed   int64
+	now    func() time.Time
+}
+
+// NewRecorder writes session logs to dir, continuing the numbering of any
+// sessions already there. seed is the world's random seed, stamped into
+// every log so a re-fed session rolls the same dice, and now is the world's
+// clock, which times the session's events.
+func NewRecorder(dir string, seed int64, now func() time.Time) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	existing, err := filepath.Glob(filepath.Join(dir, "session-*.log"))
+	if err != nil {
+		return nil, err
+	}
+
+	nextID := 1
+	for _, path := range existing {
+		var id int
+		if _, err := fmt.Sscanf(filepath.Base(path), "session-%06d.log", &id); err == nil && id >= nextID {
+			nextID = id + 1
+		}
+	}
+
+	return &Recorder{dir: dir, nextID: nextID, seed: seed, now: now}, nil
+}
+
+// Wrap starts a new session for c. If the log can't be created the session is
+// played unrecorded and c is returned as-is.
+func (r *Recorder) Wrap(c common.Client) common.Client {
+	r.mu.Lock()
+	id := r.nextID
+	r.nextID++
+	r.mu.Unlock()
+
+	path := SessionPath(r.dir, id)
+	f, err := os.Create(path)
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not create session log %s", path)
+		return c
+	}
+
+	rc := &Client{
+		Client:  c,
+		id:      id,
+		file:    f,
+		encoder: json.NewEncoder(f),
+		now:     r.now,
+		start:   r.now(),
+	}
+
+	header := Header{
+		Session:    id,
+		Start:      rc.start,
+		RemoteAddr: c.RemoteAddr(),
+		Seed:       r.seed,
+	}
+	if err := rc.encoder.Encode(header); err != nil {
+		log.Error().Err(err).Msgf("Could not write session header %s", path)
+	}
+
+	log.Info().Msgf("Recording session %d for %s to %s", id, c.RemoteAddr(), path)
+	return rc
+}
+
+// Client records everything passing through the client it wraps.
+type Client struct {
+	common.Client
+
+	mu      sync.Mutex
+	id      int
+	file    *os.File
+	encoder *json.Encoder
+	now     func() time.Time
+	start   time.Time
+	closed  bool
+}
+
+// SessionID is the number used to look the recording up later.
+func (c *Client) SessionID() int {
+	return c.id
+}
+
+// Unwrap returns the underlying network client.
+func (c *Client) Unwrap() common.Client {
+	return c.Client
+}
+
+func (c *Client) SendMessage(msg string) {
+	c.write(Event{Output: msg})
+	c.Client.SendMessage(msg)
+}
+
+// RecordInput logs a command the player issued.
+func (c *Client) RecordInput(cmd string) {
+	c.write(Event{Input: cmd})
+}
+
+func (c *Client) CloseConnection() error {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		if err := c.file.Close(); err != nil {
+			log.Error().Err(err).Msgf("Error closing session log %d", c.id)
+		}
+	}
+	c.mu.Unlock()
+
+	return c.Client.CloseConnection()
+}
+
+func (c *Client) write(event Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	event.Offset = c.now().Sub(c.start).Milliseconds()
+	if err := c.encoder.Encode(event); err != nil {
+		log.Error().Err(err).Msgf("Error writing session log %d", c.id)
+	}
+}
+
+// Unwrap strips any recording wrapper from c.
+func Unwrap(c common.Client) common.Client {
+	if rc, ok := c.(*Client); ok {
+		return rc.Unwrap()
+	}
+	return c
+}
@@ -0,0 +1,66 @@
+package recording
+
+import (
+	"testing"
+	"time"
+)
+
+type stubClient struct {
+	sent   []string
+	closed bool
+}
+
+func (c *stubClient) CloseConnection() error { c.closed = true; return nil }
+func (c *stubClient) HandleRequest()         {}
+func (c *stubClient) SendMessage(msg string) { c.sent = append(c.sent, msg) }
+func (c *stubClient) RemoteAddr() string     { return "10.0.0.1:4000" }
+func (c *stubClient) SupportsPrompt() bool   { return false }
+
+func TestRecorderRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	recorder, err := NewRecorder(dir, 42, time.Now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stub := &stubClient{}
+	wrapped := recorder.Wrap(stub)
+	rc, ok := wrapped.(*Client)
+	if !ok {
+		t.Fatalf("Wrap returned %T, want *Client", wrapped)
+	}
+	if Unwrap(wrapped) != stub {
+		t.Fatal("Unwrap did not return the original client")
+	}
+
+	rc.SendMessage("Welcome!")
+	rc.RecordInput("look")
+	rc.SendMessage("You are nowhere.")
+	rc.CloseConnection()
+	rc.SendMessage("dropped after close")
+
+	if !stub.closed || len(stub.sent) != 3 {
+		t.Fatalf("wrapped client not forwarded to: closed=%v sent=%v", stub.closed, stub.sent)
+	}
+
+	header, events, err := ReadSession(SessionPath(dir, rc.SessionID()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Session != 1 || header.Seed != 42 || header.RemoteAddr != "10.0.0.1:4000" {
+		t.Fatalf("unexpected header %+v", header)
+	}
+	if len(events) != 3 || events[1].Input != "look" || events[2].Output != "You are nowhere." {
+		t.Fatalf("unexpected events %+v", events)
+	}
+
+	// A new recorder continues numbering after existing logs
+	next, err := NewRecorder(dir, 42, time.Now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := next.Wrap(&stubClient{}).(*Client).SessionID(); id != 2 {
+		t.Fatalf("next session id = %d, want 2", id)
+	}
+}
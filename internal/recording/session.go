@@ -0,0 +1,74 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Header is the first line of every session log.
+type Header struct {
+	Session    int       `json:"session"`
+	Start      time.Time `json:"start"`
+	RemoteAddr string    `json:"addr"`
+	Seed       int64     `json:"seed"`
+}
+
+// Event is a single input command or output frame, stamped with the
+// milliseconds elapsed since the session started.
+type Event struct {
+	Offset int64  `json:"t"`
+	Input  string `json:"i,omitempty"`
+	Output string `json:"o,omitempty"`
+}
+
+func (e Event) IsInput() bool {
+	return e.Input != ""
+}
+
+func (e Event) Elapsed() time.Duration {
+	return time.Duration(e.Offset) * time.Millisecond
+}
+
+// SessionPath returns where the log for session id lives in dir.
+func SessionPath(dir string, id int) string {
+	return filepath.Join(dir, fmt.Sprintf("session-%06d.log", id))
+}
+
+// ReadSession loads a session log written by a Recorder.
+func ReadSession(path string) (Header, []Event, error) {
+	var header Header
+
+	f, err := os.Open(path)
+	if err != nil {
+		return header, nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return header, nil, err
+		}
+		return header, nil, fmt.Errorf("%s: empty session log", path)
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return header, nil, fmt.Errorf("%s: bad header: %w", path, err)
+	}
+
+	var events []Event
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return header, events, fmt.Errorf("%s: bad event on line %d: %w", path, len(events)+2, err)
+		}
+		events = append(events, event)
+	}
+
+	return header, events, scanner.Err()
+}
@@ -5,7 +5,6 @@ import (
 	"dmud/internal/components"
 	"dmud/internal/ecs"
 	"fmt"
-	"math/rand"
 	"time"
 )
 
@@ -16,16 +15,18 @@ type AISystem struct {
 }
 
 func NewAISystem() *AISystem {
-	return &AISystem{
-		lastUpdate: time.Now(),
-	}
+	return &AISystem{}
 }
 
 func (as *AISystem) Update(w *ecs.World, deltaTime float64) {
-	if time.Since(as.lastUpdate) < 2*time.Second {
+	now := w.Now()
+	if as.lastUpdate.IsZero() {
+		as.lastUpdate = now
+	}
+	if now.Sub(as.lastUpdate) < 2*time.Second {
 		return
 	}
-	as.lastUpdate = time.Now()
+	as.lastUpdate = now
 
 	npcEntities, err := w.FindEntitiesByComponentPredicate("NPC", func(i interface{}) bool {
 		return true
@@ -61,7 +62,7 @@ func (as *AISystem) processNPCBehavior(w *ecs.World, npcEntity ecs.Entity) {
 	switch npc.Behavior {
 	case components.BehaviorAggressive:
 		as.processAggressiveNPC(w, npcEntity, npc, combat)
-	case components.BehaviorFriendly, components.BehaviorMerchant:
+	case components.BehaviorFriendly, components.BehaviorMerchant, components.BehaviorSmith, components.BehaviorBanker:
 		as.processFriendlyNPC(w, npcEntity, npc)
 	case components.BehaviorGuard:
 		as.processGuardNPC(w, npcEntity, npc)
@@ -91,7 +92,7 @@ func (as *AISystem) processAggressiveNPC(w *ecs.World, npcEntity ecs.Entity, npc
 			// Pick a random player to attack
 			area.PlayersMutex.RLock()
 			if len(area.Players) > 0 {
-				target := area.Players[rand.Intn(len(area.Players))]
+				target := area.Players[w.Rand().Intn(len(area.Players))]
 				area.PlayersMutex.RUnlock()
 
 				// Find player entity
@@ -100,7 +101,7 @@ func (as *AISystem) processAggressiveNPC(w *ecs.World, npcEntity ecs.Entity, npc
 					return ok && p == target
 				})
 
-				if len(playerEntities) > 0 {
+				if len(playerEntities) > 0 && !isGhost(w, playerEntities[0].ID) {
 					// Start combat
 					newCombat := &components.Combat{
 						TargetID:  playerEntities[0].ID,
@@ -108,6 +109,7 @@ func (as *AISystem) processAggressiveNPC(w *ecs.World, npcEntity ecs.Entity, npc
 						MaxDamage: maxDamage,
 					}
 					w.AddComponent(&npcEntity, newCombat)
+					AddThreat(w, npcEntity.ID, playerEntities[0].ID, 0)
 
 					area.Broadcast(npc.Name + " attacks " + target.Name + "!")
 				}
@@ -118,7 +120,7 @@ func (as *AISystem) processAggressiveNPC(w *ecs.World, npcEntity ecs.Entity, npc
 	}
 }
 
-func (as *AISystem) attemptWander(_ *ecs.World, _ ecs.Entity, npc *components.NPC, combat *components.Combat) {
+func (as *AISystem) attemptWander(w *ecs.World, _ ecs.Entity, npc *components.NPC, combat *components.Combat) {
 	if combat != nil {
 		combat.RLock()
 		inCombat := combat.TargetID != ""
@@ -144,11 +146,11 @@ func (as *AISystem) attemptWander(_ *ecs.World, _ ecs.Entity, npc *components.NP
 		return
 	}
 
-	if time.Since(lastMovement) < wanderMinimumInterval {
+	if w.Now().Sub(lastMovement) < wanderMinimumInterval {
 		return
 	}
 
-	if rand.Float64() > 0.25 {
+	if w.Rand().Float64() > 0.25 {
 		return
 	}
 
@@ -157,7 +159,7 @@ func (as *AISystem) attemptWander(_ *ecs.World, _ ecs.Entity, npc *components.NP
 		return
 	}
 
-	chosenExit := exits[rand.Intn(len(exits))]
+	chosenExit := exits[w.Rand().Intn(len(exits))]
 	destination := chosenExit.Area
 	if destination == nil || destination == currentArea {
 		return
@@ -171,7 +173,7 @@ func (as *AISystem) attemptWander(_ *ecs.World, _ ecs.Entity, npc *components.NP
 		return
 	}
 	npc.Area = destination
-	npc.LastMovement = time.Now()
+	npc.LastMovement = w.Now()
 	npc.Unlock()
 
 	destination.Broadcast(name + " wanders in.")
@@ -196,15 +198,15 @@ func regionExits(area *components.Area) []components.Exit {
 	return exits
 }
 
-func (as *AISystem) processFriendlyNPC(_ *ecs.World, npcEntity ecs.Entity, npc *components.NPC) {
+func (as *AISystem) processFriendlyNPC(w *ecs.World, npcEntity ecs.Entity, npc *components.NPC) {
 	// Occasionally say something
-	if time.Since(npc.LastAction) > 30*time.Second && rand.Float64() < 0.3 {
-		dialogue := npc.GetRandomDialogue()
+	if w.Now().Sub(npc.LastAction) > 30*time.Second && w.Rand().Float64() < 0.3 {
+		dialogue := npc.GetRandomDialogue(w.Rand())
 		if dialogue != "" && npc.Area != nil {
 			npc.Area.Broadcast(npc.Name + " says: " + dialogue)
 			npc.Lock()
 			defer npc.Unlock()
-			npc.LastAction = time.Now()
+			npc.LastAction = w.Now()
 		}
 	}
 }
@@ -229,7 +231,7 @@ func (as *AISystem) guardBlessPlayers(w *ecs.World, npc *components.NPC) {
 		return
 	}
 
-	if time.Since(lastAction) < 10*time.Second {
+	if w.Now().Sub(lastAction) < 10*time.Second {
 		return
 	}
 
@@ -269,7 +271,7 @@ func (as *AISystem) guardBlessPlayers(w *ecs.World, npc *components.NPC) {
 			effect := components.StatusEffect{
 				Type:      components.StatusEffectGuardBlessing,
 				Name:      "Guard's Blessing",
-				AppliedAt: time.Now(),
+				AppliedAt: w.Now(),
 				Duration:  10 * time.Minute,
 				HPBonus:   500,
 				Applied:   false,
@@ -283,7 +285,7 @@ func (as *AISystem) guardBlessPlayers(w *ecs.World, npc *components.NPC) {
 				"You are under the Guard's protection.",
 				"Stay safe in these lands, friend.",
 			}
-			message := blessings[rand.Intn(len(blessings))]
+			message := blessings[w.Rand().Intn(len(blessings))]
 
 			area.Broadcast(fmt.Sprintf("%s says: \"%s\"", npc.Name, message))
 
@@ -308,7 +310,7 @@ func (as *AISystem) guardBlessPlayers(w *ecs.World, npc *components.NPC) {
 			player.BroadcastState(w.AsWorldLike(), playerEntity.ID)
 
 			npc.Lock()
-			npc.LastAction = time.Now()
+			npc.LastAction = w.Now()
 			npc.Unlock()
 
 			return
@@ -327,7 +329,7 @@ func (as *AISystem) guardBlessPlayers(w *ecs.World, npc *components.NPC) {
 				"The Guard's light mends your injuries.",
 				"Be whole again, friend.",
 			}
-			message := healings[rand.Intn(len(healings))]
+			message := healings[w.Rand().Intn(len(healings))]
 
 			area.Broadcast(fmt.Sprintf("%s says: \"%s\"", npc.Name, message))
 			player.Broadcast("The guard heals your wounds completely!")
@@ -336,7 +338,7 @@ func (as *AISystem) guardBlessPlayers(w *ecs.World, npc *components.NPC) {
 			player.BroadcastState(w.AsWorldLike(), playerEntity.ID)
 
 			npc.Lock()
-			npc.LastAction = time.Now()
+			npc.LastAction = w.Now()
 			npc.Unlock()
 
 			return
@@ -386,6 +388,7 @@ func (as *AISystem) guardIntervene(w *ecs.World, npcEntity ecs.Entity, npc *comp
 	combat.Lock()
 	combat.TargetID = aggressorID
 	combat.Unlock()
+	AddThreat(w, npcEntity.ID, aggressorID, 0)
 
 	area.Broadcast(fmt.Sprintf("%s shouts, \"Keep the peace!\" and attacks %s!", npc.Name, aggressorName))
 
@@ -500,13 +503,13 @@ func guardShouldIntervene(area *components.Area, guardID, targetID common.Entity
 
 func (as *AISystem) processPassiveNPC(w *ecs.World, npcEntity ecs.Entity, npc *components.NPC) {
 	// Passive NPCs might flee when attacked or just emote
-	if time.Since(npc.LastAction) > 45*time.Second && rand.Float64() < 0.2 {
-		dialogue := npc.GetRandomDialogue()
+	if w.Now().Sub(npc.LastAction) > 45*time.Second && w.Rand().Float64() < 0.2 {
+		dialogue := npc.GetRandomDialogue(w.Rand())
 		if dialogue != "" && npc.Area != nil {
 			npc.Area.Broadcast(npc.Name + " " + dialogue)
 			npc.Lock()
 			defer npc.Unlock()
-			npc.LastAction = time.Now()
+			npc.LastAction = w.Now()
 		}
 	}
 }
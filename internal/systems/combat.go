@@ -5,15 +5,33 @@ import (
 	"dmud/internal/components"
 	"dmud/internal/ecs"
 	"fmt"
-	"math/rand"
+	"math"
 	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
-type CombatSystem struct{}
+// CombatSystem resolves fights in rounds: each fighter swings once per
+// RoundLength, however often the world ticks.
+type CombatSystem struct {
+	RoundLength time.Duration
+}
+
+func NewCombatSystem(roundLength time.Duration) *CombatSystem {
+	if roundLength <= 0 {
+		roundLength = components.DefaultCombatRound
+	}
+	return &CombatSystem{RoundLength: roundLength}
+}
 
 func (cs *CombatSystem) Update(w *ecs.World, deltaTime float64) {
+	// NPCs go after whoever they hate most before anyone swings
+	retargetByThreat(w)
+
+	// Everyone struck down this round is dealt with together, so two
+	// fighters who kill each other both die
+	defer settleDeaths(w)
+
 	attackingEntities, err := findAttackingEntities(w)
 	if err != nil {
 		log.Error().Msgf("Error finding attacking entities: %v", err)
@@ -49,6 +67,11 @@ func (cs *CombatSystem) Update(w *ecs.World, deltaTime float64) {
 			continue
 		}
 
+		// The dead don't swing, even if nobody has noticed yet
+		if health, err := getHealthComponent(w, attackingEntity.ID); err == nil && isTargetDead(health) {
+			continue
+		}
+
 		// Get target info (could be player or NPC)
 		targetID := common.EntityID(combat.TargetID)
 		var targetName string
@@ -89,12 +112,48 @@ func (cs *CombatSystem) Update(w *ecs.World, deltaTime float64) {
 			continue
 		}
 
+		// Ghosts can't be fought; they are waiting to return to life
+		if targetPlayer != nil && isGhost(w, targetID) {
+			combat.TargetID = ""
+			continue
+		}
+
+		// Players only keep fighting each other while the PvP rules allow,
+		// such as until a duel is settled
+		if attackerPlayer != nil && targetPlayer != nil {
+			if refusal := PvPRefusal(w, attackerArea, attackingEntity.ID, targetID, targetName); refusal != "" {
+				combat.TargetID = ""
+				attackerPlayer.Broadcast(refusal)
+				continue
+			}
+		}
+
 		if isTargetDead(targetHealth) {
-			handleTargetDeath(w.AsWorldLike(), attackingEntity.ID, targetID,
-				attackerPlayer, targetPlayer, attackerNPC, targetNPC)
 			continue
 		}
 
+		// Stunned fighters lose their swing until the stun wears off
+		if isStunned(w, attackingEntity.ID) {
+			continue
+		}
+
+		// Wait for this fighter's next round before they swing again
+		now := w.Now()
+		combat.Lock()
+		if now.Before(combat.NextRound) {
+			combat.Unlock()
+			continue
+		}
+		combat.NextRound = now.Add(cs.RoundLength)
+		combat.Unlock()
+
+		// Nobody keeps resting through being hit
+		if targetPlayer != nil {
+			if posture, err := ecs.GetTypedComponent[*components.Posture](w, targetID, "Posture"); err == nil && posture.Stand() {
+				targetPlayer.Broadcast("You are attacked and scramble to your feet!")
+			}
+		}
+
 		performAttack(w, attackingEntity.ID, attackerPlayer, targetPlayer, attackerNPC, targetNPC,
 			attackerName, targetName, combat, targetHealth)
 
@@ -106,28 +165,26 @@ func (cs *CombatSystem) Update(w *ecs.World, deltaTime float64) {
 			broadcastStateToPlayer(w, targetID)
 		}
 
-		// Auto-retaliation: if target isn't already fighting back, make them attack the attacker
-		targetCombat, err := getCombatComponent(w, targetID)
-		if err != nil || targetCombat.TargetID == "" {
-			// Target doesn't have combat component or isn't attacking anyone
-			// Create or update combat component to attack back
-			var minDamage, maxDamage int
-
-			if targetPlayer != nil {
-				// Default player damage if not specified
-				minDamage = 10
-				maxDamage = 50
-			} else if targetNPC != nil {
-				// Use NPC's damage from template
-				if template, ok := components.NPCTemplates[targetNPC.TemplateID]; ok {
-					minDamage = template.MinDamage
-					maxDamage = template.MaxDamage
-				} else {
-					minDamage = 5
-					maxDamage = 15
-				}
+		// Wimpy players run for it once a hit leaves them low
+		if targetPlayer != nil && shouldFlee(w, targetID, targetHealth) {
+			targetPlayer.Broadcast("Your wounds send you into a panic!")
+			if Flee(w, targetID, targetPlayer) {
+				continue
 			}
+		}
 
+		// Auto-retaliation: if target isn't already fighting back, make them
+		// attack the attacker. A fight already under way is left alone; NPCs
+		// pick between their attackers by threat.
+		targetCombat, err := getCombatComponent(w, targetID)
+		if err == nil {
+			targetCombat.Lock()
+			if targetCombat.TargetID == "" {
+				targetCombat.TargetID = attackingEntity.ID
+			}
+			targetCombat.Unlock()
+		} else {
+			minDamage, maxDamage := combatDamageRange(w, targetID, targetPlayer, targetNPC)
 			retaliationCombat := &components.Combat{
 				TargetID:  attackingEntity.ID,
 				MinDamage: minDamage,
@@ -148,6 +205,21 @@ func (cs *CombatSystem) Update(w *ecs.World, deltaTime float64) {
 	}
 }
 
+// combatDamageRange is how hard an entity hits: players by their equipped
+// weapon and Strength, NPCs by their template.
+func combatDamageRange(w *ecs.World, entityID common.EntityID, player *components.Player, npc *components.NPC) (int, int) {
+	if player != nil {
+		equipment, _ := ecs.GetTypedComponent[*components.Equipment](w, entityID, "Equipment")
+		return components.PlayerDamageRange(equipment, getAttributesComponent(w, entityID))
+	}
+	if npc != nil {
+		if template, ok := components.NPCTemplates[npc.TemplateID]; ok {
+			return template.MinDamage, template.MaxDamage
+		}
+	}
+	return 5, 15
+}
+
 func findAttackingEntities(w *ecs.World) ([]ecs.Entity, error) {
 	return w.FindEntitiesByComponentPredicate("Combat", func(i interface{}) bool {
 		c := i.(*components.Combat)
@@ -171,6 +243,21 @@ func getHealthComponent(w *ecs.World, entityID common.EntityID) (*components.Hea
 	return ecs.GetTypedComponent[*components.Health](w, entityID, "Health")
 }
 
+func isStunned(w *ecs.World, entityID common.EntityID) bool {
+	statusEffects, err := ecs.GetTypedComponent[*components.StatusEffects](w, entityID, "StatusEffects")
+	return err == nil && statusEffects.HasEffect(components.StatusEffectStunned)
+}
+
+// getAttributesComponent returns an entity's attributes, or nil for NPCs,
+// which fight with average stats.
+func getAttributesComponent(w *ecs.World, entityID common.EntityID) *components.Attributes {
+	attributes, err := ecs.GetTypedComponent[*components.Attributes](w, entityID, "Attributes")
+	if err != nil {
+		return nil
+	}
+	return attributes
+}
+
 func isTargetDead(health *components.Health) bool {
 	return health.Current <= 0
 }
@@ -182,7 +269,12 @@ func handleTargetDeath(w components.WorldLike, attackerID common.EntityID, targe
 	if combatComp, err := w.GetComponent(attackerID, "Combat"); err == nil {
 		combat := combatComp.(*components.Combat)
 		combat.Lock()
-		if len(combat.TargetQueue) > 0 {
+		if combat.TargetID != targetID {
+			// Killed by something other than the fight in hand, such as an
+			// area spell, so just drop them from the queue
+			combat.TargetQueue = removeTarget(combat.TargetQueue, targetID)
+			combat.Unlock()
+		} else if len(combat.TargetQueue) > 0 {
 			// Switch to next target in queue
 			combat.TargetID = combat.TargetQueue[0]
 			combat.TargetQueue = combat.TargetQueue[1:]
@@ -218,21 +310,17 @@ func handleTargetDeath(w components.WorldLike, attackerID common.EntityID, targe
 			targetPlayer.Area.Broadcast(fmt.Sprintf("%s has been slain by %s!", targetPlayer.Name, attackerNPC.Name))
 		}
 
-		// Create player corpse with their inventory
+		// The corpse takes everything the player was carrying, leaving them
+		// to come back for it
 		var corpseInventory *components.Inventory
 		if invComp, err := w.GetComponent(targetID, "Inventory"); err == nil {
-			corpseInventory = invComp.(*components.Inventory)
+			inventory := invComp.(*components.Inventory)
+			corpseInventory = components.NewInventory(inventory.MaxSlots)
+			corpseInventory.Items = inventory.TakeAll()
 		}
-		spawnCorpse(w, targetPlayer.Name, targetID, true, targetPlayer.Area, corpseInventory)
+		spawnCorpse(w, targetPlayer.Name, targetPlayer.Account, true, targetPlayer.Area, corpseInventory)
 
-		// TODO: Handle respawn
-		// For now, restore health
-		if healthComp, err := w.GetComponent(targetID, "Health"); err == nil {
-			health := healthComp.(*components.Health)
-			health.Current = health.Max
-			// health.UpdateStatus()
-			targetPlayer.Broadcast("You have been revived with full health.")
-		}
+		becomeGhost(w, targetID, targetPlayer)
 	} else if targetNPC != nil {
 		// NPC died
 		if targetNPC.Area != nil {
@@ -250,6 +338,7 @@ func handleTargetDeath(w components.WorldLike, attackerID common.EntityID, targe
 
 			if expComp, err := w.GetComponent(attackerID, "Experience"); err == nil {
 				experience := expComp.(*components.Experience)
+				oldLevel := experience.GetLevel()
 				leveledUp, newLevel := experience.AddXP(xpReward)
 
 				attackerPlayer.Broadcast(fmt.Sprintf("You gained %d experience!", xpReward))
@@ -257,18 +346,49 @@ func handleTargetDeath(w components.WorldLike, attackerID common.EntityID, targe
 				if leveledUp {
 					attackerPlayer.Broadcast(fmt.Sprintf("You have reached level %d!", newLevel))
 
+					// Every level earns training points to spend on attributes
+					if attrComp, err := w.GetComponent(attackerID, "Attributes"); err == nil {
+						points := (newLevel - oldLevel) * components.TrainingPointsPerLevel
+						attrComp.(*components.Attributes).AddPoints(points)
+						attackerPlayer.Broadcast(fmt.Sprintf("You gain %d training points. Type 'train' to spend them.", points))
+					}
+
+					// New levels can unlock class abilities
+					LearnAbilities(w, attackerID, attackerPlayer, newLevel)
+
 					// Scale up player health on level up and heal to full
 					if healthComp, err := w.GetComponent(attackerID, "Health"); err == nil {
 						health := healthComp.(*components.Health)
+						var equipment *components.Equipment
+						if equipComp, err := w.GetComponent(attackerID, "Equipment"); err == nil {
+							equipment = equipComp.(*components.Equipment)
+						}
+						var attributes *components.Attributes
+						if attrComp, err := w.GetComponent(attackerID, "Attributes"); err == nil {
+							attributes = attrComp.(*components.Attributes)
+						}
+
 						health.Lock()
 						oldMax := health.Max
-						newMax := int(float64(100) * components.GetLevelScaling(newLevel))
+						newMax := components.PlayerMaxHP(newLevel, attributes, equipment)
 						hpGain := newMax - oldMax
 						health.Max = newMax
 						health.Current = newMax
 						health.Unlock()
 						attackerPlayer.Broadcast(fmt.Sprintf("Your maximum health increased by %d and you are fully healed!", hpGain))
 					}
+
+					// Mana and stamina grow with level too, and refill
+					var attributes *components.Attributes
+					if attrComp, err := w.GetComponent(attackerID, "Attributes"); err == nil {
+						attributes = attrComp.(*components.Attributes)
+					}
+					if manaComp, err := w.GetComponent(attackerID, "Mana"); err == nil {
+						manaComp.(*components.Mana).SetMax(components.PlayerMaxMana(newLevel, attributes), true)
+					}
+					if staminaComp, err := w.GetComponent(attackerID, "Stamina"); err == nil {
+						staminaComp.(*components.Stamina).SetMax(components.PlayerMaxStamina(newLevel, attributes), true)
+					}
 				}
 
 				// Broadcast state update to show XP and possibly level change
@@ -281,15 +401,126 @@ func handleTargetDeath(w components.WorldLike, attackerID common.EntityID, targe
 		if invComp, err := w.GetComponent(targetID, "Inventory"); err == nil {
 			corpseInventory = invComp.(*components.Inventory)
 		}
-		spawnCorpse(w, targetNPC.Name, targetID, false, targetNPC.Area, corpseInventory)
+		spawnCorpse(w, targetNPC.Name, "", false, targetNPC.Area, corpseInventory)
 
 		// Remove NPC from world (spawn system will respawn it)
 		w.RemoveEntity(targetID)
 	}
 }
 
+// LearnAbilities teaches a player every ability their class has unlocked by
+// level, as when they level up or finish creating their character.
+func LearnAbilities(w components.WorldLike, entityID common.EntityID, player *components.Player, level int) {
+	skillsComp, err := w.GetComponent(entityID, "Skills")
+	if err != nil {
+		return
+	}
+	charComp, err := w.GetComponent(entityID, "Character")
+	if err != nil {
+		return
+	}
+
+	class := charComp.(*components.Character).Class
+	for _, ability := range skillsComp.(*components.Skills).LearnForLevel(class, level) {
+		player.Broadcast(fmt.Sprintf("You have learned %s! Type '%s' to use it.", ability.Name, ability.ID))
+	}
+}
+
+// removeTarget returns queue without id.
+func removeTarget(queue []common.EntityID, id common.EntityID) []common.EntityID {
+	kept := queue[:0]
+	for _, queued := range queue {
+		if queued != id {
+			kept = append(kept, queued)
+		}
+	}
+	return kept
+}
+
+// settleDeaths resolves the death of every player or NPC left without
+// health who isn't already a ghost, crediting whoever was fighting them.
+// Killers are picked before any death is resolved, since resolving one can
+// remove an NPC that also landed a killing blow.
+func settleDeaths(w *ecs.World) {
+	victims, err := w.FindEntitiesByComponentPredicate("Health", func(i interface{}) bool {
+		health := i.(*components.Health)
+		health.RLock()
+		defer health.RUnlock()
+		return isTargetDead(health)
+	})
+	if err != nil || len(victims) == 0 {
+		return
+	}
+
+	type death struct {
+		killerID, victimID         common.EntityID
+		killerPlayer, victimPlayer *components.Player
+		killerNPC, victimNPC       *components.NPC
+	}
+	var deaths []death
+	for _, victim := range victims {
+		if isGhost(w, victim.ID) {
+			continue
+		}
+		d := death{victimID: victim.ID, killerID: findKiller(w, victim.ID)}
+		d.victimPlayer, _ = getPlayerComponent(w, victim.ID)
+		d.victimNPC, _ = getNPCComponent(w, victim.ID)
+		if d.victimPlayer == nil && d.victimNPC == nil {
+			continue
+		}
+		if d.killerID != "" {
+			d.killerPlayer, _ = getPlayerComponent(w, d.killerID)
+			d.killerNPC, _ = getNPCComponent(w, d.killerID)
+		}
+		deaths = append(deaths, d)
+	}
+
+	for _, d := range deaths {
+		handleTargetDeath(w.AsWorldLike(), d.killerID, d.victimID,
+			d.killerPlayer, d.victimPlayer, d.killerNPC, d.victimNPC)
+	}
+}
+
+// findKiller picks who gets the credit for victimID's death: someone
+// fighting them, preferring a player.
+func findKiller(w *ecs.World, victimID common.EntityID) common.EntityID {
+	fighters, _ := findAttackingEntities(w)
+
+	var killer common.EntityID
+	for _, fighter := range fighters {
+		combat, err := getCombatComponent(w, fighter.ID)
+		if err != nil {
+			continue
+		}
+		combat.RLock()
+		targeting := combat.TargetID == victimID
+		combat.RUnlock()
+		if !targeting || fighter.ID == victimID {
+			continue
+		}
+		if player, _ := getPlayerComponent(w, fighter.ID); player != nil {
+			return fighter.ID
+		}
+		if killer == "" {
+			killer = fighter.ID
+		}
+	}
+	return killer
+}
+
+// ResolveDeath handles a target killed outside the combat rounds, as by an
+// ability: the killer gets the credit and the victim leaves a corpse.
+func ResolveDeath(w *ecs.World, killerID, victimID common.EntityID) {
+	killerPlayer, _ := getPlayerComponent(w, killerID)
+	killerNPC, _ := getNPCComponent(w, killerID)
+	victimPlayer, _ := getPlayerComponent(w, victimID)
+	victimNPC, _ := getNPCComponent(w, victimID)
+
+	handleTargetDeath(w.AsWorldLike(), killerID, victimID, killerPlayer, victimPlayer, killerNPC, victimNPC)
+}
+
 // spawnCorpse creates a corpse entity at the location of death
-func spawnCorpse(w components.WorldLike, victimName string, victimID common.EntityID, wasPlayer bool, area *components.Area, inventory *components.Inventory) {
+func spawnCorpse(w components.WorldLike, victimName, owner string, wasPlayer bool, area *components.Area, inventory *components.Inventory) {
 	if area == nil {
 		return
 	}
@@ -298,7 +529,7 @@ func spawnCorpse(w components.WorldLike, victimName string, victimID common.Enti
 	corpseEntity := w.CreateEntity()
 
 	// Create and add corpse component with inventory
-	corpse := components.NewCorpse(victimName, victimID, wasPlayer, area, inventory)
+	corpse := components.NewCorpse(victimName, owner, wasPlayer, area, inventory, w.Now())
 	w.AddComponentToEntity(corpseEntity, corpse)
 
 	log.Debug().Msgf("Spawned corpse of %s (entity: %s) at area (%d,%d,%d)",
@@ -308,10 +539,29 @@ func spawnCorpse(w components.WorldLike, victimName string, victimID common.Enti
 func performAttack(w *ecs.World, attackerID common.EntityID, attackerPlayer, targetPlayer *components.Player, attackerNPC, targetNPC *components.NPC,
 	attackerName, targetName string, combat *components.Combat, targetHealth *components.Health) {
 
-	s := rand.NewSource(time.Now().UnixNano())
-	r := rand.New(s)
+	targetID := common.EntityID(combat.TargetID)
+	outcome := components.ResolveAttack(w.Rand(), combatRatings(w, attackerID, attackerNPC), combatRatings(w, targetID, targetNPC))
 
-	baseDamage := r.Intn(combat.MaxDamage-combat.MinDamage+1) + combat.MinDamage
+	if !outcome.Landed() {
+		announceAvoided(attackerPlayer, targetPlayer, attackerNPC, attackerName, targetName, outcome)
+
+		// A blocked blow still takes its toll on the shield
+		if outcome == components.OutcomeBlock && targetPlayer != nil {
+			if equipment, err := ecs.GetTypedComponent[*components.Equipment](w, targetID, "Equipment"); err == nil {
+				if shield := equipment.Get(components.SlotOffHand); shield != nil {
+					wearItem(w, targetID, targetPlayer, equipment, shield)
+				}
+			}
+		}
+		return
+	}
+
+	// Modifiers can pull the top of the range below the bottom
+	minDamage, maxDamage := combat.MinDamage, combat.MaxDamage
+	if maxDamage < minDamage {
+		maxDamage = minDamage
+	}
+	baseDamage := w.Rand().Intn(maxDamage-minDamage+1) + minDamage
 	damage := baseDamage
 
 	if attackerPlayer != nil {
@@ -323,20 +573,217 @@ func performAttack(w *ecs.World, attackerID common.EntityID, attackerPlayer, tar
 		}
 	}
 
+	if outcome == components.OutcomeCritical {
+		damage *= components.CriticalMultiplier
+	}
+
+	if statusEffects, err := ecs.GetTypedComponent[*components.StatusEffects](w, attackerID, "StatusEffects"); err == nil {
+		damage = int(float64(damage) * statusEffects.DamageMultiplier())
+		if damage < 1 {
+			damage = 1
+		}
+	}
+
+	// Some foes shrug off the kind of damage the attacker deals, and others
+	// are weak to it
+	damageType := attackDamageType(w, attackerID, attackerNPC)
+	resistance := components.ResistanceMultiplier(w.AsWorldLike(), targetID, damageType)
+	if resistance != 1 {
+		damage = int(math.Round(float64(damage) * resistance))
+		if damage < 1 {
+			damage = 1
+		}
+	}
+
+	// Armour soaks up part of every hit, but something always gets through
+	absorbed := 0
+	if equipment, err := ecs.GetTypedComponent[*components.Equipment](w, targetID, "Equipment"); err == nil {
+		absorbed = equipment.TotalModifiers().Armor
+		if absorbed >= damage {
+			absorbed = damage - 1
+		}
+		if absorbed < 0 {
+			absorbed = 0
+		}
+		damage -= absorbed
+	}
+
 	targetHealth.Lock()
 	targetHealth.Current -= damage
 	targetHealth.Unlock()
 
-	// Send appropriate messages based on entity types
+	if targetNPC != nil {
+		AddThreat(w, targetID, attackerID, damage)
+	}
+
+	announceHit(attackerPlayer, targetPlayer, attackerNPC, attackerName, targetName, outcome, damage, absorbed,
+		components.DescribeResistance(damageType, resistance))
+	ResolveDuelHit(w, attackerID, targetID)
+
+	log.Trace().Msg(fmt.Sprintf("%s attacked %s for %d damage!", attackerName, targetName, damage))
+
+	// Every hit wears down the attacker's weapon, and every blow armour
+	// soaks up wears down a piece of the defender's armour
+	if attackerPlayer != nil {
+		if equipment, err := ecs.GetTypedComponent[*components.Equipment](w, attackerID, "Equipment"); err == nil {
+			if weapon := equipment.Get(components.SlotMainHand); weapon != nil {
+				wearItem(w, attackerID, attackerPlayer, equipment, weapon)
+			}
+		}
+	}
+	if targetPlayer != nil && absorbed > 0 {
+		if equipment, err := ecs.GetTypedComponent[*components.Equipment](w, targetID, "Equipment"); err == nil {
+			if armor := equipment.WearableArmor(); len(armor) > 0 {
+				wearItem(w, targetID, targetPlayer, equipment, armor[w.Rand().Intn(len(armor))])
+			}
+		}
+	}
+}
+
+// attackDamageType is the kind of damage an entity's attacks do: NPCs from
+// their template, players from their weapon.
+func attackDamageType(w *ecs.World, entityID common.EntityID, npc *components.NPC) components.DamageType {
+	if npc != nil {
+		if template, ok := components.NPCTemplates[npc.TemplateID]; ok {
+			return template.DamageType
+		}
+		return components.UnarmedDamageType
+	}
+	equipment, _ := ecs.GetTypedComponent[*components.Equipment](w, entityID, "Equipment")
+	return components.WeaponDamageType(equipment)
+}
+
+// combatRatings returns how well an entity fights: NPCs from their
+// template, players from their level and gear.
+func combatRatings(w *ecs.World, entityID common.EntityID, npc *components.NPC) components.CombatRatings {
+	if npc != nil {
+		return components.NPCCombatRatings(npc.TemplateID)
+	}
+
+	level := 1
+	if experience, err := ecs.GetTypedComponent[*components.Experience](w, entityID, "Experience"); err == nil {
+		level = experience.GetLevel()
+	}
+	equipment, _ := ecs.GetTypedComponent[*components.Equipment](w, entityID, "Equipment")
+	return components.PlayerCombatRatings(level, getAttributesComponent(w, entityID), equipment)
+}
+
+// combatArea is where a fight is taking place, for telling bystanders.
+func combatArea(attackerPlayer *components.Player, attackerNPC *components.NPC) *components.Area {
 	if attackerPlayer != nil {
-		attackerPlayer.Broadcast(fmt.Sprintf("You attacked %s for %d damage!", targetName, damage))
+		return attackerPlayer.Area
+	}
+	if attackerNPC != nil {
+		return attackerNPC.Area
+	}
+	return nil
+}
+
+// announceAvoided tells the fighters and anyone watching about a swing
+// that did no damage.
+func announceAvoided(attackerPlayer, targetPlayer *components.Player, attackerNPC *components.NPC,
+	attackerName, targetName string, outcome components.AttackOutcome) {
+
+	var attackerMsg, targetMsg, areaMsg string
+	switch outcome {
+	case components.OutcomeMiss:
+		attackerMsg = fmt.Sprintf("You miss %s.", targetName)
+		targetMsg = fmt.Sprintf("%s misses you.", attackerName)
+		areaMsg = fmt.Sprintf("%s misses %s.", attackerName, targetName)
+	case components.OutcomeDodge:
+		attackerMsg = fmt.Sprintf("%s dodges your attack.", targetName)
+		targetMsg = fmt.Sprintf("You dodge %s's attack.", attackerName)
+		areaMsg = fmt.Sprintf("%s dodges %s's attack.", targetName, attackerName)
+	case components.OutcomeParry:
+		attackerMsg = fmt.Sprintf("%s parries your attack.", targetName)
+		targetMsg = fmt.Sprintf("You parry %s's attack.", attackerName)
+		areaMsg = fmt.Sprintf("%s parries %s's attack.", targetName, attackerName)
+	case components.OutcomeBlock:
+		attackerMsg = fmt.Sprintf("%s blocks your attack.", targetName)
+		targetMsg = fmt.Sprintf("You block %s's attack with your shield.", attackerName)
+		areaMsg = fmt.Sprintf("%s blocks %s's attack.", targetName, attackerName)
+	default:
+		return
+	}
+
+	announceCombat(attackerPlayer, targetPlayer, attackerNPC, attackerMsg, targetMsg, areaMsg)
+}
+
+// announceHit describes a blow that landed, picking a verb for how hard it
+// struck. resistance notes how the target's resistances scaled it, if they
+// did.
+func announceHit(attackerPlayer, targetPlayer *components.Player, attackerNPC *components.NPC,
+	attackerName, targetName string, outcome components.AttackOutcome, damage, absorbed int, resistance string) {
+
+	first, third := components.DamageVerb(damage)
+
+	prefix := ""
+	if outcome == components.OutcomeCritical {
+		prefix = "Critical hit! "
+	}
+
+	attackerMsg := fmt.Sprintf("%sYou %s %s. %s", prefix, first, targetName, DamageDetails(damage, resistance))
+	absorbedNote := ""
+	if absorbed > 0 {
+		absorbedNote = fmt.Sprintf("%d absorbed by armor", absorbed)
 	}
+	targetMsg := fmt.Sprintf("%s%s %s you. %s", prefix, attackerName, third, DamageDetails(damage, absorbedNote, resistance))
+	areaMsg := fmt.Sprintf("%s %s %s.", attackerName, third, targetName)
+
+	announceCombat(attackerPlayer, targetPlayer, attackerNPC, attackerMsg, targetMsg, areaMsg)
+}
 
+// DamageDetails reads like "(12 damage)", followed by any notes that
+// aren't empty, like "(12 damage, pierce resisted x0.5)".
+func DamageDetails(damage int, notes ...string) string {
+	details := fmt.Sprintf("%d damage", damage)
+	for _, note := range notes {
+		if note != "" {
+			details += ", " + note
+		}
+	}
+	return "(" + details + ")"
+}
+
+func announceCombat(attackerPlayer, targetPlayer *components.Player, attackerNPC *components.NPC,
+	attackerMsg, targetMsg, areaMsg string) {
+
+	if attackerPlayer != nil {
+		attackerPlayer.Broadcast(attackerMsg)
+	}
 	if targetPlayer != nil {
-		targetPlayer.Broadcast(fmt.Sprintf("%s attacked you for %d damage!", attackerName, damage))
+		targetPlayer.Broadcast(targetMsg)
+	}
+	if area := combatArea(attackerPlayer, attackerNPC); area != nil {
+		area.Broadcast(areaMsg, attackerPlayer, targetPlayer)
 	}
+}
 
-	log.Trace().Msg(fmt.Sprintf("%s attacked %s for %d damage!", attackerName, targetName, damage))
+// wearItem takes a point of durability off a player's equipped item. When it
+// breaks, the bonuses it gave stop applying straight away.
+func wearItem(w *ecs.World, entityID common.EntityID, player *components.Player, equipment *components.Equipment, item *components.Item) {
+	mods := item.ActiveModifiers()
+	if !item.Wear(1) {
+		return
+	}
+
+	player.Broadcast(fmt.Sprintf("Your %s breaks!", item.Name))
+
+	if combat, err := getCombatComponent(w, entityID); err == nil {
+		minDamage, maxDamage := components.PlayerDamageRange(equipment, getAttributesComponent(w, entityID))
+		combat.Lock()
+		combat.MinDamage = minDamage
+		combat.MaxDamage = maxDamage
+		combat.Unlock()
+	}
+
+	if mods.MaxHP != 0 {
+		if health, err := getHealthComponent(w, entityID); err == nil {
+			health.Lock()
+			health.AdjustMax(-mods.MaxHP)
+			health.Unlock()
+		}
+	}
 }
 
 func broadcastStateToPlayer(w *ecs.World, entityID common.EntityID) {
@@ -29,7 +29,7 @@ func (cs *CorpseSystem) Update(w *ecs.World, deltaTime float64) {
 		}
 
 		// Check if corpse has decayed
-		if corpse.IsDecayed() {
+		if corpse.IsDecayed(w.Now()) {
 			corpse.RLock()
 			area := corpse.Area
 			victimName := corpse.VictimName
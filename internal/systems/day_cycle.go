@@ -16,9 +16,8 @@ type DayCycleSystem struct {
 
 func NewDayCycleSystem(broadcast func(string)) *DayCycleSystem {
 	return &DayCycleSystem{
-		lastUpdate: time.Now(),
-		dayCycle:   components.NewDayCycle(),
-		broadcast:  broadcast,
+		dayCycle:  components.NewDayCycle(),
+		broadcast: broadcast,
 	}
 }
 
@@ -27,7 +26,10 @@ func (dcs *DayCycleSystem) GetDayCycle() *components.DayCycle {
 }
 
 func (dcs *DayCycleSystem) Update(w *ecs.World, deltaTime float64) {
-	now := time.Now()
+	now := w.Now()
+	if dcs.lastUpdate.IsZero() {
+		dcs.lastUpdate = now
+	}
 	elapsed := now.Sub(dcs.lastUpdate)
 	dcs.lastUpdate = now
 
@@ -41,12 +43,12 @@ func (dcs *DayCycleSystem) Update(w *ecs.World, deltaTime float64) {
 	if dcs.dayCycle.ElapsedTime >= periodDuration {
 		dcs.dayCycle.ElapsedTime -= periodDuration
 		oldTime := dcs.dayCycle.CurrentTime
-		dcs.advanceTime()
+		dcs.advanceTime(now)
 		dcs.announceTransition(oldTime, dcs.dayCycle.CurrentTime)
 	}
 }
 
-func (dcs *DayCycleSystem) advanceTime() {
+func (dcs *DayCycleSystem) advanceTime(now time.Time) {
 	switch dcs.dayCycle.CurrentTime {
 	case components.Dawn:
 		dcs.dayCycle.CurrentTime = components.Day
@@ -57,7 +59,7 @@ func (dcs *DayCycleSystem) advanceTime() {
 	case components.Night:
 		dcs.dayCycle.CurrentTime = components.Dawn
 		dcs.dayCycle.DayNumber++
-		dcs.dayCycle.CycleStart = time.Now()
+		dcs.dayCycle.CycleStart = now
 	}
 }
 
@@ -0,0 +1,187 @@
+package systems
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultBindArea is where players with no bind point return to life.
+const DefaultBindArea = "1"
+
+// RespawnSystem brings ghosts back to life at their bind point once they
+// have been dead for GhostDuration.
+type RespawnSystem struct {
+	GhostDuration time.Duration
+}
+
+func NewRespawnSystem(ghostDuration time.Duration) *RespawnSystem {
+	if ghostDuration <= 0 {
+		ghostDuration = components.DefaultGhostDuration
+	}
+	return &RespawnSystem{GhostDuration: ghostDuration}
+}
+
+func (rs *RespawnSystem) Update(w *ecs.World, deltaTime float64) {
+	ghosts, err := w.FindEntitiesByComponentPredicate("Ghost", func(i interface{}) bool {
+		return true
+	})
+	if err != nil {
+		return
+	}
+
+	for _, entity := range ghosts {
+		ghost, err := ecs.GetTypedComponent[*components.Ghost](w, entity.ID, "Ghost")
+		if err != nil || ghost.Since(w.Now()) < rs.GhostDuration {
+			continue
+		}
+		Respawn(w, entity.ID)
+	}
+}
+
+func isGhost(w *ecs.World, entityID common.EntityID) bool {
+	_, err := w.GetComponent(entityID, "Ghost")
+	return err == nil
+}
+
+// becomeGhost leaves a freshly killed player as a ghost: out of every fight
+// and unable to act until they return to life.
+func becomeGhost(w components.WorldLike, entityID common.EntityID, player *components.Player) {
+	// Nobody carries on fighting a ghost
+	fighters, _ := w.FindEntitiesByComponentPredicate("Combat", func(i interface{}) bool {
+		return true
+	})
+	for _, fighter := range fighters {
+		combatComp, err := w.GetComponent(fighter.GetID(), "Combat")
+		if err != nil {
+			continue
+		}
+		combat := combatComp.(*components.Combat)
+		combat.Lock()
+		if fighter.GetID() == entityID {
+			combat.TargetID = ""
+			combat.TargetQueue = nil
+		} else {
+			combat.TargetQueue = removeTarget(combat.TargetQueue, entityID)
+			if combat.TargetID == entityID {
+				combat.TargetID = ""
+				if len(combat.TargetQueue) > 0 {
+					combat.TargetID = combat.TargetQueue[0]
+					combat.TargetQueue = combat.TargetQueue[1:]
+				}
+			}
+		}
+		combat.Unlock()
+	}
+
+	if healthComp, err := w.GetComponent(entityID, "Health"); err == nil {
+		health := healthComp.(*components.Health)
+		health.Lock()
+		health.Current = 0
+		health.Status = components.Dead
+		health.Unlock()
+	}
+
+	entities, _ := w.FindEntitiesByComponentPredicate("Player", func(i interface{}) bool {
+		return i == player
+	})
+	if len(entities) == 0 {
+		return
+	}
+	w.AddComponentToEntity(entities[0], components.NewGhost(w.Now()))
+
+	player.Broadcast("You rise from your body as a ghost. Type 'release' to return to life at your bind point.")
+	if player.Area != nil {
+		player.Area.Broadcast(fmt.Sprintf("The ghost of %s rises from their corpse.", player.Name), player)
+	}
+	player.BroadcastState(w, entityID)
+}
+
+// Respawn brings a ghost back to life at their bind point, weakened and
+// poorer in experience for having died.
+func Respawn(w *ecs.World, entityID common.EntityID) {
+	player, err := getPlayerComponent(w, entityID)
+	if err != nil {
+		return
+	}
+	w.RemoveComponent(entityID, "Ghost")
+
+	area := bindArea(w, entityID)
+	if area != nil && area != player.Area {
+		if player.Area != nil {
+			player.Area.RemovePlayer(player)
+		}
+		player.Area = area
+		area.AddPlayer(player)
+	}
+
+	// Players come back with half their health, mana and stamina
+	if health, err := getHealthComponent(w, entityID); err == nil {
+		health.Lock()
+		health.Current = health.Max / 2
+		if health.Current < 1 {
+			health.Current = 1
+		}
+		health.Status = components.Injured
+		health.Unlock()
+	}
+	if mana, err := ecs.GetTypedComponent[*components.Mana](w, entityID, "Mana"); err == nil {
+		mana.Lock()
+		mana.Current = mana.Max / 2
+		mana.Unlock()
+	}
+	if stamina, err := ecs.GetTypedComponent[*components.Stamina](w, entityID, "Stamina"); err == nil {
+		stamina.Lock()
+		stamina.Current = stamina.Max / 2
+		stamina.Unlock()
+	}
+
+	player.Broadcast("You return to life, weakened by your death.")
+
+	if experience, err := ecs.GetTypedComponent[*components.Experience](w, entityID, "Experience"); err == nil {
+		penalty := int(float64(experience.GetRequiredXP()) * components.DeathXPPenalty)
+		if lost := experience.LoseXP(penalty); lost > 0 {
+			player.Broadcast(fmt.Sprintf("You lose %d experience.", lost))
+		}
+	}
+
+	statusEffects, err := ecs.GetTypedComponent[*components.StatusEffects](w, entityID, "StatusEffects")
+	if err != nil {
+		entity, err := w.FindEntity(entityID)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error finding entity %s to weaken", entityID)
+		} else {
+			statusEffects = components.NewStatusEffects()
+			w.AddComponent(&entity, statusEffects)
+		}
+	}
+	if statusEffects != nil {
+		statusEffects.AddEffect(components.StatusEffect{
+			Type:      components.StatusEffectWeakened,
+			Name:      "weakness",
+			AppliedAt: w.Now(),
+			Duration:  components.WeakenedDuration,
+		})
+	}
+
+	player.Look(w.AsWorldLike())
+	player.BroadcastState(w.AsWorldLike(), entityID)
+}
+
+// bindArea is the area a player returns to life in, falling back to
+// DefaultBindArea if their bind point is missing.
+func bindArea(w *ecs.World, entityID common.EntityID) *components.Area {
+	areaID := DefaultBindArea
+	if bind, err := ecs.GetTypedComponent[*components.BindPoint](w, entityID, "BindPoint"); err == nil && bind.Get() != "" {
+		areaID = bind.Get()
+	}
+	area, err := ecs.GetTypedComponent[*components.Area](w, common.EntityID(areaID), "Area")
+	if err != nil {
+		area, _ = ecs.GetTypedComponent[*components.Area](w, common.EntityID(DefaultBindArea), "Area")
+	}
+	return area
+}
@@ -0,0 +1,76 @@
+package systems
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"fmt"
+)
+
+// Flee tries to get a player out of a fight through a random exit.
+// Escaping costs some of their XP, and stunned players can't run at all.
+// It returns true if the player got away.
+func Flee(w *ecs.World, entityID common.EntityID, player *components.Player) bool {
+	if isStunned(w, entityID) {
+		player.Broadcast("You are stunned and can't flee!")
+		return false
+	}
+
+	area := player.Area
+	if area == nil || len(area.Exits) == 0 {
+		player.Broadcast("There's nowhere to run!")
+		return false
+	}
+
+	if w.Rand().Intn(100) >= components.FleeChance(getAttributesComponent(w, entityID)) {
+		player.Broadcast("You try to flee, but can't get away!")
+		area.Broadcast(fmt.Sprintf("%s tries to flee, but can't get away!", player.Name), player)
+		return false
+	}
+
+	exit := area.Exits[w.Rand().Intn(len(area.Exits))]
+	if exit.Area == nil {
+		player.Broadcast("You try to flee, but can't get away!")
+		return false
+	}
+
+	// Anyone still fighting the player finds they've gone next round
+	if combat, err := getCombatComponent(w, entityID); err == nil {
+		combat.Lock()
+		combat.TargetID = ""
+		combat.TargetQueue = nil
+		combat.Unlock()
+	}
+
+	area.Broadcast(fmt.Sprintf("%s flees %s!", player.Name, exit.Direction), player)
+	area.RemovePlayer(player)
+	player.Area = exit.Area
+	player.Area.AddPlayer(player)
+	player.Broadcast(fmt.Sprintf("You flee %s!", exit.Direction))
+	forfeitDuel(w, entityID, player)
+
+	if experience, err := ecs.GetTypedComponent[*components.Experience](w, entityID, "Experience"); err == nil {
+		penalty := int(float64(experience.GetRequiredXP()) * components.FleeXPPenalty)
+		if lost := experience.LoseXP(penalty); lost > 0 {
+			player.Broadcast(fmt.Sprintf("You lose %d experience for running away.", lost))
+		}
+	}
+
+	player.Look(w.AsWorldLike())
+	player.BroadcastState(w.AsWorldLike(), entityID)
+	return true
+}
+
+// shouldFlee reports whether a hit has left a wimpy player below their
+// threshold.
+func shouldFlee(w *ecs.World, entityID common.EntityID, health *components.Health) bool {
+	wimpy, err := ecs.GetTypedComponent[*components.Wimpy](w, entityID, "Wimpy")
+	if err != nil {
+		return false
+	}
+	threshold := wimpy.GetThreshold()
+
+	health.RLock()
+	defer health.RUnlock()
+	return threshold > 0 && health.Current > 0 && health.Current < threshold
+}
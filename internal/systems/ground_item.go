@@ -0,0 +1,48 @@
+package systems
+
+import (
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// GroundItemDecayTime is how long a dropped item lies on the ground before it
+// is cleaned up.
+const GroundItemDecayTime = 10 * time.Minute
+
+type GroundItemSystem struct {
+	DecayTime time.Duration
+}
+
+func NewGroundItemSystem() *GroundItemSystem {
+	return &GroundItemSystem{DecayTime: GroundItemDecayTime}
+}
+
+func (gs *GroundItemSystem) Update(w *ecs.World, deltaTime float64) {
+	areaEntities, err := w.FindEntitiesByComponentPredicate("Area", func(i interface{}) bool {
+		area, ok := i.(*components.Area)
+		if !ok {
+			return false
+		}
+		area.GroundItemsMutex.RLock()
+		defer area.GroundItemsMutex.RUnlock()
+		return len(area.GroundItems) > 0
+	})
+	if err != nil || len(areaEntities) == 0 {
+		return
+	}
+
+	for _, entity := range areaEntities {
+		area, err := ecs.GetTypedComponent[*components.Area](w, entity.ID, "Area")
+		if err != nil {
+			continue
+		}
+
+		for _, item := range area.RemoveDecayedGroundItems(gs.DecayTime, w.Now()) {
+			area.Broadcast(item.DisplayName() + " crumbles to dust.")
+			log.Debug().Msgf("Ground item %s in area %s has decayed and been removed", item.Name, entity.ID)
+		}
+	}
+}
@@ -44,6 +44,11 @@ func HandleMovement(w *ecs.World, movingEntity ecs.Entity) {
 		return
 	}
 
+	if components.IsOverburdened(w.AsWorldLike(), movingEntity.ID) {
+		movingPlayer.Broadcast("You are carrying too much to move. Drop something first.")
+		return
+	}
+
 	moving, err := ecs.GetTypedComponent[*components.Movement](w, movingEntity.ID, "Movement")
 	if err != nil {
 		log.Error().Msgf("Error getting moving component: %v", err)
@@ -0,0 +1,171 @@
+package systems
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"fmt"
+)
+
+// PvPRefusal says why attackerID can't fight targetID, another player, in
+// area, or returns "" if they can. Nobody fights in a safe area; elsewhere
+// duelists can fight each other, and anyone else needs both sides to have
+// turned PvP on.
+func PvPRefusal(w *ecs.World, area *components.Area, attackerID, targetID common.EntityID, targetName string) string {
+	if area != nil && area.HasFlag(components.SafeFlag) {
+		return "This is a safe place. Nobody can fight other players here."
+	}
+	if Dueling(w, attackerID, targetID) {
+		return ""
+	}
+	if !pvpEnabled(w, attackerID) {
+		return "You haven't turned PvP on. Type 'pvp on' to fight other players."
+	}
+	if !pvpEnabled(w, targetID) {
+		return fmt.Sprintf("%s hasn't turned PvP on.", targetName)
+	}
+	return ""
+}
+
+func pvpEnabled(w *ecs.World, entityID common.EntityID) bool {
+	pvp, err := ecs.GetTypedComponent[*components.PvP](w, entityID, "PvP")
+	return err == nil && pvp.IsEnabled()
+}
+
+// Dueling reports whether two players are dueling each other.
+func Dueling(w *ecs.World, a, b common.EntityID) bool {
+	duelA, errA := ecs.GetTypedComponent[*components.Duel](w, a, "Duel")
+	duelB, errB := ecs.GetTypedComponent[*components.Duel](w, b, "Duel")
+	return errA == nil && errB == nil && duelA.GetOpponent() == b && duelB.GetOpponent() == a
+}
+
+// ResolveDuelHit ends a duel once a hit from attackerID would have killed
+// targetID, leaving them on 1 health instead. It returns true if the duel
+// ended.
+func ResolveDuelHit(w *ecs.World, attackerID, targetID common.EntityID) bool {
+	if !Dueling(w, attackerID, targetID) {
+		return false
+	}
+	health, err := getHealthComponent(w, targetID)
+	if err != nil {
+		return false
+	}
+
+	health.Lock()
+	if health.Current > 0 {
+		health.Unlock()
+		return false
+	}
+	health.Current = 1
+	health.Unlock()
+
+	winner, _ := getPlayerComponent(w, attackerID)
+	loser, _ := getPlayerComponent(w, targetID)
+	endDuel(w, attackerID, targetID)
+	if winner == nil || loser == nil {
+		return true
+	}
+
+	loser.Broadcast(fmt.Sprintf("You are beaten! %s has won the duel.", winner.Name))
+	winner.Broadcast(fmt.Sprintf("You have won the duel against %s!", loser.Name))
+	if loser.Area != nil {
+		loser.Area.Broadcast(fmt.Sprintf("%s has won the duel against %s!", winner.Name, loser.Name), winner, loser)
+	}
+	loser.BroadcastState(w.AsWorldLike(), targetID)
+	return true
+}
+
+// DuelSystem ends duels that can't go on: those whose fighters have left
+// the arena, died or logged out, and those that have run past
+// components.DuelTimeout.
+type DuelSystem struct{}
+
+func NewDuelSystem() *DuelSystem {
+	return &DuelSystem{}
+}
+
+func (ds *DuelSystem) Update(w *ecs.World, deltaTime float64) {
+	duelists, err := w.FindEntitiesByComponentPredicate("Duel", func(i interface{}) bool {
+		d, ok := i.(*components.Duel)
+		return ok && d.GetOpponent() != ""
+	})
+	if err != nil {
+		return
+	}
+
+	for _, entity := range duelists {
+		duel, err := ecs.GetTypedComponent[*components.Duel](w, entity.ID, "Duel")
+		if err != nil {
+			continue
+		}
+		opponentID := duel.GetOpponent()
+		if opponentID == "" {
+			continue // Ended earlier in this sweep
+		}
+		player, err := getPlayerComponent(w, entity.ID)
+		if err != nil {
+			continue
+		}
+
+		if !Dueling(w, entity.ID, opponentID) {
+			endDuel(w, entity.ID, opponentID)
+			player.Broadcast("Your opponent has left. The duel is over.")
+			continue
+		}
+
+		if player.Area != duel.GetArena() || isGhost(w, entity.ID) {
+			forfeitDuel(w, entity.ID, player)
+			continue
+		}
+
+		if duel.TimedOut(w.Now()) {
+			endDuel(w, entity.ID, opponentID)
+			opponent, _ := getPlayerComponent(w, opponentID)
+			if opponent == nil {
+				continue
+			}
+			player.Broadcast(fmt.Sprintf("Your duel with %s has gone on too long and ends in a draw.", opponent.Name))
+			opponent.Broadcast(fmt.Sprintf("Your duel with %s has gone on too long and ends in a draw.", player.Name))
+		}
+	}
+}
+
+// forfeitDuel ends the duel of a player who ran from it, handing their
+// opponent the win.
+func forfeitDuel(w *ecs.World, entityID common.EntityID, player *components.Player) {
+	duel, err := ecs.GetTypedComponent[*components.Duel](w, entityID, "Duel")
+	if err != nil {
+		return
+	}
+	opponentID := duel.GetOpponent()
+	if opponentID == "" || !Dueling(w, entityID, opponentID) {
+		return
+	}
+
+	endDuel(w, opponentID, entityID)
+	player.Broadcast("You forfeit the duel.")
+	if opponent, _ := getPlayerComponent(w, opponentID); opponent != nil {
+		opponent.Broadcast(fmt.Sprintf("%s abandons the duel. You win!", player.Name))
+	}
+}
+
+// endDuel closes the duel between two players and stops them fighting.
+func endDuel(w *ecs.World, a, b common.EntityID) {
+	for _, pair := range [][2]common.EntityID{{a, b}, {b, a}} {
+		if duel, err := ecs.GetTypedComponent[*components.Duel](w, pair[0], "Duel"); err == nil {
+			duel.End()
+		}
+		if combat, err := getCombatComponent(w, pair[0]); err == nil {
+			combat.Lock()
+			combat.TargetQueue = removeTarget(combat.TargetQueue, pair[1])
+			if combat.TargetID == pair[1] {
+				combat.TargetID = ""
+				if len(combat.TargetQueue) > 0 {
+					combat.TargetID = combat.TargetQueue[0]
+					combat.TargetQueue = combat.TargetQueue[1:]
+				}
+			}
+			combat.Unlock()
+		}
+	}
+}
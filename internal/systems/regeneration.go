@@ -0,0 +1,98 @@
+package systems
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"time"
+)
+
+// RegenerationSystem restores health, mana and stamina every Tick. Resting
+// and sleeping speed it up, fighting slows it down.
+type RegenerationSystem struct {
+	Tick time.Duration
+
+	nextTick time.Time
+}
+
+func NewRegenerationSystem(tick time.Duration) *RegenerationSystem {
+	if tick <= 0 {
+		tick = components.DefaultRegenTick
+	}
+	return &RegenerationSystem{Tick: tick}
+}
+
+func (rs *RegenerationSystem) Update(w *ecs.World, deltaTime float64) {
+	now := w.Now()
+	if now.Before(rs.nextTick) {
+		return
+	}
+	rs.nextTick = now.Add(rs.Tick)
+
+	entities, err := w.FindEntitiesByComponentPredicate("Health", func(i interface{}) bool {
+		return true
+	})
+	if err != nil {
+		return
+	}
+
+	for _, entity := range entities {
+		health, err := getHealthComponent(w, entity.ID)
+		if err != nil {
+			continue
+		}
+
+		// The dead wait for the death flow, not regeneration
+		health.RLock()
+		dead := health.Current <= 0
+		health.RUnlock()
+		if dead {
+			continue
+		}
+
+		multiplier := regenMultiplier(w, entity.ID)
+		changed := false
+
+		health.Lock()
+		if health.Current < health.Max {
+			health.Heal(components.RegenAmount(health.Max, components.HealthRegenRate, multiplier))
+			changed = true
+		}
+		health.Unlock()
+
+		if mana, err := ecs.GetTypedComponent[*components.Mana](w, entity.ID, "Mana"); err == nil {
+			changed = regenPool(&mana.Pool, multiplier) || changed
+		}
+		if stamina, err := ecs.GetTypedComponent[*components.Stamina](w, entity.ID, "Stamina"); err == nil {
+			changed = regenPool(&stamina.Pool, multiplier) || changed
+		}
+
+		if changed {
+			if player, err := getPlayerComponent(w, entity.ID); err == nil {
+				player.BroadcastState(w.AsWorldLike(), entity.ID)
+			}
+		}
+	}
+}
+
+func regenPool(pool *components.Pool, multiplier float64) bool {
+	_, max := pool.Get()
+	return pool.Restore(components.RegenAmount(max, components.PoolRegenRate, multiplier)) > 0
+}
+
+// regenMultiplier combines an entity's posture with whether it is fighting.
+func regenMultiplier(w *ecs.World, entityID common.EntityID) float64 {
+	multiplier := 1.0
+	if posture, err := ecs.GetTypedComponent[*components.Posture](w, entityID, "Posture"); err == nil {
+		multiplier = posture.RegenMultiplier()
+	}
+	if combat, err := getCombatComponent(w, entityID); err == nil {
+		combat.RLock()
+		fighting := combat.TargetID != ""
+		combat.RUnlock()
+		if fighting {
+			multiplier *= components.CombatRegenMultiplier
+		}
+	}
+	return multiplier
+}
@@ -0,0 +1,34 @@
+package systems
+
+import (
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+
+	"github.com/rs/zerolog/log"
+)
+
+type ShopSystem struct{}
+
+func NewShopSystem() *ShopSystem {
+	return &ShopSystem{}
+}
+
+func (ss *ShopSystem) Update(w *ecs.World, deltaTime float64) {
+	shopEntities, err := w.FindEntitiesByComponentPredicate("Shop", func(i interface{}) bool {
+		return true
+	})
+	if err != nil || len(shopEntities) == 0 {
+		return
+	}
+
+	for _, entity := range shopEntities {
+		shop, err := ecs.GetTypedComponent[*components.Shop](w, entity.ID, "Shop")
+		if err != nil {
+			continue
+		}
+
+		if shop.Restock(w.Now()) {
+			log.Debug().Msgf("Shop %s restocked", entity.ID)
+		}
+	}
+}
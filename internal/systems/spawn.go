@@ -4,7 +4,6 @@ import (
 	"dmud/internal/common"
 	"dmud/internal/components"
 	"dmud/internal/ecs"
-	"math/rand"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -18,7 +17,6 @@ type SpawnSystem struct {
 
 func NewSpawnSystem() *SpawnSystem {
 	return &SpawnSystem{
-		lastCheck:    time.Now(),
 		wasNightTime: false,
 	}
 }
@@ -29,10 +27,14 @@ func (ss *SpawnSystem) SetDayCycle(dc *components.DayCycle) {
 
 func (ss *SpawnSystem) Update(w *ecs.World, deltaTime float64) {
 	// Only check spawns every 5 seconds
-	if time.Since(ss.lastCheck) < 5*time.Second {
+	now := w.Now()
+	if ss.lastCheck.IsZero() {
+		ss.lastCheck = now
+	}
+	if now.Sub(ss.lastCheck) < 5*time.Second {
 		return
 	}
-	ss.lastCheck = time.Now()
+	ss.lastCheck = now
 
 	// Check for night->day transition to despawn night creatures
 	isNightNow := ss.isNightTime()
@@ -139,7 +141,7 @@ func (ss *SpawnSystem) processSpawn(w *ecs.World, spawnEntity ecs.Entity) {
 		// Check if we need to spawn more
 		if activeCount < config.MinCount {
 			// Check spawn chance
-			if rand.Float64() <= config.Chance {
+			if w.Rand().Float64() <= config.Chance {
 				ss.spawnNPC(w, area, config, spawn)
 			}
 		}
@@ -183,7 +185,22 @@ func (ss *SpawnSystem) spawnNPC(w *ecs.World, area *components.Area, config comp
 		return
 	}
 
-	// Create NPC entity
+	npcEntity := CreateNPC(w, area, template)
+
+	// Track the spawn - append to the list
+	if spawn.ActiveSpawns[template.ID] == nil {
+		spawn.ActiveSpawns[template.ID] = make([]common.EntityID, 0)
+	}
+	spawn.ActiveSpawns[template.ID] = append(spawn.ActiveSpawns[template.ID], npcEntity.ID)
+
+	// Announce spawn to area (with newline to avoid interrupting typing)
+	area.Broadcast(template.Name + " arrives.")
+
+	log.Info().Msgf("Spawned NPC: %s in area %s", template.Name, spawn.AreaID)
+}
+
+// CreateNPC adds a fresh NPC entity built from template to the world in area.
+func CreateNPC(w *ecs.World, area *components.Area, template components.NPCTemplate) ecs.Entity {
 	npcEntity := ecs.NewEntity()
 	w.AddEntity(npcEntity)
 
@@ -195,8 +212,8 @@ func (ss *SpawnSystem) spawnNPC(w *ecs.World, area *components.Area, config comp
 		TemplateID:   template.ID,
 		Behavior:     template.Behavior,
 		Dialogue:     template.Dialogue,
-		LastAction:   time.Now(),
-		LastMovement: time.Now(),
+		LastAction:   w.Now(),
+		LastMovement: w.Now(),
 	}
 	w.AddComponent(&npcEntity, npc)
 
@@ -209,7 +226,7 @@ func (ss *SpawnSystem) spawnNPC(w *ecs.World, area *components.Area, config comp
 	w.AddComponent(&npcEntity, health)
 
 	// Add Inventory component with generated loot
-	inventory := components.GenerateLoot(template.ID)
+	inventory := components.GenerateLoot(w.Rand(), template.ID)
 	w.AddComponent(&npcEntity, inventory)
 
 	// Add Combat component for NPCs that can fight on their own
@@ -221,14 +238,10 @@ func (ss *SpawnSystem) spawnNPC(w *ecs.World, area *components.Area, config comp
 		w.AddComponent(&npcEntity, combat)
 	}
 
-	// Track the spawn - append to the list
-	if spawn.ActiveSpawns[template.ID] == nil {
-		spawn.ActiveSpawns[template.ID] = make([]common.EntityID, 0)
+	// Add Shop component for NPCs that trade
+	if template.Shop != nil {
+		w.AddComponent(&npcEntity, components.NewShop(template.Shop, w.Now()))
 	}
-	spawn.ActiveSpawns[template.ID] = append(spawn.ActiveSpawns[template.ID], npcEntity.ID)
 
-	// Announce spawn to area (with newline to avoid interrupting typing)
-	area.Broadcast(template.Name + " arrives.")
-
-	log.Info().Msgf("Spawned NPC: %s in area %s", template.Name, spawn.AreaID)
+	return npcEntity
 }
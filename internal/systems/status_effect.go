@@ -28,7 +28,7 @@ func (ses *StatusEffectSystem) Update(w *ecs.World, deltaTime float64) {
 			continue
 		}
 
-		removed := statusEffects.RemoveExpired()
+		removed := statusEffects.RemoveExpired(w.Now())
 
 		if len(removed) == 0 {
 			continue
@@ -46,6 +46,14 @@ func (ses *StatusEffectSystem) Update(w *ecs.World, deltaTime float64) {
 
 		hasHPChange := false
 		for _, effect := range removed {
+			if effect.Type == components.StatusEffectStunned {
+				player.Broadcast("You are no longer stunned.")
+				continue
+			}
+			if effect.Type == components.StatusEffectWeakened {
+				player.Broadcast("You feel your strength return.")
+				continue
+			}
 			if effect.HPBonus > 0 {
 				health.Lock()
 				health.Current -= effect.HPBonus
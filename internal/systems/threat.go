@@ -0,0 +1,127 @@
+package systems
+
+import (
+	"dmud/internal/common"
+	"dmud/internal/components"
+	"dmud/internal/ecs"
+	"fmt"
+	"time"
+)
+
+// AddThreat builds up sourceID's threat on an NPC, giving the NPC a threat
+// table if this is the first time anyone has crossed it. Players have no
+// threat table; they pick their own fights.
+func AddThreat(w *ecs.World, npcID, sourceID common.EntityID, amount int) {
+	if npc, _ := getNPCComponent(w, npcID); npc == nil {
+		return
+	}
+	threat := getThreatComponent(w, npcID)
+	if threat == nil {
+		return
+	}
+	if amount < 0 {
+		amount = 0
+	}
+	threat.Add(sourceID, amount)
+}
+
+// AddHealingThreat draws threat onto a healer from every NPC fighting the
+// character they healed.
+func AddHealingThreat(w *ecs.World, healerID, healedID common.EntityID, amount int) {
+	amount = int(float64(amount) * components.HealingThreatMultiplier)
+
+	entities, _ := w.FindEntitiesByComponentPredicate("Threat", func(i interface{}) bool {
+		return i.(*components.Threat).Has(healedID)
+	})
+	for _, entity := range entities {
+		AddThreat(w, entity.ID, healerID, amount)
+	}
+}
+
+// Taunt forces an NPC to turn on taunterID for duration.
+func Taunt(w *ecs.World, npcID, taunterID common.EntityID, duration time.Duration) {
+	if npc, _ := getNPCComponent(w, npcID); npc == nil {
+		return
+	}
+	if threat := getThreatComponent(w, npcID); threat != nil {
+		threat.Taunt(taunterID, duration, w.Now())
+	}
+}
+
+// getThreatComponent returns an NPC's threat table, creating it if needed.
+func getThreatComponent(w *ecs.World, npcID common.EntityID) *components.Threat {
+	if threat, err := ecs.GetTypedComponent[*components.Threat](w, npcID, "Threat"); err == nil {
+		return threat
+	}
+	entity, err := w.FindEntity(npcID)
+	if err != nil {
+		return nil
+	}
+	threat := components.NewThreat()
+	w.AddComponent(&entity, threat)
+	return threat
+}
+
+// retargetByThreat points every NPC with a threat table at whoever it hates
+// most among those still standing in front of it, picking the fight back up
+// if its last target died or got away.
+func retargetByThreat(w *ecs.World) {
+	entities, _ := w.FindEntitiesByComponentPredicate("Threat", func(i interface{}) bool {
+		return true
+	})
+
+	for _, entity := range entities {
+		npc, _ := getNPCComponent(w, entity.ID)
+		threat, _ := ecs.GetTypedComponent[*components.Threat](w, entity.ID, "Threat")
+		if npc == nil || threat == nil {
+			continue
+		}
+
+		combat, _ := getCombatComponent(w, entity.ID)
+		current := common.EntityID("")
+		if combat != nil {
+			current = combat.TargetID
+		}
+
+		target := threat.Target(current, func(id common.EntityID) bool {
+			return canFight(w, npc.Area, id)
+		}, w.Now())
+		if target == "" || target == current {
+			continue
+		}
+
+		if combat == nil {
+			minDamage, maxDamage := combatDamageRange(w, entity.ID, nil, npc)
+			e := entity
+			w.AddComponent(&e, &components.Combat{TargetID: target, MinDamage: minDamage, MaxDamage: maxDamage})
+		} else {
+			combat.Lock()
+			combat.TargetQueue = removeTarget(combat.TargetQueue, target)
+			combat.TargetID = target
+			combat.Unlock()
+		}
+
+		if player, _ := getPlayerComponent(w, target); player != nil && npc.Area != nil {
+			player.Broadcast(fmt.Sprintf("%s turns to attack you!", npc.Name))
+			npc.Area.Broadcast(fmt.Sprintf("%s turns to attack %s!", npc.Name, player.Name), player)
+		}
+	}
+}
+
+// canFight reports whether id is alive, not a ghost and standing in area.
+func canFight(w *ecs.World, area *components.Area, id common.EntityID) bool {
+	var targetArea *components.Area
+	if player, _ := getPlayerComponent(w, id); player != nil {
+		if isGhost(w, id) {
+			return false
+		}
+		targetArea = player.Area
+	} else if npc, _ := getNPCComponent(w, id); npc != nil {
+		targetArea = npc.Area
+	} else {
+		return false
+	}
+
+	health, err := getHealthComponent(w, id)
+	return err == nil && !isTargetDead(health) && targetArea == area
+}
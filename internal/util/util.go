@@ -17,25 +17,12 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-var lastTime time.Time
-
 func init() {
 	rand.Seed(time.Now().UnixNano())
 }
 
 type EntityID string
 
-func CalculateDeltaTime() float64 {
-	if lastTime.IsZero() {
-		lastTime = time.Now()
-		return 0
-	}
-	currentTime := time.Now()
-	deltaTime := currentTime.Sub(lastTime).Seconds()
-	lastTime = currentTime
-	return deltaTime
-}
-
 func ContainsClient(clients []common.Client, client common.Client) bool {
 	for _, c := range clients {
 		if c == client {
@@ -71,6 +58,11 @@ func HashAndSalt(pwd string) string {
 	return string(hash)
 }
 
+// ComparePassword reports whether pwd is the password hash was made from.
+func ComparePassword(hash, pwd string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(pwd)) == nil
+}
+
 func IsAlphaNumeric(str string) bool {
 	for _, c := range str {
 		if !unicode.IsLetter(c) && !unicode.IsNumber(c) {